@@ -8,6 +8,7 @@ package tailscale // import "tailscale.com/client/tailscale/v2"
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -51,6 +53,15 @@ type Client struct {
 	// If not specified, a new [http.Client] with a Timeout of 1 minute will be used.
 	HTTP *http.Client
 
+	// DisableLogDecompression stops the client from decompressing log payloads that the API returns
+	// compressed, so that they are passed on as-is by [LoggingResource.GetNetworkFlowLogsRaw].
+	// Methods that decode logs fail on compressed payloads when this is set.
+	DisableLogDecompression bool
+
+	// RetryPolicy, if not nil, is used to retry requests that can be safely resumed after a
+	// failure, such as streaming network flow logs.
+	RetryPolicy *RetryPolicy
+
 	initOnce sync.Once
 
 	// Specific resources
@@ -62,6 +73,7 @@ type Client struct {
 	logging         *LoggingResource
 	policyFile      *PolicyFileResource
 	tailnetSettings *TailnetSettingsResource
+	userInvites     *UserInvitesResource
 	users           *UsersResource
 	vipServices     *VIPServicesResource
 	webhooks        *WebhooksResource
@@ -122,6 +134,7 @@ func (c *Client) init() {
 		c.logging = &LoggingResource{c}
 		c.policyFile = &PolicyFileResource{c}
 		c.tailnetSettings = &TailnetSettingsResource{c}
+		c.userInvites = &UserInvitesResource{c}
 		c.users = &UsersResource{c}
 		c.vipServices = &VIPServicesResource{c}
 		c.webhooks = &WebhooksResource{c}
@@ -176,6 +189,12 @@ func (c *Client) TailnetSettings() *TailnetSettingsResource {
 	return c.tailnetSettings
 }
 
+// UserInvites provides access to https://tailscale.com/api#tag/userinvites.
+func (c *Client) UserInvites() *UserInvitesResource {
+	c.init()
+	return c.userInvites
+}
+
 // Users provides access to https://tailscale.com/api#tag/users.
 func (c *Client) Users() *UsersResource {
 	c.init()
@@ -373,6 +392,107 @@ func (c *Client) doWithResponseHeaders(req *http.Request, out any) (http.Header,
 	return res.Header, nil
 }
 
+// doStream performs req and returns the undecoded body of a successful response, which the
+// caller must close. Unsuccessful responses are returned as an [APIError] where possible.
+func (c *Client) doStream(req *http.Request) (io.ReadCloser, error) {
+	c.init()
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		// Still return an APIError, so that callers can tell the status code apart from a
+		// connection failure.
+		apiErr = APIError{Message: cmp.Or(strings.TrimSpace(string(body)), http.StatusText(resp.StatusCode))}
+	}
+	apiErr.Status = resp.StatusCode
+	return nil, withRetryAfter(apiErr, resp.Header)
+}
+
+// errStopStream is returned by the handle function passed to [streamArray] to stop
+// streaming early without an error.
+var errStopStream = errors.New("stop streaming")
+
+// streamArray performs req, whose JSON response is an object with an array in the named field,
+// and decodes that array one element at a time, calling handle for each element. Other fields
+// of the response are skipped. This keeps memory use flat for very large responses.
+func streamArray[T any](c *Client, req *http.Request, field string, handle func(T) error) error {
+	body, err := c.doStream(req)
+	if err != nil {
+		return err
+	}
+	return decodeArray(req.Context(), body, field, handle)
+}
+
+// decodeArray is like [streamArray], but decodes a response body that has already been
+// obtained. It closes body.
+func decodeArray[T any](ctx context.Context, body io.ReadCloser, field string, handle func(T) error) error {
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	if err := checkDelim(decoder, '{', "opening brace"); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to read field name: %w", err)
+		}
+		if name, ok := token.(string); !ok || name != field {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to skip field %v: %w", token, err)
+			}
+			continue
+		}
+
+		if err := checkDelim(decoder, '[', field+" array start"); err != nil {
+			return err
+		}
+		for decoder.More() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var v T
+			if err := decoder.Decode(&v); err != nil {
+				return fmt.Errorf("failed to decode %s entry: %w", field, err)
+			}
+			if err := handle(v); err != nil {
+				if errors.Is(err, errStopStream) {
+					return nil
+				}
+				return err
+			}
+		}
+		if err := checkDelim(decoder, ']', field+" array end"); err != nil {
+			return err
+		}
+	}
+	return checkDelim(decoder, '}', "closing brace")
+}
+
+// checkDelim reads and verifies the next JSON delimiter from the decoder
+func checkDelim(dec *json.Decoder, want json.Delim, description string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", description, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %c for %s, got %v", want, description, token)
+	}
+	return nil
+}
+
 func (err APIError) Error() string {
 	return fmt.Sprintf("%s (%v)", err.Message, err.Status)
 }
@@ -387,6 +507,17 @@ func IsNotFound(err error) bool {
 	return false
 }
 
+// IsPreconditionFailed returns true if the provided error implementation is an APIError with a status of 412.
+// This is returned when an update is made with an ETag that no longer matches the current resource.
+func IsPreconditionFailed(err error) bool {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == http.StatusPreconditionFailed
+	}
+
+	return false
+}
+
 // ErrorData returns the contents of the [APIError].Data field from the provided error if it is of type [APIError].
 // Returns a nil slice if the given error is not of type [APIError].
 func ErrorData(err error) []APIErrorData {
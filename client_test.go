@@ -80,3 +80,11 @@ func TestIsNotFound(t *testing.T) {
 	e := APIError{Status: http.StatusNotFound}
 	assert.True(t, IsNotFound(e))
 }
+
+func TestIsPreconditionFailed(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsPreconditionFailed(APIError{Status: http.StatusPreconditionFailed}))
+	assert.False(t, IsPreconditionFailed(APIError{Status: http.StatusNotFound}))
+	assert.False(t, IsPreconditionFailed(io.EOF))
+}
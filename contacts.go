@@ -5,7 +5,11 @@ package tailscale
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/mail"
+	"slices"
 )
 
 // ContactsResource provides access to https://tailscale.com/api#tag/contacts.
@@ -22,6 +26,21 @@ const (
 // ContactType defines the type of contact.
 type ContactType string
 
+// AllContactTypes returns every [ContactType] known to this client.
+func AllContactTypes() []ContactType {
+	return []ContactType{ContactAccount, ContactSupport, ContactSecurity}
+}
+
+// IsValid reports whether t is a [ContactType] known to this client.
+func (t ContactType) IsValid() bool {
+	return slices.Contains(AllContactTypes(), t)
+}
+
+// ParseContactType parses s as a [ContactType], ignoring case and surrounding whitespace.
+func ParseContactType(s string) (ContactType, error) {
+	return parseEnum("contact type", s, AllContactTypes())
+}
+
 // Contacts type defines the object returned when retrieving contacts.
 type Contacts struct {
 	Account  Contact `json:"account"`
@@ -43,6 +62,38 @@ type UpdateContactRequest struct {
 	Email *string `json:"email,omitempty"`
 }
 
+// Validate checks that Email, if set, is a plain email address such as "admin@example.com".
+func (r *UpdateContactRequest) Validate() error {
+	if r.Email == nil {
+		return nil
+	}
+	addr, err := mail.ParseAddress(*r.Email)
+	if err != nil || addr.Name != "" || addr.Address != *r.Email {
+		return fmt.Errorf("contact email %q is not a valid email address", *r.Email)
+	}
+	return nil
+}
+
+// ContactVerificationPendingError is returned by [ContactsResource.Update] when the API rejects an
+// update because a previous change to the contact has not been verified yet. It wraps the
+// [APIError] returned by the API.
+type ContactVerificationPendingError struct {
+	// ContactType is the type of contact that could not be updated.
+	ContactType ContactType
+	// Reason is the API's explanation of why the contact could not be updated.
+	Reason string
+
+	err APIError
+}
+
+func (e *ContactVerificationPendingError) Error() string {
+	return fmt.Sprintf("cannot update %s contact while verification is pending: %s", e.ContactType, e.Reason)
+}
+
+func (e *ContactVerificationPendingError) Unwrap() error {
+	return e.err
+}
+
 // Get retieves the [Contacts] for the tailnet.
 func (cr *ContactsResource) Get(ctx context.Context) (*Contacts, error) {
 	req, err := cr.buildRequest(ctx, http.MethodGet, cr.buildTailnetURL("contacts"))
@@ -53,13 +104,64 @@ func (cr *ContactsResource) Get(ctx context.Context) (*Contacts, error) {
 	return body[Contacts](cr, req)
 }
 
+// GetAccount retrieves the tailnet's [ContactAccount] contact.
+func (cr *ContactsResource) GetAccount(ctx context.Context) (*Contact, error) {
+	return cr.getContact(ctx, ContactAccount)
+}
+
+// GetSupport retrieves the tailnet's [ContactSupport] contact.
+func (cr *ContactsResource) GetSupport(ctx context.Context) (*Contact, error) {
+	return cr.getContact(ctx, ContactSupport)
+}
+
+// GetSecurity retrieves the tailnet's [ContactSecurity] contact.
+func (cr *ContactsResource) GetSecurity(ctx context.Context) (*Contact, error) {
+	return cr.getContact(ctx, ContactSecurity)
+}
+
+// getContact retrieves a single contact. The API only returns contacts all together, so this
+// fetches all of them.
+func (cr *ContactsResource) getContact(ctx context.Context, contactType ContactType) (*Contact, error) {
+	contacts, err := cr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch contactType {
+	case ContactAccount:
+		return &contacts.Account, nil
+	case ContactSupport:
+		return &contacts.Support, nil
+	default:
+		return &contacts.Security, nil
+	}
+}
+
 // Update updates the email for the specified [ContactType] within the tailnet.
 // If the email address changes, the system will send a verification email to confirm the change.
+// Unknown contact types and invalid email addresses are rejected without making a request. If the
+// API rejects the update while an earlier change to the contact is still awaiting verification, a
+// [*ContactVerificationPendingError] is returned; this is determined by fetching the contact.
 func (cr *ContactsResource) Update(ctx context.Context, contactType ContactType, contact UpdateContactRequest) error {
+	if !contactType.IsValid() {
+		return fmt.Errorf("unknown contact type %q", contactType)
+	}
+	if err := contact.Validate(); err != nil {
+		return err
+	}
 	req, err := cr.buildRequest(ctx, http.MethodPatch, cr.buildTailnetURL("contacts", contactType), requestBody(contact))
 	if err != nil {
 		return err
 	}
 
-	return cr.do(req, nil)
+	err = cr.do(req, nil)
+	// The API doesn't use a distinct status for pending verifications, so a rejected update is
+	// checked against the contact's verification state instead.
+	var apiErr APIError
+	if errors.As(err, &apiErr) && (apiErr.Status == http.StatusBadRequest || apiErr.Status == http.StatusConflict) {
+		if current, getErr := cr.getContact(ctx, contactType); getErr == nil && current.NeedsVerification {
+			return &ContactVerificationPendingError{ContactType: contactType, Reason: apiErr.Message, err: apiErr}
+		}
+	}
+	return err
 }
@@ -6,6 +6,7 @@ package tailscale
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -63,3 +64,86 @@ func TestClient_UpdateContact(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, updateRequest, receivedRequest)
 }
+
+func TestClient_ContactsTypedGetters(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = &Contacts{
+		Account:  Contact{Email: "account@example.com"},
+		Support:  Contact{Email: "support@example.com"},
+		Security: Contact{Email: "security@example.com", NeedsVerification: true},
+	}
+
+	account, err := client.Contacts().GetAccount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "account@example.com", account.Email)
+	assert.Equal(t, "/api/v2/tailnet/example.com/contacts", server.Path)
+
+	support, err := client.Contacts().GetSupport(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "support@example.com", support.Email)
+
+	security, err := client.Contacts().GetSecurity(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &Contact{Email: "security@example.com", NeedsVerification: true}, security)
+}
+
+func TestUpdateContactRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&UpdateContactRequest{}).Validate())
+	assert.NoError(t, (&UpdateContactRequest{Email: PointerTo("admin@example.com")}).Validate())
+	for _, email := range []string{"", "admin", "admin@", "Admin <admin@example.com>", " admin@example.com"} {
+		assert.Error(t, (&UpdateContactRequest{Email: PointerTo(email)}).Validate(), email)
+	}
+}
+
+func TestClient_UpdateContactInvalid(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Contacts().Update(context.Background(), ContactType("billing"), UpdateContactRequest{Email: PointerTo("a@example.com")})
+	assert.EqualError(t, err, `unknown contact type "billing"`)
+	err = client.Contacts().Update(context.Background(), ContactAccount, UpdateContactRequest{Email: PointerTo("not an email")})
+	assert.Error(t, err)
+	assert.Empty(t, server.Method)
+}
+
+func TestClient_UpdateContactVerificationPending(t *testing.T) {
+	t.Parallel()
+
+	needsVerification := true
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tailnet/example.com/contacts":
+			contacts := Contacts{Security: Contact{Email: "old@example.com", NeedsVerification: needsVerification}}
+			assert.NoError(t, json.NewEncoder(w).Encode(contacts))
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v2/tailnet/example.com/contacts/security":
+			w.WriteHeader(http.StatusBadRequest)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "cannot change email"}))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	err := client.Contacts().Update(context.Background(), ContactSecurity, UpdateContactRequest{Email: PointerTo("new@example.com")})
+	var pending *ContactVerificationPendingError
+	if assert.True(t, errors.As(err, &pending)) {
+		assert.Equal(t, ContactSecurity, pending.ContactType)
+		assert.Equal(t, "cannot change email", pending.Reason)
+	}
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+
+	// Failures for contacts that aren't awaiting verification are returned unchanged.
+	needsVerification = false
+	err = client.Contacts().Update(context.Background(), ContactSecurity, UpdateContactRequest{Email: PointerTo("new@example.com")})
+	assert.False(t, errors.As(err, &pending))
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "cannot change email", apiErr.Message)
+}
@@ -6,7 +6,10 @@ package tailscale
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -106,6 +109,23 @@ type Device struct {
 	Distro             *Distro             `json:"distro"`
 }
 
+// IsOnline reports whether the device is currently connected to the control plane.
+func (d *Device) IsOnline() bool {
+	return d.ConnectedToControl
+}
+
+// SeenWithin reports whether the device is online, or was last seen no more than
+// the given duration ago. A nil or zero LastSeen is treated as never seen.
+func (d *Device) SeenWithin(duration time.Duration) bool {
+	if d.IsOnline() {
+		return true
+	}
+	if d.LastSeen == nil || d.LastSeen.IsZero() {
+		return false
+	}
+	return time.Since(d.LastSeen.Time) <= duration
+}
+
 type DevicePostureAttributes struct {
 	Attributes map[string]any  `json:"attributes"`
 	Expiries   map[string]Time `json:"expiries"`
@@ -248,6 +268,22 @@ func (dr *DevicesResource) ListWithAllFields(ctx context.Context) ([]Device, err
 //
 // To include all fields, pass the [WithFields] option with [IncludeFieldsAll].
 func (dr *DevicesResource) List(ctx context.Context, opts ...ListDevicesOptions) ([]Device, error) {
+	req, err := dr.buildListRequest(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string][]Device)
+	err = dr.do(req, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m["devices"], nil
+}
+
+// buildListRequest builds the request used by [DevicesResource.List] for the given options.
+func (dr *DevicesResource) buildListRequest(ctx context.Context, opts ...ListDevicesOptions) (*http.Request, error) {
 	req, err := dr.buildRequest(ctx, http.MethodGet, dr.buildTailnetURL("devices"))
 	if err != nil {
 		return nil, err
@@ -273,14 +309,7 @@ func (dr *DevicesResource) List(ctx context.Context, opts ...ListDevicesOptions)
 	}
 
 	req.URL.RawQuery = q.Encode()
-
-	m := make(map[string][]Device)
-	err = dr.do(req, &m)
-	if err != nil {
-		return nil, err
-	}
-
-	return m["devices"], nil
+	return req, nil
 }
 
 // SetAuthorized marks the specified device as authorized or not.
@@ -309,10 +338,17 @@ func (dr *DevicesResource) Delete(ctx context.Context, deviceID string) error {
 	return dr.do(req, nil)
 }
 
-// SetName updates the name of the device identified by deviceID.
+// SetName updates the name of the device identified by deviceID. Machine names are case-insensitive,
+// so the name is converted to lowercase and checked with [ValidateDeviceName] before the request is
+// sent.
 //
 // Using the device `NodeID` is preferred, but its numeric `ID` value can also be used.
 func (dr *DevicesResource) SetName(ctx context.Context, deviceID, name string) error {
+	name = strings.ToLower(name)
+	if err := ValidateDeviceName(name); err != nil {
+		return err
+	}
+
 	req, err := dr.buildRequest(ctx, http.MethodPost, dr.buildURL("device", deviceID, "name"), requestBody(map[string]string{
 		"name": name,
 	}))
@@ -323,6 +359,96 @@ func (dr *DevicesResource) SetName(ctx context.Context, deviceID, name string) e
 	return dr.do(req, nil)
 }
 
+// maxDeviceNameLength is the maximum length of a machine name, which must fit in a single DNS label.
+const maxDeviceNameLength = 63
+
+// ErrDeviceNameInUse is returned by [DevicesResource.SetUniqueName] when the requested
+// name is already used by another device in the tailnet.
+var ErrDeviceNameInUse = errors.New("device name is already in use")
+
+// ValidateDeviceName checks that name is usable as a machine name: at most 63 characters
+// consisting of lowercase letters, digits and hyphens, not starting or ending with a hyphen.
+// An empty name is valid, and tells the API to derive the name from the device's hostname.
+func ValidateDeviceName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if len(name) > maxDeviceNameLength {
+		return fmt.Errorf("device name %q is longer than %d characters", name, maxDeviceNameLength)
+	}
+	if name[0] == '-' || name[len(name)-1] == '-' {
+		return fmt.Errorf("device name %q must not start or end with a hyphen", name)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Errorf("device name %q contains invalid character %q", name, r)
+		}
+	}
+	return nil
+}
+
+// SetNameOptions configures [DevicesResource.SetUniqueName].
+type SetNameOptions func(*setNameOptions)
+
+type setNameOptions struct {
+	autoSuffix bool
+}
+
+// WithAutoSuffix makes [DevicesResource.SetUniqueName] append a numeric suffix
+// (e.g. "-1", "-2") to the requested name when it is already in use, instead of failing.
+func WithAutoSuffix() SetNameOptions {
+	return func(o *setNameOptions) {
+		o.autoSuffix = true
+	}
+}
+
+// SetUniqueName converts name to lowercase and validates it, checks that no other device in the
+// tailnet already uses it and then sets it as the name of the device identified by deviceID. If the
+// name is in use, an error wrapping [ErrDeviceNameInUse] is returned unless [WithAutoSuffix] is
+// given. Returns the name that was set.
+//
+// Using the device `NodeID` is preferred, but its numeric `ID` value can also be used.
+func (dr *DevicesResource) SetUniqueName(ctx context.Context, deviceID, name string, opts ...SetNameOptions) (string, error) {
+	name = strings.ToLower(name)
+	if err := ValidateDeviceName(name); err != nil {
+		return "", err
+	}
+
+	sno := setNameOptions{}
+	for _, apply := range opts {
+		apply(&sno)
+	}
+
+	devices, err := dr.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool)
+	for _, d := range devices {
+		if d.ID == deviceID || d.NodeID == deviceID {
+			continue
+		}
+		machineName, _, _ := strings.Cut(d.Name, ".")
+		taken[strings.ToLower(machineName)] = true
+	}
+
+	unique := name
+	for i := 1; name != "" && taken[unique]; i++ {
+		if !sno.autoSuffix {
+			return "", fmt.Errorf("%w: %q", ErrDeviceNameInUse, name)
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		base := strings.TrimRight(name[:min(len(name), maxDeviceNameLength-len(suffix))], "-")
+		unique = base + suffix
+	}
+
+	if err := dr.SetName(ctx, deviceID, unique); err != nil {
+		return "", err
+	}
+	return unique, nil
+}
+
 // SetTags updates the tags of the device identified by deviceID.
 //
 // Using the device `NodeID` is preferred, but its numeric `ID` value can also be used.
@@ -397,3 +523,50 @@ func (dr *DevicesResource) SubnetRoutes(ctx context.Context, deviceID string) (*
 
 	return body[DeviceRoutes](dr, req)
 }
+
+// TagOwnershipError is returned by [DevicesResource.CheckTagOwnership] when some of the
+// proposed tags cannot be applied by the given owner.
+type TagOwnershipError struct {
+	// Owner is the identity that the tags were checked against.
+	Owner string
+	// Undefined lists tags that do not appear in the policy file's TagOwners.
+	Undefined []string
+	// Unowned lists tags that are defined, but not owned by Owner.
+	Unowned []string
+}
+
+func (e *TagOwnershipError) Error() string {
+	var parts []string
+	if len(e.Undefined) > 0 {
+		parts = append(parts, fmt.Sprintf("tags not defined in tagOwners: %s", strings.Join(e.Undefined, ", ")))
+	}
+	if len(e.Unowned) > 0 {
+		parts = append(parts, fmt.Sprintf("tags not owned by %s: %s", e.Owner, strings.Join(e.Unowned, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CheckTagOwnership fetches the tailnet policy file and verifies that owner can apply every tag
+// in tags, returning a [*TagOwnershipError] if it cannot. owner is a user login name, a group
+// or a tag, as it would appear in the policy file's TagOwners section.
+//
+// This is intended as a pre-flight check before calling [DevicesResource.SetTags].
+func (dr *DevicesResource) CheckTagOwnership(ctx context.Context, owner string, tags []string) error {
+	acl, err := dr.PolicyFile().Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	tagErr := &TagOwnershipError{Owner: owner}
+	for _, tag := range tags {
+		if _, ok := acl.TagOwners[tag]; !ok {
+			tagErr.Undefined = append(tagErr.Undefined, tag)
+		} else if !acl.OwnsTag(owner, tag) {
+			tagErr.Unowned = append(tagErr.Unowned, tag)
+		}
+	}
+	if len(tagErr.Undefined) > 0 || len(tagErr.Unowned) > 0 {
+		return tagErr
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DeviceInventory is an aggregated breakdown of the devices in a tailnet, suitable for
+// patch-compliance reporting.
+type DeviceInventory struct {
+	// Total is the total number of devices.
+	Total int
+	// ByOS counts devices by their operating system.
+	ByOS map[string]int
+	// ByClientVersion counts devices by their Tailscale client version, without any
+	// build suffix (e.g. "1.22.2" rather than "1.22.2-t60b671955-gecc5d9846").
+	ByClientVersion map[string]int
+	// UpdateAvailable counts the devices for which a client update is available.
+	UpdateAvailable int
+	// Outdated lists the devices whose client version is older than the minimum version
+	// that the inventory was built with. Devices with an unknown client version are not included.
+	Outdated []Device
+}
+
+// NewDeviceInventory builds a [DeviceInventory] from the given devices. If minVersion
+// is not empty, devices running a client older than minVersion are listed in Outdated.
+func NewDeviceInventory(devices []Device, minVersion string) *DeviceInventory {
+	inv := &DeviceInventory{
+		Total:           len(devices),
+		ByOS:            make(map[string]int),
+		ByClientVersion: make(map[string]int),
+	}
+	for _, d := range devices {
+		inv.ByOS[d.OS]++
+		version := shortClientVersion(d.ClientVersion)
+		inv.ByClientVersion[version]++
+		if d.UpdateAvailable {
+			inv.UpdateAvailable++
+		}
+		if minVersion != "" && version != "" && compareClientVersions(version, minVersion) < 0 {
+			inv.Outdated = append(inv.Outdated, d)
+		}
+	}
+	return inv
+}
+
+// Inventory lists every [Device] in the tailnet and returns a [DeviceInventory] of them.
+// See [NewDeviceInventory] for the meaning of minVersion.
+func (dr *DevicesResource) Inventory(ctx context.Context, minVersion string) (*DeviceInventory, error) {
+	devices, err := dr.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDeviceInventory(devices, minVersion), nil
+}
+
+// shortClientVersion strips any build suffix from a Tailscale client version.
+func shortClientVersion(version string) string {
+	short, _, _ := strings.Cut(version, "-")
+	return short
+}
+
+// compareClientVersions compares two dotted version strings numerically, returning
+// -1, 0 or +1. Build suffixes are ignored and missing components are treated as 0.
+func compareClientVersions(a, b string) int {
+	as := strings.Split(shortClientVersion(a), ".")
+	bs := strings.Split(shortClientVersion(b), ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var av, bv int
+		if i < len(as) {
+			av, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			bv, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	}
+	return 0
+}
+
+// DeviceGroups maps a grouping key, such as a user or a tag, to the devices in that group.
+type DeviceGroups map[string][]Device
+
+// All returns an iterator over the groups, ordered by key.
+func (g DeviceGroups) All() iter.Seq2[string, []Device] {
+	return func(yield func(string, []Device) bool) {
+		for _, key := range slices.Sorted(maps.Keys(g)) {
+			if !yield(key, g[key]) {
+				return
+			}
+		}
+	}
+}
+
+// GroupDevices groups devices by the keys returned by keys. A device is added to
+// every group whose key is returned for it, and to no group if keys returns none.
+func GroupDevices(devices []Device, keys func(Device) []string) DeviceGroups {
+	groups := make(DeviceGroups)
+	for _, d := range devices {
+		for _, key := range keys(d) {
+			groups[key] = append(groups[key], d)
+		}
+	}
+	return groups
+}
+
+// GroupDevicesByUser groups devices by the login name of the user that owns them.
+func GroupDevicesByUser(devices []Device) DeviceGroups {
+	return GroupDevices(devices, func(d Device) []string { return []string{d.User} })
+}
+
+// GroupDevicesByTag groups devices by each of their tags. Tagged devices appear once per tag;
+// untagged devices are grouped under the empty key.
+func GroupDevicesByTag(devices []Device) DeviceGroups {
+	return GroupDevices(devices, func(d Device) []string {
+		if len(d.Tags) == 0 {
+			return []string{""}
+		}
+		return d.Tags
+	})
+}
+
+// GroupDevicesByOS groups devices by their operating system.
+func GroupDevicesByOS(devices []Device) DeviceGroups {
+	return GroupDevices(devices, func(d Device) []string { return []string{d.OS} })
+}
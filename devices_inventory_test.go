@@ -0,0 +1,88 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeviceInventory(t *testing.T) {
+	t.Parallel()
+
+	devices := []Device{
+		{NodeID: "n1", OS: "linux", ClientVersion: "1.22.2-t60b671955-gecc5d9846", UpdateAvailable: true},
+		{NodeID: "n2", OS: "linux", ClientVersion: "1.60.0"},
+		{NodeID: "n3", OS: "windows", ClientVersion: "1.58.10", UpdateAvailable: true},
+		{NodeID: "n4", OS: "linux", ClientVersion: ""},
+	}
+
+	inv := NewDeviceInventory(devices, "1.58.2")
+	assert.Equal(t, 4, inv.Total)
+	assert.Equal(t, map[string]int{"linux": 3, "windows": 1}, inv.ByOS)
+	assert.Equal(t, map[string]int{"1.22.2": 1, "1.60.0": 1, "1.58.10": 1, "": 1}, inv.ByClientVersion)
+	assert.Equal(t, 2, inv.UpdateAvailable)
+	assert.Equal(t, []Device{devices[0]}, inv.Outdated)
+
+	assert.Empty(t, NewDeviceInventory(devices, "").Outdated)
+}
+
+func TestCompareClientVersions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, compareClientVersions("1.58.2", "1.58.2-t1234"))
+	assert.Equal(t, -1, compareClientVersions("1.9.0", "1.10.0"))
+	assert.Equal(t, 1, compareClientVersions("1.10", "1.9.9"))
+	assert.Equal(t, 0, compareClientVersions("1.10", "1.10.0"))
+}
+
+func TestClient_Devices_Inventory(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string][]Device{
+		"devices": {
+			{NodeID: "n1", OS: "macOS", ClientVersion: "1.50.0"},
+			{NodeID: "n2", OS: "macOS", ClientVersion: "1.62.0"},
+		},
+	}
+
+	inv, err := client.Devices().Inventory(context.Background(), "1.60.0")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/devices", server.Path)
+	assert.Equal(t, 2, inv.ByOS["macOS"])
+	assert.Len(t, inv.Outdated, 1)
+	assert.Equal(t, "n1", inv.Outdated[0].NodeID)
+}
+
+func TestGroupDevices(t *testing.T) {
+	t.Parallel()
+
+	devices := []Device{
+		{NodeID: "n1", User: "alice@example.com", OS: "linux", Tags: []string{"tag:prod", "tag:web"}},
+		{NodeID: "n2", User: "bob@example.com", OS: "macOS"},
+		{NodeID: "n3", User: "alice@example.com", OS: "linux", Tags: []string{"tag:web"}},
+	}
+
+	byUser := GroupDevicesByUser(devices)
+	assert.Equal(t, []Device{devices[0], devices[2]}, byUser["alice@example.com"])
+	assert.Equal(t, []Device{devices[1]}, byUser["bob@example.com"])
+
+	byTag := GroupDevicesByTag(devices)
+	assert.Equal(t, []Device{devices[0]}, byTag["tag:prod"])
+	assert.Equal(t, []Device{devices[0], devices[2]}, byTag["tag:web"])
+	assert.Equal(t, []Device{devices[1]}, byTag[""])
+
+	var keys []string
+	for key, group := range GroupDevicesByOS(devices).All() {
+		keys = append(keys, key)
+		assert.NotEmpty(t, group)
+	}
+	assert.Equal(t, []string{"linux", "macOS"}, keys)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// DeviceChanges describes how the devices in a tailnet changed between two calls to [DeviceSync.Sync].
+type DeviceChanges struct {
+	Added   []Device
+	Updated []Device
+	Removed []Device
+}
+
+// Empty reports whether no devices were added, updated or removed.
+func (c *DeviceChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// DeviceSync keeps a local snapshot of the devices in a tailnet and reports only what
+// changed each time it is synced. Use [DevicesResource.NewSync] to create one.
+//
+// If the API returns an ETag for the device list, it is sent back as If-None-Match so that an
+// unchanged list is not downloaded again. Otherwise, changes are detected by hashing each device.
+type DeviceSync struct {
+	devices *DevicesResource
+	opts    []ListDevicesOptions
+
+	mu       sync.Mutex // protects the below fields
+	etag     string
+	snapshot map[string]Device
+	hashes   map[string][sha256.Size]byte
+}
+
+// NewSync returns a [DeviceSync] that lists devices with the given options.
+// The first call to [DeviceSync.Sync] reports every device as added.
+func (dr *DevicesResource) NewSync(opts ...ListDevicesOptions) *DeviceSync {
+	return &DeviceSync{
+		devices:  dr,
+		opts:     opts,
+		snapshot: make(map[string]Device),
+		hashes:   make(map[string][sha256.Size]byte),
+	}
+}
+
+// Sync fetches the current device list and returns the changes since the previous call.
+// Devices are identified by their NodeID.
+func (s *DeviceSync) Sync(ctx context.Context) (*DeviceChanges, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := s.devices.buildListRequest(ctx, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	m := make(map[string][]Device)
+	header, err := s.devices.doWithResponseHeaders(req, &m)
+	if err != nil {
+		return nil, err
+	}
+	devices, ok := m["devices"]
+	if !ok && s.etag != "" {
+		// 304 Not Modified, nothing changed.
+		return &DeviceChanges{}, nil
+	}
+	s.etag = header.Get("Etag")
+
+	changes := &DeviceChanges{}
+	seen := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		seen[d.NodeID] = true
+		b, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(b)
+		old, exists := s.hashes[d.NodeID]
+		switch {
+		case !exists:
+			changes.Added = append(changes.Added, d)
+		case old != hash:
+			changes.Updated = append(changes.Updated, d)
+		}
+		s.hashes[d.NodeID] = hash
+		s.snapshot[d.NodeID] = d
+	}
+
+	for _, nodeID := range slices.Sorted(maps.Keys(s.snapshot)) {
+		if !seen[nodeID] {
+			changes.Removed = append(changes.Removed, s.snapshot[nodeID])
+			delete(s.snapshot, nodeID)
+			delete(s.hashes, nodeID)
+		}
+	}
+
+	return changes, nil
+}
+
+// Devices returns the devices in the current snapshot, ordered by NodeID.
+func (s *DeviceSync) Devices() []Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	devices := make([]Device, 0, len(s.snapshot))
+	for _, nodeID := range slices.Sorted(maps.Keys(s.snapshot)) {
+		devices = append(devices, s.snapshot[nodeID])
+	}
+	return devices
+}
@@ -0,0 +1,71 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Devices_Sync(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	n1 := Device{NodeID: "n1", Name: "one"}
+	n2 := Device{NodeID: "n2", Name: "two"}
+	server.ResponseBody = map[string][]Device{"devices": {n1, n2}}
+
+	sync := client.Devices().NewSync(WithFields(IncludeFieldsAll))
+
+	changes, err := sync.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/tailnet/example.com/devices", server.Path)
+	assert.Equal(t, "all", server.Query.Get("fields"))
+	assert.Equal(t, []Device{n1, n2}, changes.Added)
+	assert.Empty(t, changes.Updated)
+	assert.Empty(t, changes.Removed)
+
+	changes, err = sync.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, changes.Empty())
+
+	n2.Name = "two-renamed"
+	n3 := Device{NodeID: "n3", Name: "three"}
+	server.ResponseBody = map[string][]Device{"devices": {n2, n3}}
+
+	changes, err = sync.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Device{n3}, changes.Added)
+	assert.Equal(t, []Device{n2}, changes.Updated)
+	assert.Equal(t, []Device{n1}, changes.Removed)
+	assert.Equal(t, []Device{n2, n3}, sync.Devices())
+}
+
+func TestClient_Devices_SyncNotModified(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseHeader.Set("ETag", `"v1"`)
+	server.ResponseBody = map[string][]Device{"devices": {{NodeID: "n1"}}}
+
+	sync := client.Devices().NewSync()
+	changes, err := sync.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, changes.Added, 1)
+	assert.Empty(t, server.Header.Get("If-None-Match"))
+
+	server.ResponseCode = http.StatusNotModified
+	server.ResponseBody = nil
+	changes, err = sync.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `"v1"`, server.Header.Get("If-None-Match"))
+	assert.True(t, changes.Empty())
+	assert.Len(t, sync.Devices(), 1)
+}
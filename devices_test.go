@@ -8,6 +8,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -473,6 +474,10 @@ func TestClient_SetDeviceName(t *testing.T) {
 	body := make(map[string]string)
 	assert.NoError(t, json.Unmarshal(server.Body.Bytes(), &body))
 	assert.EqualValues(t, name, body["name"])
+
+	assert.NoError(t, client.Devices().SetName(context.Background(), deviceID, "Web-01"))
+	assert.NoError(t, json.Unmarshal(server.Body.Bytes(), &body))
+	assert.Equal(t, "web-01", body["name"])
 }
 
 func TestClient_SetDeviceTags(t *testing.T) {
@@ -587,3 +592,136 @@ func TestClient_UserAgent(t *testing.T) {
 	assert.NoError(t, client.Devices().SetAuthorized(context.Background(), "test", true))
 	assert.Equal(t, "custom-user-agent", server.Header.Get("User-Agent"))
 }
+
+func TestDevice_IsOnline(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&Device{ConnectedToControl: true}).IsOnline())
+	assert.False(t, (&Device{LastSeen: ptrTo(Time{time.Now()})}).IsOnline())
+}
+
+func TestDevice_SeenWithin(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		Name     string
+		Device   Device
+		Expected bool
+	}{
+		{
+			Name:     "connected devices are always seen",
+			Device:   Device{ConnectedToControl: true},
+			Expected: true,
+		},
+		{
+			Name:     "nil last seen",
+			Device:   Device{},
+			Expected: false,
+		},
+		{
+			Name:     "zero last seen",
+			Device:   Device{LastSeen: &Time{}},
+			Expected: false,
+		},
+		{
+			Name:     "recently seen",
+			Device:   Device{LastSeen: ptrTo(Time{time.Now().Add(-time.Minute)})},
+			Expected: true,
+		},
+		{
+			Name:     "seen too long ago",
+			Device:   Device{LastSeen: ptrTo(Time{time.Now().Add(-time.Hour)})},
+			Expected: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Device.SeenWithin(5*time.Minute))
+		})
+	}
+}
+
+func TestClient_Devices_CheckTagOwnership(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = &ACL{
+		Groups: map[string][]string{
+			"group:devops": {"alice@example.com"},
+		},
+		TagOwners: map[string][]string{
+			"tag:prod":       {"group:devops"},
+			"tag:dev":        {"bob@example.com"},
+			"tag:monitoring": {"alice@example.com"},
+		},
+	}
+
+	err := client.Devices().CheckTagOwnership(context.Background(), "alice@example.com", []string{"tag:prod", "tag:monitoring"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/acl", server.Path)
+
+	err = client.Devices().CheckTagOwnership(context.Background(), "alice@example.com", []string{"tag:prod", "tag:dev", "tag:unknown"})
+	var tagErr *TagOwnershipError
+	assert.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, []string{"tag:unknown"}, tagErr.Undefined)
+	assert.Equal(t, []string{"tag:dev"}, tagErr.Unowned)
+	assert.EqualError(t, err, "tags not defined in tagOwners: tag:unknown; tags not owned by alice@example.com: tag:dev")
+}
+
+func TestValidateDeviceName(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateDeviceName(""))
+	assert.NoError(t, ValidateDeviceName("web-01"))
+	assert.Error(t, ValidateDeviceName("Web-01"))
+	assert.Error(t, ValidateDeviceName("web_01"))
+	assert.Error(t, ValidateDeviceName("-web"))
+	assert.Error(t, ValidateDeviceName("web-"))
+	assert.Error(t, ValidateDeviceName(strings.Repeat("a", 64)))
+}
+
+func TestClient_Devices_SetUniqueName(t *testing.T) {
+	t.Parallel()
+
+	var names []string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/tailnet/example.com/devices":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]Device{
+				"devices": {
+					{NodeID: "nSELF", Name: "web.example.ts.net"},
+					{NodeID: "n1", Name: "db.example.ts.net"},
+					{NodeID: "n2", Name: "db-1.example.ts.net"},
+				},
+			}))
+		case "/api/v2/device/nSELF/name":
+			body := make(map[string]string)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			names = append(names, body["name"])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	name, err := client.Devices().SetUniqueName(context.Background(), "nSELF", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, "web", name)
+
+	_, err = client.Devices().SetUniqueName(context.Background(), "nSELF", "db")
+	assert.ErrorIs(t, err, ErrDeviceNameInUse)
+
+	name, err = client.Devices().SetUniqueName(context.Background(), "nSELF", "db", WithAutoSuffix())
+	assert.NoError(t, err)
+	assert.Equal(t, "db-2", name)
+
+	_, err = client.Devices().SetUniqueName(context.Background(), "nSELF", "DB")
+	assert.ErrorIs(t, err, ErrDeviceNameInUse)
+
+	_, err = client.Devices().SetUniqueName(context.Background(), "nSELF", "web_01")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"web", "db-2"}, names)
+}
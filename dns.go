@@ -5,7 +5,13 @@ package tailscale
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
 )
 
 // DNSResource provides access to https://tailscale.com/api#tag/dns.
@@ -23,8 +29,17 @@ type DNSPreferences struct {
 	MagicDNS bool `json:"magicDNS"`
 }
 
+// MaxSearchPaths is the maximum number of search paths accepted by [DNSResource.SetSearchPaths].
+const MaxSearchPaths = 10
+
 // SetSearchPaths replaces the list of search paths with the list supplied by the user and returns an error otherwise.
+// The search paths are validated before calling the API and duplicates are removed, keeping the first occurrence.
 func (dr *DNSResource) SetSearchPaths(ctx context.Context, searchPaths []string) error {
+	searchPaths, err := normalizeSearchPaths(searchPaths)
+	if err != nil {
+		return err
+	}
+
 	req, err := dr.buildRequest(ctx, http.MethodPost, dr.buildTailnetURL("dns", "searchpaths"), requestBody(map[string][]string{
 		"searchPaths": searchPaths,
 	}))
@@ -50,6 +65,73 @@ func (dr *DNSResource) SearchPaths(ctx context.Context) ([]string, error) {
 	return resp["searchPaths"], nil
 }
 
+// AddSearchPath appends searchPath to the tailnet's search paths, unless it is already present.
+// As the search paths endpoint has no concurrency control, a concurrent change made between
+// reading and writing the search paths may be lost.
+func (dr *DNSResource) AddSearchPath(ctx context.Context, searchPath string) error {
+	if err := validateSearchPath(searchPath); err != nil {
+		return err
+	}
+	searchPaths, err := dr.SearchPaths(ctx)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(searchPaths, func(p string) bool { return sameSearchPath(p, searchPath) }) {
+		return nil
+	}
+	return dr.SetSearchPaths(ctx, append(searchPaths, searchPath))
+}
+
+// RemoveSearchPath removes searchPath from the tailnet's search paths, if present.
+// The same caveat as for [DNSResource.AddSearchPath] applies.
+func (dr *DNSResource) RemoveSearchPath(ctx context.Context, searchPath string) error {
+	searchPaths, err := dr.SearchPaths(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := slices.DeleteFunc(slices.Clone(searchPaths), func(p string) bool { return sameSearchPath(p, searchPath) })
+	if len(remaining) == len(searchPaths) {
+		return nil
+	}
+	return dr.SetSearchPaths(ctx, remaining)
+}
+
+// normalizeSearchPaths validates searchPaths and removes duplicates, keeping the first occurrence.
+func normalizeSearchPaths(searchPaths []string) ([]string, error) {
+	out := make([]string, 0, len(searchPaths))
+	for _, p := range searchPaths {
+		if err := validateSearchPath(p); err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(out, func(o string) bool { return sameSearchPath(o, p) }) {
+			out = append(out, p)
+		}
+	}
+	if len(out) > MaxSearchPaths {
+		return nil, fmt.Errorf("too many search paths: %d, the maximum is %d", len(out), MaxSearchPaths)
+	}
+	return out, nil
+}
+
+// validateSearchPath returns an error if searchPath is not a valid domain name.
+func validateSearchPath(searchPath string) error {
+	name := strings.TrimSuffix(searchPath, ".")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("invalid search path %q: must be a domain name of at most 253 characters", searchPath)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !isDomainLabel(label) {
+			return fmt.Errorf("invalid search path %q: invalid label %q", searchPath, label)
+		}
+	}
+	return nil
+}
+
+// sameSearchPath reports whether a and b refer to the same domain.
+func sameSearchPath(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
 // SetNameservers replaces the list of DNS nameservers for the given tailnet with the list supplied by the user. Note
 // that changing the list of DNS nameservers may also affect the status of MagicDNS (if MagicDNS is on).
 func (dr *DNSResource) SetNameservers(ctx context.Context, dns []string) error {
@@ -63,6 +145,23 @@ func (dr *DNSResource) SetNameservers(ctx context.Context, dns []string) error {
 	return dr.do(req, nil)
 }
 
+// SetNameserverAddrs is like [DNSResource.SetNameservers], but takes IP addresses,
+// returning an error without calling the API if any of them are invalid.
+func (dr *DNSResource) SetNameserverAddrs(ctx context.Context, addrs []netip.Addr) error {
+	dns, err := formatAddrs(addrs)
+	if err != nil {
+		return err
+	}
+	return dr.SetNameservers(ctx, dns)
+}
+
+// SetNameserverResolvers replaces the tailnet's global nameservers with the given resolvers,
+// which may also be DNS-over-HTTPS resolvers or be used with exit nodes. The resolvers are
+// validated before calling the API. The rest of the DNS configuration is left untouched.
+func (dr *DNSResource) SetNameserverResolvers(ctx context.Context, resolvers []DNSConfigurationResolver) error {
+	return dr.PatchConfiguration(ctx, UpdateDNSConfigurationRequest{Nameservers: &resolvers})
+}
+
 // Nameservers lists the DNS nameservers for the tailnet
 func (dr *DNSResource) Nameservers(ctx context.Context) ([]string, error) {
 	req, err := dr.buildRequest(ctx, http.MethodGet, dr.buildTailnetURL("dns", "nameservers"))
@@ -99,6 +198,23 @@ func (dr *DNSResource) UpdateSplitDNS(ctx context.Context, request SplitDNSReque
 	return resp, nil
 }
 
+// UpdateSplitDNSDomain sets the nameservers for a single split DNS domain, leaving all other
+// domains unchanged. To remove a domain, use [DNSResource.DeleteSplitDNSDomain].
+func (dr *DNSResource) UpdateSplitDNSDomain(ctx context.Context, domain string, nameservers []string) error {
+	if len(nameservers) == 0 {
+		return fmt.Errorf("no nameservers given for split DNS domain %q", domain)
+	}
+	_, err := dr.UpdateSplitDNS(ctx, SplitDNSRequest{domain: nameservers})
+	return err
+}
+
+// DeleteSplitDNSDomain removes a single split DNS domain, leaving all other domains unchanged.
+// It does nothing if the domain is not configured.
+func (dr *DNSResource) DeleteSplitDNSDomain(ctx context.Context, domain string) error {
+	_, err := dr.UpdateSplitDNS(ctx, SplitDNSRequest{domain: nil})
+	return err
+}
+
 // SetSplitDNS sets the split DNS settings for the tailnet using the provided
 // [SplitDNSRequest] object. This is a PUT operation that fully replaces the underlying
 // data structure.
@@ -113,6 +229,38 @@ func (dr *DNSResource) SetSplitDNS(ctx context.Context, request SplitDNSRequest)
 	return dr.do(req, nil)
 }
 
+// SetSplitDNSAddrs is like [DNSResource.SetSplitDNS], but takes IP addresses,
+// returning an error without calling the API if any of them are invalid.
+func (dr *DNSResource) SetSplitDNSAddrs(ctx context.Context, domains map[string][]netip.Addr) error {
+	request := make(SplitDNSRequest, len(domains))
+	for domain, addrs := range domains {
+		dns, err := formatAddrs(addrs)
+		if err != nil {
+			return fmt.Errorf("split DNS domain %q: %w", domain, err)
+		}
+		request[domain] = dns
+	}
+	return dr.SetSplitDNS(ctx, request)
+}
+
+// SetSplitDNSResolvers replaces the tailnet's split DNS configuration with the given resolvers,
+// which are validated before calling the API. The rest of the DNS configuration is left untouched.
+func (dr *DNSResource) SetSplitDNSResolvers(ctx context.Context, domains map[string][]DNSConfigurationResolver) error {
+	return dr.PatchConfiguration(ctx, UpdateDNSConfigurationRequest{SplitDNS: &domains})
+}
+
+// formatAddrs returns the string form of addrs, or an error if any of them are invalid.
+func formatAddrs(addrs []netip.Addr) ([]string, error) {
+	out := make([]string, 0, len(addrs))
+	for i, addr := range addrs {
+		if !addr.IsValid() {
+			return nil, fmt.Errorf("invalid address at index %d", i)
+		}
+		out = append(out, addr.String())
+	}
+	return out, nil
+}
+
 // SplitDNS retrieves the split DNS configuration for the tailnet.
 func (dr *DNSResource) SplitDNS(ctx context.Context) (SplitDNSResponse, error) {
 	req, err := dr.buildRequest(ctx, http.MethodGet, dr.buildTailnetURL("dns", "split-dns"))
@@ -127,6 +275,37 @@ func (dr *DNSResource) SplitDNS(ctx context.Context) (SplitDNSResponse, error) {
 	return resp, nil
 }
 
+// ErrNoTailnetName is returned by [DNSResource.TailnetName] when the tailnet has no devices
+// of its own from which to determine its MagicDNS name.
+var ErrNoTailnetName = errors.New("tailnet name unavailable: tailnet has no devices")
+
+// TailnetName returns the tailnet's MagicDNS base name, such as "tail1234.ts.net", which is the
+// domain under which its devices and services are named. The API doesn't expose it directly, so
+// it is derived from the fully qualified name of a device in the tailnet; if the tailnet has no
+// devices of its own, [ErrNoTailnetName] is returned.
+func (dr *DNSResource) TailnetName(ctx context.Context) (string, error) {
+	devices, err := dr.Devices().List(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range devices {
+		if d.IsExternal {
+			// Devices shared into the tailnet are named under their own tailnet.
+			continue
+		}
+		if _, name, ok := strings.Cut(strings.TrimSuffix(d.Name, "."), "."); ok {
+			return name, nil
+		}
+	}
+	return "", ErrNoTailnetName
+}
+
+// MagicDNSName returns the fully qualified MagicDNS name of host within the tailnet with
+// the given MagicDNS base name, e.g. MagicDNSName("web", "tail1234.ts.net") returns "web.tail1234.ts.net".
+func MagicDNSName(host, tailnetName string) string {
+	return host + "." + tailnetName
+}
+
 // Preferences retrieves the DNS preferences that are currently set for the given tailnet.
 func (dr *DNSResource) Preferences(ctx context.Context) (*DNSPreferences, error) {
 	req, err := dr.buildRequest(ctx, http.MethodGet, dr.buildTailnetURL("dns", "preferences"))
@@ -155,16 +334,110 @@ type DNSConfiguration struct {
 	Preferences DNSConfigurationPreferences           `json:"preferences,omitempty"`
 }
 
+// DNSConfigurationResolver is a DNS resolver. Its Address is either a plain IP address, an IP
+// address and port, a DNS-over-HTTPS URL such as "https://dns.nextdns.io/abc123", or a
+// DNS-over-HTTP URL such as "http://100.64.0.1:8080/dns-query" served over the tailnet.
 type DNSConfigurationResolver struct {
 	Address         string `json:"address,omitempty"`
 	UseWithExitNode bool   `json:"useWithExitNode,omitempty"`
 }
 
+const (
+	DNSResolverTypeIP   DNSResolverType = "ip"
+	DNSResolverTypeDoH  DNSResolverType = "doh"
+	DNSResolverTypeHTTP DNSResolverType = "http"
+)
+
+// DNSResolverType is the protocol used to reach a [DNSConfigurationResolver].
+type DNSResolverType string
+
+// Type returns the type of the resolver, or "" if its address is invalid.
+func (r DNSConfigurationResolver) Type() DNSResolverType {
+	typ, _ := parseResolverAddress(r.Address)
+	return typ
+}
+
+// Validate returns an error if the resolver's address is not in a format accepted by the API.
+func (r DNSConfigurationResolver) Validate() error {
+	_, err := parseResolverAddress(r.Address)
+	return err
+}
+
+func parseResolverAddress(addr string) (DNSResolverType, error) {
+	if _, err := netip.ParseAddr(addr); err == nil {
+		return DNSResolverTypeIP, nil
+	}
+	if _, err := netip.ParseAddrPort(addr); err == nil {
+		return DNSResolverTypeIP, nil
+	}
+	if !strings.Contains(addr, "://") {
+		return "", fmt.Errorf("invalid resolver %q: must be an IP address or a DNS-over-HTTPS URL", addr)
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid resolver %q: %w", addr, err)
+	}
+	if u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid resolver %q: URL must have a host and no credentials, query or fragment", addr)
+	}
+	switch u.Scheme {
+	case "https":
+		return DNSResolverTypeDoH, nil
+	case "http":
+		// DNS over HTTP is only served by peers over the tailnet, so it must be addressed by IP.
+		if _, err := netip.ParseAddr(u.Hostname()); err != nil {
+			return "", fmt.Errorf("invalid resolver %q: DNS-over-HTTP resolvers must be addressed by IP", addr)
+		}
+		return DNSResolverTypeHTTP, nil
+	default:
+		return "", fmt.Errorf("invalid resolver %q: unsupported scheme %q", addr, u.Scheme)
+	}
+}
+
+// validateResolvers returns an error if any of the given resolvers are invalid.
+func validateResolvers(resolvers []DNSConfigurationResolver) error {
+	for _, r := range resolvers {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type DNSConfigurationPreferences struct {
 	OverrideLocalDNS bool `json:"overrideLocalDNS,omitempty"`
 	MagicDNS         bool `json:"magicDNS,omitempty"`
 }
 
+// Validate returns an error if any of the configuration's resolvers are invalid.
+func (c DNSConfiguration) Validate() error {
+	if err := validateResolvers(c.Nameservers); err != nil {
+		return err
+	}
+	for _, resolvers := range c.SplitDNS {
+		if err := validateResolvers(resolvers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateDNSConfigurationRequest is a partial update of a tailnet's [DNSConfiguration]. Only non-nil
+// fields are changed; for example, setting only Preferences.MagicDNS toggles MagicDNS without
+// touching the nameservers. To clear a list, point to an empty slice.
+type UpdateDNSConfigurationRequest struct {
+	Nameservers *[]DNSConfigurationResolver            `json:"nameservers,omitempty"`
+	SplitDNS    *map[string][]DNSConfigurationResolver `json:"splitDNS,omitempty"`
+	SearchPaths *[]string                              `json:"searchPaths,omitempty"`
+	Preferences *UpdateDNSConfigurationPreferences     `json:"preferences,omitempty"`
+}
+
+// UpdateDNSConfigurationPreferences is a partial update of [DNSConfigurationPreferences].
+type UpdateDNSConfigurationPreferences struct {
+	OverrideLocalDNS *bool `json:"overrideLocalDNS,omitempty"`
+	MagicDNS         *bool `json:"magicDNS,omitempty"`
+}
+
 // Configuration retrieves the tailnet's complete DNS configuration.
 // WARNING - this is currently in alpha and subject to change.
 func (dr *DNSResource) Configuration(ctx context.Context) (*DNSConfiguration, error) {
@@ -179,9 +452,39 @@ func (dr *DNSResource) Configuration(ctx context.Context) (*DNSConfiguration, er
 // SetConfiguration sets the tailnet's complete DNS configuration.
 // WARNING - this is currently in alpha and subject to change.
 func (dr *DNSResource) SetConfiguration(ctx context.Context, configuration DNSConfiguration) error {
+	if err := configuration.Validate(); err != nil {
+		return err
+	}
+
 	req, err := dr.buildRequest(ctx, http.MethodPost, dr.buildTailnetURL("dns", "configuration"), requestBody(configuration))
 	if err != nil {
-		return nil
+		return err
+	}
+
+	return dr.do(req, nil)
+}
+
+// PatchConfiguration partially updates the tailnet's DNS configuration, changing only the fields
+// set in request. Unlike [DNSResource.SetConfiguration], it doesn't clobber concurrent changes to
+// other parts of the configuration.
+// WARNING - this is currently in alpha and subject to change.
+func (dr *DNSResource) PatchConfiguration(ctx context.Context, request UpdateDNSConfigurationRequest) error {
+	if request.Nameservers != nil {
+		if err := validateResolvers(*request.Nameservers); err != nil {
+			return err
+		}
+	}
+	if request.SplitDNS != nil {
+		for _, resolvers := range *request.SplitDNS {
+			if err := validateResolvers(resolvers); err != nil {
+				return err
+			}
+		}
+	}
+
+	req, err := dr.buildRequest(ctx, http.MethodPatch, dr.buildTailnetURL("dns", "configuration"), requestBody(request))
+	if err != nil {
+		return err
 	}
 
 	return dr.do(req, nil)
@@ -0,0 +1,113 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DNSConfigurationChange describes how a tailnet's DNS configuration changed between two checks
+// by a [DNSWatcher].
+type DNSConfigurationChange struct {
+	// Old is the configuration before the change.
+	Old *DNSConfiguration
+	// New is the configuration after the change.
+	New *DNSConfiguration
+
+	NameserversChanged      bool
+	SplitDNSChanged         bool
+	SearchPathsChanged      bool
+	MagicDNSChanged         bool
+	OverrideLocalDNSChanged bool
+}
+
+// Empty reports whether nothing changed.
+func (c *DNSConfigurationChange) Empty() bool {
+	return !c.NameserversChanged && !c.SplitDNSChanged && !c.SearchPathsChanged && !c.MagicDNSChanged && !c.OverrideLocalDNSChanged
+}
+
+// DNSWatcher polls a tailnet's DNS configuration and reports changes, such as edits made in the
+// admin console outside of an automation's control. Use [DNSResource.NewWatcher] to create one.
+type DNSWatcher struct {
+	dns *DNSResource
+
+	mu       sync.Mutex // protects baseline
+	baseline *DNSConfiguration
+}
+
+// NewWatcher returns a [DNSWatcher] for the tailnet's DNS configuration. Unless a baseline is set
+// with [DNSWatcher.SetBaseline], the configuration seen by the first check becomes the baseline.
+func (dr *DNSResource) NewWatcher() *DNSWatcher {
+	return &DNSWatcher{dns: dr}
+}
+
+// SetBaseline sets the configuration that the next check is compared against, such as the
+// desired configuration of an infrastructure-as-code tool.
+func (w *DNSWatcher) SetBaseline(configuration DNSConfiguration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.baseline = &configuration
+}
+
+// Check fetches the current DNS configuration and compares it to the baseline, which is then
+// updated to the current configuration. If there is no baseline yet, Check establishes it and
+// reports no change.
+func (w *DNSWatcher) Check(ctx context.Context) (*DNSConfigurationChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current, err := w.dns.Configuration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	old := w.baseline
+	w.baseline = current
+	if old == nil {
+		return &DNSConfigurationChange{Old: current, New: current}, nil
+	}
+	return diffDNSConfiguration(old, current), nil
+}
+
+// Watch calls [DNSWatcher.Check] every interval until ctx is done, calling handle for every
+// change found. It returns when ctx is done, or with the first error from Check or handle.
+// The interval must be positive.
+func (w *DNSWatcher) Watch(ctx context.Context, interval time.Duration, handle func(ctx context.Context, change *DNSConfigurationChange) error) error {
+	return poll(ctx, interval, func(ctx context.Context) error {
+		change, err := w.Check(ctx)
+		if err != nil {
+			return err
+		}
+		if !change.Empty() {
+			if err := handle(ctx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Revert restores the configuration from before change and makes it the baseline again.
+func (w *DNSWatcher) Revert(ctx context.Context, change *DNSConfigurationChange) error {
+	if err := w.dns.SetConfiguration(ctx, *change.Old); err != nil {
+		return err
+	}
+	w.SetBaseline(*change.Old)
+	return nil
+}
+
+func diffDNSConfiguration(old, new *DNSConfiguration) *DNSConfigurationChange {
+	return &DNSConfigurationChange{
+		Old:                     old,
+		New:                     new,
+		NameserversChanged:      !slices.Equal(old.Nameservers, new.Nameservers),
+		SplitDNSChanged:         !maps.EqualFunc(old.SplitDNS, new.SplitDNS, slices.Equal),
+		SearchPathsChanged:      !slices.Equal(old.SearchPaths, new.SearchPaths),
+		MagicDNSChanged:         old.Preferences.MagicDNS != new.Preferences.MagicDNS,
+		OverrideLocalDNSChanged: old.Preferences.OverrideLocalDNS != new.Preferences.OverrideLocalDNS,
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSWatcher(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	current := DNSConfiguration{
+		Nameservers: []DNSConfigurationResolver{{Address: "192.0.2.53"}},
+		Preferences: DNSConfigurationPreferences{MagicDNS: true},
+	}
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/dns/configuration", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&current))
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(current))
+	}))
+	ctx := context.Background()
+	watcher := client.DNS().NewWatcher()
+
+	change, err := watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.True(t, change.Empty())
+
+	mu.Lock()
+	current.Nameservers = []DNSConfigurationResolver{{Address: "198.51.100.53"}}
+	current.Preferences.MagicDNS = false
+	mu.Unlock()
+
+	change, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &DNSConfigurationChange{
+		Old: &DNSConfiguration{
+			Nameservers: []DNSConfigurationResolver{{Address: "192.0.2.53"}},
+			Preferences: DNSConfigurationPreferences{MagicDNS: true},
+		},
+		New: &DNSConfiguration{
+			Nameservers: []DNSConfigurationResolver{{Address: "198.51.100.53"}},
+		},
+		NameserversChanged: true,
+		MagicDNSChanged:    true,
+	}, change)
+
+	assert.NoError(t, watcher.Revert(ctx, change))
+	assert.Equal(t, "192.0.2.53", current.Nameservers[0].Address)
+	assert.True(t, current.Preferences.MagicDNS)
+
+	change, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.True(t, change.Empty())
+
+	watcher.SetBaseline(DNSConfiguration{SearchPaths: []string{"example.com"}, Preferences: DNSConfigurationPreferences{MagicDNS: true}})
+	errStop := errors.New("stop")
+	var changes []*DNSConfigurationChange
+	err = watcher.Watch(ctx, time.Millisecond, func(_ context.Context, change *DNSConfigurationChange) error {
+		changes = append(changes, change)
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Len(t, changes, 1)
+	assert.True(t, changes[0].NameserversChanged)
+	assert.True(t, changes[0].SearchPathsChanged)
+	assert.False(t, changes[0].MagicDNSChanged)
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = watcher.Watch(ctx, time.Millisecond, func(context.Context, *DNSConfigurationChange) error {
+		t.Error("unexpected change")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
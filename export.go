@@ -0,0 +1,67 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// recordWriter writes records to an [io.Writer], either as lines of JSON or as CSV records after
+// a header record. It backs the exporters of the different resources.
+type recordWriter struct {
+	buf  *bufio.Writer
+	json *json.Encoder
+	csv  *csv.Writer
+
+	header      []string
+	wroteHeader bool
+}
+
+// newNDJSONWriter returns a [recordWriter] that writes each value as a line of JSON to w.
+func newNDJSONWriter(w io.Writer) *recordWriter {
+	buf := bufio.NewWriter(w)
+	return &recordWriter{buf: buf, json: json.NewEncoder(buf)}
+}
+
+// newCSVWriter returns a [recordWriter] that writes CSV records to w, preceded by header.
+func newCSVWriter(w io.Writer, header []string) *recordWriter {
+	return &recordWriter{csv: csv.NewWriter(w), header: header}
+}
+
+// writeJSON writes v as a line of JSON.
+func (rw *recordWriter) writeJSON(v any) error {
+	return rw.json.Encode(v)
+}
+
+// writeCSV writes record, after the header record if it hasn't been written yet.
+func (rw *recordWriter) writeCSV(record []string) error {
+	if err := rw.writeHeader(); err != nil {
+		return err
+	}
+	return rw.csv.Write(record)
+}
+
+func (rw *recordWriter) writeHeader() error {
+	if rw.wroteHeader {
+		return nil
+	}
+	rw.wroteHeader = true
+	return rw.csv.Write(rw.header)
+}
+
+// flush writes any buffered output to the underlying writer. For CSV, the header record is
+// written even if no other records were.
+func (rw *recordWriter) flush() error {
+	if rw.csv != nil {
+		if err := rw.writeHeader(); err != nil {
+			return err
+		}
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+	return rw.buf.Flush()
+}
@@ -0,0 +1,35 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordWriter(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	rw := newNDJSONWriter(&b)
+	assert.NoError(t, rw.writeJSON(map[string]int{"a": 1}))
+	assert.NoError(t, rw.writeJSON(map[string]int{"b": 2}))
+	assert.Empty(t, b.String())
+	assert.NoError(t, rw.flush())
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", b.String())
+
+	b.Reset()
+	rw = newCSVWriter(&b, []string{"id", "name"})
+	assert.NoError(t, rw.writeCSV([]string{"1", "a,b"}))
+	assert.NoError(t, rw.writeCSV([]string{"2", "c"}))
+	assert.NoError(t, rw.flush())
+	assert.Equal(t, "id,name\n1,\"a,b\"\n2,c\n", b.String())
+
+	b.Reset()
+	rw = newCSVWriter(&b, []string{"id", "name"})
+	assert.NoError(t, rw.flush())
+	assert.Equal(t, "id,name\n", b.String())
+}
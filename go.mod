@@ -4,7 +4,7 @@ go 1.24.0
 
 require (
 	github.com/stretchr/testify v1.11.1
-	github.com/tailscale/hujson v0.0.0-20220506213045-af5ed07155e5
+	github.com/tailscale/hujson v0.0.0-20221223112325-20486734a56a
 	golang.org/x/oauth2 v0.34.0
 )
 
@@ -5,7 +5,12 @@ package tailscale
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -14,6 +19,17 @@ type KeysResource struct {
 	*Client
 }
 
+const (
+	KeyTypeAuth      KeyType = "auth"
+	KeyTypeAPI       KeyType = "api"
+	KeyTypeClient    KeyType = "client"
+	KeyTypeFederated KeyType = "federated"
+)
+
+// KeyType is the type of a [Key]: an auth key, an API access token, an OAuth client or a
+// federated identity.
+type KeyType string
+
 // KeyCapabilities describes the capabilities of an authentication key.
 type KeyCapabilities struct {
 	Devices struct {
@@ -31,6 +47,79 @@ type CreateKeyRequest struct {
 	Capabilities  KeyCapabilities `json:"capabilities"`
 	ExpirySeconds int64           `json:"expirySeconds"`
 	Description   string          `json:"description"`
+
+	// Expiry is an alternative to ExpirySeconds. It is rounded up to whole seconds and
+	// only used if ExpirySeconds is zero.
+	Expiry time.Duration `json:"-"`
+}
+
+func (r CreateKeyRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateKeyRequest
+	if r.ExpirySeconds == 0 && r.Expiry > 0 {
+		r.ExpirySeconds = int64((r.Expiry + time.Second - 1) / time.Second)
+	}
+	return json.Marshal(alias(r))
+}
+
+// AuthKeyBuilder assembles a [CreateKeyRequest] for an auth key using chained method calls.
+// Use [NewAuthKey] to create one and [AuthKeyBuilder.Build] to obtain the request.
+//
+//	key, err := client.Keys().CreateAuthKey(ctx, tailscale.NewAuthKey().
+//		Reusable().
+//		Preauthorized().
+//		Tags("tag:ci").
+//		Expiry(24 * time.Hour).
+//		Description("CI runners").
+//		Build())
+type AuthKeyBuilder struct {
+	req CreateKeyRequest
+}
+
+// NewAuthKey returns a new [AuthKeyBuilder] for a single-use, non-ephemeral auth key.
+func NewAuthKey() *AuthKeyBuilder {
+	return &AuthKeyBuilder{}
+}
+
+// Reusable allows the key to be used to add more than one device.
+func (b *AuthKeyBuilder) Reusable() *AuthKeyBuilder {
+	b.req.Capabilities.Devices.Create.Reusable = true
+	return b
+}
+
+// Ephemeral makes devices added with the key ephemeral, so they are removed once they go offline.
+func (b *AuthKeyBuilder) Ephemeral() *AuthKeyBuilder {
+	b.req.Capabilities.Devices.Create.Ephemeral = true
+	return b
+}
+
+// Preauthorized approves devices added with the key, if device approval is enabled for the tailnet.
+func (b *AuthKeyBuilder) Preauthorized() *AuthKeyBuilder {
+	b.req.Capabilities.Devices.Create.Preauthorized = true
+	return b
+}
+
+// Tags applies the given tags to devices added with the key.
+func (b *AuthKeyBuilder) Tags(tags ...string) *AuthKeyBuilder {
+	b.req.Capabilities.Devices.Create.Tags = append(b.req.Capabilities.Devices.Create.Tags, tags...)
+	return b
+}
+
+// Expiry sets how long the key remains valid. If unset, the API default applies.
+func (b *AuthKeyBuilder) Expiry(d time.Duration) *AuthKeyBuilder {
+	b.req.ExpirySeconds = 0
+	b.req.Expiry = d
+	return b
+}
+
+// Description sets a description of the key.
+func (b *AuthKeyBuilder) Description(description string) *AuthKeyBuilder {
+	b.req.Description = description
+	return b
+}
+
+// Build returns the assembled [CreateKeyRequest].
+func (b *AuthKeyBuilder) Build() CreateKeyRequest {
+	return b.req
 }
 
 // CreateOAuthClientRequest describes the definition of an OAuth client to create.
@@ -41,7 +130,7 @@ type CreateOAuthClientRequest struct {
 }
 
 type createOAuthClientWithKeyTypeRequest struct {
-	KeyType string `json:"keyType"`
+	KeyType KeyType `json:"keyType"`
 	CreateOAuthClientRequest
 }
 
@@ -54,7 +143,7 @@ type SetOAuthClientRequest struct {
 }
 
 type setOAuthClientWithKeyTypeRequest struct {
-	KeyType string `json:"keyType"`
+	KeyType KeyType `json:"keyType"`
 	SetOAuthClientRequest
 }
 
@@ -65,12 +154,12 @@ type CreateFederatedIdentityRequest struct {
 	Audience         string            `json:"audience"`
 	Issuer           string            `json:"issuer"`
 	Subject          string            `json:"subject"`
-	CustomClaimRules map[string]string `json:"customClaimRules"`
+	CustomClaimRules map[string]string `json:"customClaimRules"` // see [NewClaimRules]
 	Description      string            `json:"description"`
 }
 
 type createFederatedIdentityWithKeyTypeRequest struct {
-	KeyType string `json:"keyType"`
+	KeyType KeyType `json:"keyType"`
 	CreateFederatedIdentityRequest
 }
 
@@ -81,19 +170,19 @@ type SetFederatedIdentityRequest struct {
 	Audience         string            `json:"audience"`
 	Issuer           string            `json:"issuer"`
 	Subject          string            `json:"subject"`
-	CustomClaimRules map[string]string `json:"customClaimRules"`
+	CustomClaimRules map[string]string `json:"customClaimRules"` // see [NewClaimRules]
 	Description      string            `json:"description"`
 }
 
 type setFederatedIdentityWithKeyTypeRequest struct {
-	KeyType string `json:"keyType"`
+	KeyType KeyType `json:"keyType"`
 	SetFederatedIdentityRequest
 }
 
 // Key describes an authentication key within the tailnet.
 type Key struct {
 	ID               string            `json:"id"`
-	KeyType          string            `json:"keyType"`
+	KeyType          KeyType           `json:"keyType"`
 	Key              string            `json:"key"`
 	Description      string            `json:"description"`
 	ExpirySeconds    *time.Duration    `json:"expirySeconds"`
@@ -112,6 +201,180 @@ type Key struct {
 	CustomClaimRules map[string]string `json:"customClaimRules"`
 }
 
+// MarshalJSON encodes ExpirySeconds as a number of seconds, as used by the API.
+func (k Key) MarshalJSON() ([]byte, error) {
+	type alias Key
+	out := struct {
+		alias
+		ExpirySeconds *int64 `json:"expirySeconds"`
+	}{alias: alias(k)}
+	if k.ExpirySeconds != nil {
+		secs := int64(*k.ExpirySeconds / time.Second)
+		out.ExpirySeconds = &secs
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes ExpirySeconds from a number of seconds, as used by the API.
+func (k *Key) UnmarshalJSON(b []byte) error {
+	type alias Key
+	aux := struct {
+		*alias
+		ExpirySeconds *int64 `json:"expirySeconds"`
+	}{alias: (*alias)(k)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	k.ExpirySeconds = nil
+	if aux.ExpirySeconds != nil {
+		d := time.Duration(*aux.ExpirySeconds) * time.Second
+		k.ExpirySeconds = &d
+	}
+	return nil
+}
+
+// Expiry returns the lifetime of the key: ExpirySeconds if set, otherwise the time between
+// its creation and expiry. It returns 0 if neither is known.
+func (k *Key) Expiry() time.Duration {
+	if k.ExpirySeconds != nil {
+		return *k.ExpirySeconds
+	}
+	if k.Created.IsZero() || k.Expires.IsZero() {
+		return 0
+	}
+	return k.Expires.Sub(k.Created)
+}
+
+// AuthKey is an auth key, used to add devices to the tailnet.
+type AuthKey struct {
+	ID           string
+	Key          string
+	Description  string
+	Created      time.Time
+	Expires      time.Time
+	Revoked      time.Time
+	Invalid      bool
+	Capabilities KeyCapabilities
+	UserID       string
+}
+
+// OAuthClient is an OAuth client, used to obtain API access tokens with the given scopes.
+type OAuthClient struct {
+	ID          string
+	Key         string
+	Description string
+	Created     time.Time
+	Updated     time.Time
+	Revoked     time.Time
+	Invalid     bool
+	Scopes      []string
+	Tags        []string
+	UserID      string
+}
+
+// FederatedIdentity is a federated identity, used to exchange ID tokens issued by a trusted
+// identity provider for API access tokens with the given scopes.
+type FederatedIdentity struct {
+	ID               string
+	Description      string
+	Created          time.Time
+	Updated          time.Time
+	Revoked          time.Time
+	Invalid          bool
+	Scopes           []string
+	Tags             []string
+	UserID           string
+	Audience         string
+	Issuer           string
+	Subject          string
+	CustomClaimRules map[string]string
+}
+
+// AuthKey returns k as an [AuthKey]. It reports false if k is not an auth key.
+func (k *Key) AuthKey() (*AuthKey, bool) {
+	if k.KeyType != KeyTypeAuth {
+		return nil, false
+	}
+	return &AuthKey{
+		ID:           k.ID,
+		Key:          k.Key,
+		Description:  k.Description,
+		Created:      k.Created,
+		Expires:      k.Expires,
+		Revoked:      k.Revoked,
+		Invalid:      k.Invalid,
+		Capabilities: k.Capabilities,
+		UserID:       k.UserID,
+	}, true
+}
+
+// OAuthClient returns k as an [OAuthClient]. It reports false if k is not an OAuth client.
+func (k *Key) OAuthClient() (*OAuthClient, bool) {
+	if k.KeyType != KeyTypeClient {
+		return nil, false
+	}
+	return &OAuthClient{
+		ID:          k.ID,
+		Key:         k.Key,
+		Description: k.Description,
+		Created:     k.Created,
+		Updated:     k.Updated,
+		Revoked:     k.Revoked,
+		Invalid:     k.Invalid,
+		Scopes:      k.Scopes,
+		Tags:        k.Tags,
+		UserID:      k.UserID,
+	}, true
+}
+
+// FederatedIdentity returns k as a [FederatedIdentity]. It reports false if k is not a federated identity.
+func (k *Key) FederatedIdentity() (*FederatedIdentity, bool) {
+	if k.KeyType != KeyTypeFederated {
+		return nil, false
+	}
+	return &FederatedIdentity{
+		ID:               k.ID,
+		Description:      k.Description,
+		Created:          k.Created,
+		Updated:          k.Updated,
+		Revoked:          k.Revoked,
+		Invalid:          k.Invalid,
+		Scopes:           k.Scopes,
+		Tags:             k.Tags,
+		UserID:           k.UserID,
+		Audience:         k.Audience,
+		Issuer:           k.Issuer,
+		Subject:          k.Subject,
+		CustomClaimRules: k.CustomClaimRules,
+	}, true
+}
+
+// KeysByType holds the keys of a tailnet, separated by type. See [KeysResource.ListByType].
+type KeysByType struct {
+	AuthKeys            []AuthKey
+	OAuthClients        []OAuthClient
+	FederatedIdentities []FederatedIdentity
+	// Other holds keys of any other type, such as API access tokens, or whose type is unknown.
+	Other []Key
+}
+
+// GroupKeysByType separates keys by their type, keeping their order within each type.
+func GroupKeysByType(keys []Key) *KeysByType {
+	out := &KeysByType{}
+	for _, k := range keys {
+		if a, ok := k.AuthKey(); ok {
+			out.AuthKeys = append(out.AuthKeys, *a)
+		} else if c, ok := k.OAuthClient(); ok {
+			out.OAuthClients = append(out.OAuthClients, *c)
+		} else if f, ok := k.FederatedIdentity(); ok {
+			out.FederatedIdentities = append(out.FederatedIdentities, *f)
+		} else {
+			out.Other = append(out.Other, k)
+		}
+	}
+	return out
+}
+
 // Create creates a new authentication key. Returns the generated [Key] if successful.
 // Deprecated: Use CreateAuthKey instead.
 func (kr *KeysResource) Create(ctx context.Context, ckr CreateKeyRequest) (*Key, error) {
@@ -131,7 +394,7 @@ func (kr *KeysResource) CreateAuthKey(ctx context.Context, ckr CreateKeyRequest)
 // CreateOAuthClient creates a new OAuth client. Returns the generated [Key] if successful.
 func (kr *KeysResource) CreateOAuthClient(ctx context.Context, ckr CreateOAuthClientRequest) (*Key, error) {
 	req, err := kr.buildRequest(ctx, http.MethodPost, kr.buildTailnetURL("keys"), requestBody(createOAuthClientWithKeyTypeRequest{
-		KeyType:                  "client",
+		KeyType:                  KeyTypeClient,
 		CreateOAuthClientRequest: ckr,
 	}))
 	if err != nil {
@@ -144,7 +407,7 @@ func (kr *KeysResource) CreateOAuthClient(ctx context.Context, ckr CreateOAuthCl
 // SetOAuthClient sets the configuration for an existing OAuth client. Returns the generated [Key] if successful.
 func (kr *KeysResource) SetOAuthClient(ctx context.Context, id string, skr SetOAuthClientRequest) (*Key, error) {
 	req, err := kr.buildRequest(ctx, http.MethodPut, kr.buildTailnetURL("keys", id), requestBody(setOAuthClientWithKeyTypeRequest{
-		KeyType:               "client",
+		KeyType:               KeyTypeClient,
 		SetOAuthClientRequest: skr,
 	}))
 	if err != nil {
@@ -154,10 +417,36 @@ func (kr *KeysResource) SetOAuthClient(ctx context.Context, id string, skr SetOA
 	return body[Key](kr, req)
 }
 
+// RotateOAuthClient creates a new OAuth client with the same scopes, tags and description as the
+// OAuth client with the given ID. The API has no way to change the secret of an existing client, so
+// the returned [Key] has a new ID as well as the new secret, which is only ever returned here. The
+// old client keeps working, so that running consumers aren't cut off; once they use the new secret,
+// retire the old client by passing its ID to [KeysResource.Delete].
+func (kr *KeysResource) RotateOAuthClient(ctx context.Context, id string) (*Key, error) {
+	old, err := kr.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if old.KeyType != KeyTypeClient {
+		return nil, fmt.Errorf("key %s is not an OAuth client: key type is %q", id, old.KeyType)
+	}
+
+	return kr.CreateOAuthClient(ctx, CreateOAuthClientRequest{
+		Scopes:      old.Scopes,
+		Tags:        old.Tags,
+		Description: old.Description,
+	})
+}
+
 // CreateFederatedIdentity creates a new federated identity. Returns the generated [Key] if successful.
+// The custom claim rules are checked with [ValidateClaimRules] before the request is made.
 func (kr *KeysResource) CreateFederatedIdentity(ctx context.Context, ckr CreateFederatedIdentityRequest) (*Key, error) {
+	if err := ValidateClaimRules(ckr.CustomClaimRules); err != nil {
+		return nil, err
+	}
+
 	req, err := kr.buildRequest(ctx, http.MethodPost, kr.buildTailnetURL("keys"), requestBody(createFederatedIdentityWithKeyTypeRequest{
-		KeyType:                        "federated",
+		KeyType:                        KeyTypeFederated,
 		CreateFederatedIdentityRequest: ckr,
 	}))
 	if err != nil {
@@ -168,9 +457,14 @@ func (kr *KeysResource) CreateFederatedIdentity(ctx context.Context, ckr CreateF
 }
 
 // SetFederatedIdentity sets the configuration for an existing federated identity. Returns the generated [Key] if successful.
+// The custom claim rules are checked with [ValidateClaimRules] before the request is made.
 func (kr *KeysResource) SetFederatedIdentity(ctx context.Context, id string, skr SetFederatedIdentityRequest) (*Key, error) {
+	if err := ValidateClaimRules(skr.CustomClaimRules); err != nil {
+		return nil, err
+	}
+
 	req, err := kr.buildRequest(ctx, http.MethodPut, kr.buildTailnetURL("keys", id), requestBody(setFederatedIdentityWithKeyTypeRequest{
-		KeyType:                     "federated",
+		KeyType:                     KeyTypeFederated,
 		SetFederatedIdentityRequest: skr,
 	}))
 	if err != nil {
@@ -191,26 +485,224 @@ func (kr *KeysResource) Get(ctx context.Context, id string) (*Key, error) {
 	return body[Key](kr, req)
 }
 
+// ErrUnknownCredential is returned by [KeysResource.GetCurrent] when the key that the client
+// authenticates with cannot be determined from its configuration.
+var ErrUnknownCredential = errors.New("unable to determine the key used to authenticate the client")
+
+// GetCurrent returns the metadata of the key that the client authenticates with, so that tools can
+// report their own identity and check their scopes. For [OAuth] and [IdentityFederation] this is the
+// OAuth client or federated identity identified by ClientID; otherwise it is the API key in APIKey.
+// Returns [ErrUnknownCredential] for any other [Auth].
+func (kr *KeysResource) GetCurrent(ctx context.Context) (*Key, error) {
+	id, err := kr.currentKeyID()
+	if err != nil {
+		return nil, err
+	}
+	return kr.Get(ctx, id)
+}
+
+// currentKeyID returns the ID of the key that the client authenticates with.
+func (kr *KeysResource) currentKeyID() (string, error) {
+	switch auth := kr.Auth.(type) {
+	case *OAuth:
+		return auth.ClientID, nil
+	case *IdentityFederation:
+		return auth.ClientID, nil
+	case nil:
+		// API keys have the form tskey-api-<id>-<secret>.
+		rest, ok := strings.CutPrefix(kr.APIKey, "tskey-api-")
+		id, _, found := strings.Cut(rest, "-")
+		if ok && found && id != "" {
+			return id, nil
+		}
+	}
+	return "", ErrUnknownCredential
+}
+
 // List returns every [Key] within the tailnet. The only fields set for each [Key] will be its identifier.
 // The keys returned are relative to the user that owns the API key used to authenticate the client.
 //
-// Specify all to list both user and tailnet level keys.
-func (kr *KeysResource) List(ctx context.Context, all bool) ([]Key, error) {
+// Specify all to list both user and tailnet level keys. The keys can be filtered with options such
+// as [WithKeyType]; the API doesn't support filtering keys, so filters are applied client-side, and
+// all fields of the keys are requested whenever a filter is given.
+func (kr *KeysResource) List(ctx context.Context, all bool, opts ...ListKeysOptions) ([]Key, error) {
+	o := newListKeysOptions(opts)
+	req, err := kr.buildListRequest(ctx, all, o)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make(map[string][]Key)
+	if err = kr.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	keys := resp["keys"]
+	if !o.filtered() {
+		return keys, nil
+	}
+	now := time.Now()
+	return slices.DeleteFunc(keys, func(k Key) bool { return !o.matches(&k, now) }), nil
+}
+
+// ListKeysOptions filters the keys returned by [KeysResource.List] and [KeysResource.Iter].
+type ListKeysOptions func(*listKeysOptions)
+
+func newListKeysOptions(opts []ListKeysOptions) *listKeysOptions {
+	var o listKeysOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+func (kr *KeysResource) buildListRequest(ctx context.Context, all bool, o *listKeysOptions) (*http.Request, error) {
 	url := kr.buildTailnetURL("keys")
+	query := url.Query()
 	if all {
-		url.RawQuery = "all=true"
+		query.Set("all", "true")
 	}
-	req, err := kr.buildRequest(ctx, http.MethodGet, url)
+	fields := o.fields
+	if o.filtered() {
+		// Filters are applied to the keys' details, which are only returned with all fields.
+		fields = IncludeFieldsAll
+	}
+	if fields != "" {
+		query.Set("fields", fields.String())
+	}
+	url.RawQuery = query.Encode()
+	return kr.buildRequest(ctx, http.MethodGet, url)
+}
+
+type listKeysOptions struct {
+	// fields specifies which fields to include in the response.
+	// Defaults to [IncludeFieldsDefault] if empty.
+	fields   IncludeFields
+	keyTypes []KeyType
+	tag      string
+	userID   string
+	valid    *bool
+}
+
+// WithKeyFields specifies which fields to include in the response. By default, the API only returns
+// the ID of each key. Use [IncludeFieldsAll] to also return each key's capabilities, scopes, creator
+// and expiry, avoiding a call to [KeysResource.Get] for every key.
+func WithKeyFields(fields IncludeFields) ListKeysOptions {
+	return func(o *listKeysOptions) {
+		o.fields = fields
+	}
+}
+
+// WithKeyType only returns keys of the given types.
+func WithKeyType(keyTypes ...KeyType) ListKeysOptions {
+	return func(o *listKeysOptions) {
+		o.keyTypes = append(o.keyTypes, keyTypes...)
+	}
+}
+
+// WithKeyTag only returns keys with the given tag. For auth keys, this is a tag applied to the
+// devices they create; for OAuth clients and federated identities, a tag they may assign.
+func WithKeyTag(tag string) ListKeysOptions {
+	return func(o *listKeysOptions) {
+		o.tag = tag
+	}
+}
+
+// WithKeyUser only returns keys created by the user with the given ID.
+func WithKeyUser(userID string) ListKeysOptions {
+	return func(o *listKeysOptions) {
+		o.userID = userID
+	}
+}
+
+// WithKeyValid only returns keys that are valid, or only keys that are invalid, revoked or expired.
+func WithKeyValid(valid bool) ListKeysOptions {
+	return func(o *listKeysOptions) {
+		o.valid = &valid
+	}
+}
+
+// filtered reports whether any option filters the listed keys.
+func (o *listKeysOptions) filtered() bool {
+	return len(o.keyTypes) > 0 || o.tag != "" || o.userID != "" || o.valid != nil
+}
+
+func (o *listKeysOptions) matches(k *Key, now time.Time) bool {
+	if len(o.keyTypes) > 0 && !slices.Contains(o.keyTypes, k.KeyType) {
+		return false
+	}
+	if o.tag != "" && !slices.Contains(k.Tags, o.tag) && !slices.Contains(k.Capabilities.Devices.Create.Tags, o.tag) {
+		return false
+	}
+	if o.userID != "" && k.UserID != o.userID {
+		return false
+	}
+	if o.valid != nil && k.isValidAt(now) != *o.valid {
+		return false
+	}
+	return true
+}
+
+// IsValid reports whether the key can still be used: it is not marked invalid, revoked or expired.
+func (k *Key) IsValid() bool {
+	return k.isValidAt(time.Now())
+}
+
+func (k *Key) isValidAt(now time.Time) bool {
+	return !k.Invalid && k.Revoked.IsZero() && (k.Expires.IsZero() || k.Expires.After(now))
+}
+
+// ListExpiringWithin returns the tailnet's keys that expire within window, along with those that are
+// already invalid, revoked or expired, sorted by expiry. If the key list doesn't include a key's
+// metadata, it is fetched with [KeysResource.Get], so that descriptions and tags are always populated.
+func (kr *KeysResource) ListExpiringWithin(ctx context.Context, window time.Duration) ([]Key, error) {
+	keys, err := kr.listWithDetails(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := make(map[string][]Key)
-	if err = kr.do(req, &resp); err != nil {
+	deadline := time.Now().Add(window)
+	var expiring []Key
+	for _, k := range keys {
+		if !k.IsValid() || (!k.Expires.IsZero() && k.Expires.Before(deadline)) {
+			expiring = append(expiring, k)
+		}
+	}
+	slices.SortStableFunc(expiring, func(a, b Key) int {
+		return a.Expires.Compare(b.Expires)
+	})
+	return expiring, nil
+}
+
+// listWithDetails lists every user and tailnet level key with all fields. Keys that the list
+// doesn't include metadata for are fetched individually with [KeysResource.Get].
+func (kr *KeysResource) listWithDetails(ctx context.Context) ([]Key, error) {
+	keys, err := kr.List(ctx, true, WithKeyFields(IncludeFieldsAll))
+	if err != nil {
 		return nil, err
 	}
+	for i, k := range keys {
+		if k.KeyType != "" {
+			continue
+		}
+		full, err := kr.Get(ctx, k.ID)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = *full
+	}
+	return keys, nil
+}
 
-	return resp["keys"], nil
+// ListByType is like [KeysResource.List], but returns the keys separated by type. All fields of the
+// keys are requested, as their types are not returned otherwise. Keys for which the API doesn't
+// report a type are returned in [KeysByType.Other].
+func (kr *KeysResource) ListByType(ctx context.Context, all bool) (*KeysByType, error) {
+	keys, err := kr.List(ctx, all, WithKeyFields(IncludeFieldsAll))
+	if err != nil {
+		return nil, err
+	}
+	return GroupKeysByType(keys), nil
 }
 
 // Delete removes an authentication key from the tailnet.
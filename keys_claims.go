@@ -0,0 +1,214 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ClaimMatchOperator determines how a [ClaimRule] matches the value of an ID token claim.
+type ClaimMatchOperator string
+
+const (
+	ClaimMatchEquals   ClaimMatchOperator = "equals"
+	ClaimMatchPrefix   ClaimMatchOperator = "prefix"
+	ClaimMatchSuffix   ClaimMatchOperator = "suffix"
+	ClaimMatchContains ClaimMatchOperator = "contains"
+	ClaimMatchPresent  ClaimMatchOperator = "present"
+	ClaimMatchGlob     ClaimMatchOperator = "glob"
+)
+
+// claimWildcard matches any sequence of characters in a custom claim rule pattern.
+const claimWildcard = "*"
+
+// ClaimRule is a typed form of a single entry in the CustomClaimRules of a federated identity,
+// which maps a claim name to the pattern its value must match.
+type ClaimRule struct {
+	// Claim is the name of the ID token claim.
+	Claim string
+	// Operator determines how Value is matched.
+	Operator ClaimMatchOperator
+	// Value is the value to match. It is ignored for [ClaimMatchPresent], and may contain
+	// "*" wildcards only for [ClaimMatchGlob].
+	Value string
+}
+
+// Pattern returns the pattern that the API uses to represent the rule.
+func (r ClaimRule) Pattern() string {
+	switch r.Operator {
+	case ClaimMatchPrefix:
+		return r.Value + claimWildcard
+	case ClaimMatchSuffix:
+		return claimWildcard + r.Value
+	case ClaimMatchContains:
+		return claimWildcard + r.Value + claimWildcard
+	case ClaimMatchPresent:
+		return claimWildcard
+	default:
+		return r.Value
+	}
+}
+
+// Validate checks that the rule has a valid claim name, a known operator and a value
+// that can be represented as a pattern.
+func (r ClaimRule) Validate() error {
+	if err := validateClaimName(r.Claim); err != nil {
+		return err
+	}
+	switch r.Operator {
+	case ClaimMatchPresent:
+		return nil
+	case ClaimMatchEquals, ClaimMatchPrefix, ClaimMatchSuffix, ClaimMatchContains:
+		if r.Value == "" {
+			return fmt.Errorf("claim %q: %s rule requires a value", r.Claim, r.Operator)
+		}
+		if strings.Contains(r.Value, claimWildcard) {
+			return fmt.Errorf("claim %q: %s rule value %q must not contain %q, use a glob rule instead", r.Claim, r.Operator, r.Value, claimWildcard)
+		}
+	case ClaimMatchGlob:
+		if r.Value == "" {
+			return fmt.Errorf("claim %q: glob rule requires a pattern", r.Claim)
+		}
+	default:
+		return fmt.Errorf("claim %q: unknown match operator %q", r.Claim, r.Operator)
+	}
+	if strings.ContainsFunc(r.Value, unicode.IsControl) {
+		return fmt.Errorf("claim %q: value %q contains control characters", r.Claim, r.Value)
+	}
+	return nil
+}
+
+// ParseClaimRule parses a claim name and pattern, as found in CustomClaimRules, into a [ClaimRule].
+// Patterns with a single leading or trailing wildcard are parsed as prefix, suffix or contains rules;
+// any other pattern containing a wildcard is parsed as a glob rule.
+func ParseClaimRule(claim, pattern string) (ClaimRule, error) {
+	r := ClaimRule{Claim: claim}
+	inner := strings.TrimPrefix(strings.TrimSuffix(pattern, claimWildcard), claimWildcard)
+	switch {
+	case pattern == claimWildcard:
+		r.Operator = ClaimMatchPresent
+	case !strings.Contains(pattern, claimWildcard):
+		r.Operator, r.Value = ClaimMatchEquals, pattern
+	case inner == "" || strings.Contains(inner, claimWildcard):
+		r.Operator, r.Value = ClaimMatchGlob, pattern
+	case strings.HasPrefix(pattern, claimWildcard) && strings.HasSuffix(pattern, claimWildcard):
+		r.Operator, r.Value = ClaimMatchContains, inner
+	case strings.HasSuffix(pattern, claimWildcard):
+		r.Operator, r.Value = ClaimMatchPrefix, inner
+	default:
+		r.Operator, r.Value = ClaimMatchSuffix, inner
+	}
+	return r, r.Validate()
+}
+
+// ParseClaimRules parses the CustomClaimRules of a federated identity into [ClaimRule]s, ordered by claim name.
+func ParseClaimRules(rules map[string]string) ([]ClaimRule, error) {
+	parsed := make([]ClaimRule, 0, len(rules))
+	var errs []error
+	for _, claim := range slices.Sorted(maps.Keys(rules)) {
+		r, err := ParseClaimRule(claim, rules[claim])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, errors.Join(errs...)
+}
+
+// ValidateClaimRules checks the syntax of the CustomClaimRules of a federated identity.
+func ValidateClaimRules(rules map[string]string) error {
+	_, err := ParseClaimRules(rules)
+	return err
+}
+
+// validateClaimName checks that name is a non-empty claim name without whitespace.
+func validateClaimName(name string) error {
+	if name == "" {
+		return errors.New("claim name must not be empty")
+	}
+	if strings.ContainsFunc(name, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return fmt.Errorf("claim name %q must not contain whitespace", name)
+	}
+	return nil
+}
+
+// ClaimRulesBuilder assembles the CustomClaimRules of a federated identity using chained method calls.
+// Use [NewClaimRules] to create one and [ClaimRulesBuilder.Build] to obtain the rules.
+//
+//	rules, err := tailscale.NewClaimRules().
+//		Equals("repository", "example/app").
+//		HasPrefix("ref", "refs/heads/release-").
+//		Build()
+type ClaimRulesBuilder struct {
+	rules []ClaimRule
+}
+
+// NewClaimRules returns a new, empty [ClaimRulesBuilder].
+func NewClaimRules() *ClaimRulesBuilder {
+	return &ClaimRulesBuilder{}
+}
+
+// Rule adds rule.
+func (b *ClaimRulesBuilder) Rule(rule ClaimRule) *ClaimRulesBuilder {
+	b.rules = append(b.rules, rule)
+	return b
+}
+
+// Equals requires claim to equal value exactly.
+func (b *ClaimRulesBuilder) Equals(claim, value string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchEquals, Value: value})
+}
+
+// HasPrefix requires claim to start with prefix.
+func (b *ClaimRulesBuilder) HasPrefix(claim, prefix string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchPrefix, Value: prefix})
+}
+
+// HasSuffix requires claim to end with suffix.
+func (b *ClaimRulesBuilder) HasSuffix(claim, suffix string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchSuffix, Value: suffix})
+}
+
+// Contains requires claim to contain substr.
+func (b *ClaimRulesBuilder) Contains(claim, substr string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchContains, Value: substr})
+}
+
+// Present requires claim to be present, with any value.
+func (b *ClaimRulesBuilder) Present(claim string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchPresent})
+}
+
+// Glob requires claim to match pattern, in which "*" matches any sequence of characters.
+func (b *ClaimRulesBuilder) Glob(claim, pattern string) *ClaimRulesBuilder {
+	return b.Rule(ClaimRule{Claim: claim, Operator: ClaimMatchGlob, Value: pattern})
+}
+
+// Build validates the rules and returns them in the form used by CustomClaimRules.
+// Each claim may only have a single rule.
+func (b *ClaimRulesBuilder) Build() (map[string]string, error) {
+	rules := make(map[string]string, len(b.rules))
+	var errs []error
+	for _, r := range b.rules {
+		if err := r.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, exists := rules[r.Claim]; exists {
+			errs = append(errs, fmt.Errorf("claim %q has more than one rule", r.Claim))
+			continue
+		}
+		rules[r.Claim] = r.Pattern()
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
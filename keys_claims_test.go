@@ -0,0 +1,95 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimRulesBuilder(t *testing.T) {
+	t.Parallel()
+
+	rules, err := NewClaimRules().
+		Equals("repository", "example/app").
+		HasPrefix("ref", "refs/heads/release-").
+		HasSuffix("email", "@example.com").
+		Contains("workflow", "deploy").
+		Present("environment").
+		Glob("job_workflow_ref", "example/*/deploy.yml@*").
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"repository":       "example/app",
+		"ref":              "refs/heads/release-*",
+		"email":            "*@example.com",
+		"workflow":         "*deploy*",
+		"environment":      "*",
+		"job_workflow_ref": "example/*/deploy.yml@*",
+	}, rules)
+
+	parsed, err := ParseClaimRules(rules)
+	assert.NoError(t, err)
+	assert.Equal(t, []ClaimRule{
+		{Claim: "email", Operator: ClaimMatchSuffix, Value: "@example.com"},
+		{Claim: "environment", Operator: ClaimMatchPresent},
+		{Claim: "job_workflow_ref", Operator: ClaimMatchGlob, Value: "example/*/deploy.yml@*"},
+		{Claim: "ref", Operator: ClaimMatchPrefix, Value: "refs/heads/release-"},
+		{Claim: "repository", Operator: ClaimMatchEquals, Value: "example/app"},
+		{Claim: "workflow", Operator: ClaimMatchContains, Value: "deploy"},
+	}, parsed)
+
+	// Building the parsed rules yields the original rules.
+	b := NewClaimRules()
+	for _, r := range parsed {
+		b.Rule(r)
+	}
+	roundTripped, err := b.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, rules, roundTripped)
+}
+
+func TestClaimRulesBuilder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*ClaimRulesBuilder{
+		"empty claim":       NewClaimRules().Equals("", "x"),
+		"whitespace claim":  NewClaimRules().Equals("my claim", "x"),
+		"empty value":       NewClaimRules().HasPrefix("ref", ""),
+		"wildcard in value": NewClaimRules().Equals("ref", "refs/*"),
+		"control character": NewClaimRules().Glob("ref", "refs/\n*"),
+		"unknown operator":  NewClaimRules().Rule(ClaimRule{Claim: "ref", Operator: "matches", Value: "x"}),
+		"duplicate claim":   NewClaimRules().Equals("ref", "a").HasPrefix("ref", "b"),
+	}
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			rules, err := b.Build()
+			assert.Error(t, err)
+			assert.Nil(t, rules)
+		})
+	}
+}
+
+func TestValidateClaimRules(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateClaimRules(nil))
+	assert.NoError(t, ValidateClaimRules(map[string]string{"sub": "repo:example/*"}))
+	err := ValidateClaimRules(map[string]string{"": "x", "ok": "y", "bad claim": ""})
+	assert.ErrorContains(t, err, `claim name must not be empty`)
+	assert.ErrorContains(t, err, `claim name "bad claim" must not contain whitespace`)
+}
+
+func TestClient_CreateFederatedIdentityInvalidClaimRules(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	_, err := client.Keys().CreateFederatedIdentity(context.Background(), CreateFederatedIdentityRequest{
+		CustomClaimRules: map[string]string{"": "x"},
+	})
+	assert.Error(t, err)
+	assert.Empty(t, server.Method)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultDeleteConcurrency is the number of keys deleted at once by [KeysResource.DeleteInvalid]
+// when [DeleteInvalidKeysOptions.Concurrency] is not set.
+const defaultDeleteConcurrency = 4
+
+// DeleteInvalidKeysOptions controls which keys [KeysResource.DeleteInvalid] deletes, and how.
+type DeleteInvalidKeysOptions struct {
+	// OlderThan, if non-zero, only deletes keys that were created at least this long ago.
+	OlderThan time.Duration
+	// Concurrency is the maximum number of delete requests in flight at once. Defaults to 4.
+	Concurrency int
+	// DryRun reports the keys that would be deleted without deleting them.
+	DryRun bool
+}
+
+// DeleteInvalid deletes the tailnet's keys that are invalid, revoked or expired, as reported by
+// [Key.IsValid], and returns the keys that were deleted, ordered as listed by the API. Keys that
+// are already gone by the time they are deleted are treated as deleted.
+//
+// A failure to delete one key does not stop the others from being deleted; the keys that were
+// deleted are returned along with the errors of those that were not.
+func (kr *KeysResource) DeleteInvalid(ctx context.Context, opts DeleteInvalidKeysOptions) ([]Key, error) {
+	keys, err := kr.listWithDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var candidates []Key
+	for _, k := range keys {
+		if k.isValidAt(now) {
+			continue
+		}
+		if opts.OlderThan > 0 && now.Sub(k.Created) < opts.OlderThan {
+			continue
+		}
+		candidates = append(candidates, k)
+	}
+	if opts.DryRun || len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDeleteConcurrency
+	}
+
+	errs := make([]error, len(candidates))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, k := range candidates {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := kr.Delete(ctx, k.ID); err != nil && !IsNotFound(err) {
+				errs[i] = fmt.Errorf("failed to delete key %s: %w", k.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var deleted []Key
+	for i, k := range candidates {
+		if errs[i] == nil {
+			deleted = append(deleted, k)
+		}
+	}
+	return deleted, errors.Join(errs...)
+}
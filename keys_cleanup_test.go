@@ -0,0 +1,118 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newKeyCleanupTestClient returns a client whose tailnet has a mix of valid and invalid keys,
+// along with the IDs of the keys deleted through it. Deleting "broken" fails.
+func newKeyCleanupTestClient(t *testing.T, inFlight *atomic.Int32, maxInFlight *atomic.Int32) (*Client, func() []string) {
+	now := time.Now().UTC()
+	keys := []Key{
+		{ID: "valid", KeyType: KeyTypeAuth, Created: now.Add(-100 * 24 * time.Hour), Expires: now.Add(time.Hour)},
+		{ID: "expired-old", KeyType: KeyTypeAuth, Created: now.Add(-100 * 24 * time.Hour), Expires: now.Add(-90 * 24 * time.Hour)},
+		{ID: "expired-new", KeyType: KeyTypeAuth, Created: now.Add(-2 * 24 * time.Hour), Expires: now.Add(-time.Hour)},
+		{ID: "revoked", KeyType: KeyTypeClient, Created: now.Add(-60 * 24 * time.Hour), Revoked: now.Add(-time.Hour)},
+		{ID: "invalid", KeyType: KeyTypeAuth, Created: now.Add(-60 * 24 * time.Hour), Invalid: true},
+		{ID: "gone", KeyType: KeyTypeAuth, Created: now.Add(-60 * 24 * time.Hour), Invalid: true},
+		{ID: "broken", KeyType: KeyTypeAuth, Created: now.Add(-60 * 24 * time.Hour), Invalid: true},
+	}
+
+	var mu sync.Mutex
+	var deleted []string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/api/v2/tailnet/example.com/keys", r.URL.Path)
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]Key{"keys": keys}))
+			return
+		}
+
+		assert.Equal(t, http.MethodDelete, r.Method)
+		if inFlight != nil {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/tailnet/example.com/keys/")
+		switch id {
+		case "gone":
+			w.WriteHeader(http.StatusNotFound)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "not found"}))
+			return
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "boom"}))
+			return
+		}
+		mu.Lock()
+		deleted = append(deleted, id)
+		mu.Unlock()
+	}))
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return deleted
+	}
+}
+
+func keyIDs(keys []Key) []string {
+	var ids []string
+	for _, k := range keys {
+		ids = append(ids, k.ID)
+	}
+	return ids
+}
+
+func TestClient_DeleteInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight atomic.Int32
+	client, deleted := newKeyCleanupTestClient(t, &inFlight, &maxInFlight)
+
+	keys, err := client.Keys().DeleteInvalid(context.Background(), DeleteInvalidKeysOptions{Concurrency: 2})
+	assert.ErrorContains(t, err, "failed to delete key broken")
+	assert.Equal(t, []string{"expired-old", "expired-new", "revoked", "invalid", "gone"}, keyIDs(keys))
+	assert.ElementsMatch(t, []string{"expired-old", "expired-new", "revoked", "invalid"}, deleted())
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestClient_DeleteInvalidKeysOlderThan(t *testing.T) {
+	t.Parallel()
+
+	client, deleted := newKeyCleanupTestClient(t, nil, nil)
+
+	keys, err := client.Keys().DeleteInvalid(context.Background(), DeleteInvalidKeysOptions{OlderThan: 90 * 24 * time.Hour})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"expired-old"}, keyIDs(keys))
+	assert.Equal(t, []string{"expired-old"}, deleted())
+}
+
+func TestClient_DeleteInvalidKeysDryRun(t *testing.T) {
+	t.Parallel()
+
+	client, deleted := newKeyCleanupTestClient(t, nil, nil)
+
+	keys, err := client.Keys().DeleteInvalid(context.Background(), DeleteInvalidKeysOptions{OlderThan: 30 * 24 * time.Hour, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"expired-old", "revoked", "invalid", "gone", "broken"}, keyIDs(keys))
+	assert.Empty(t, deleted())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Iter returns an iterator over the keys in the tailnet, accepting the same arguments as
+// [KeysResource.List]. As with List, all fields of the keys are requested whenever a filter is
+// given, since filters are applied to the keys' details. Keys are decoded from the response one
+// at a time as they are read, so tailnets with thousands of keys can be processed without holding
+// the whole list in memory.
+//
+// The request is made when iteration starts. If it fails, or a key cannot be decoded, the error
+// is yielded with a zero [Key] and iteration stops. Stopping iteration early closes the response.
+func (kr *KeysResource) Iter(ctx context.Context, all bool, opts ...ListKeysOptions) iter.Seq2[Key, error] {
+	return func(yield func(Key, error) bool) {
+		o := newListKeysOptions(opts)
+		req, err := kr.buildListRequest(ctx, all, o)
+		if err != nil {
+			yield(Key{}, err)
+			return
+		}
+
+		now := time.Now()
+		err = streamArray(kr.Client, req, "keys", func(k Key) error {
+			if o.filtered() && !o.matches(&k, now) {
+				return nil
+			}
+			if !yield(k, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil {
+			yield(Key{}, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_IterKeys(t *testing.T) {
+	t.Parallel()
+
+	client := newIDOnlyKeysTestClient(t, []Key{
+		{ID: "a", KeyType: KeyTypeAuth},
+		{ID: "b", KeyType: KeyTypeClient},
+		{ID: "c", KeyType: KeyTypeAuth},
+	})
+
+	var ids []string
+	for k, err := range client.Keys().Iter(context.Background(), true, WithKeyType(KeyTypeAuth)) {
+		assert.NoError(t, err)
+		assert.Equal(t, KeyTypeAuth, k.KeyType)
+		ids = append(ids, k.ID)
+	}
+	assert.Equal(t, []string{"a", "c"}, ids)
+
+	// Stopping early must not yield further keys or errors.
+	ids = nil
+	for k, err := range client.Keys().Iter(context.Background(), false) {
+		assert.NoError(t, err)
+		ids = append(ids, k.ID)
+		break
+	}
+	assert.Equal(t, []string{"a"}, ids)
+}
+
+func TestClient_IterKeysQuery(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{
+		"keys":  []Key{{ID: "a"}},
+		"extra": map[string]any{"ignored": true},
+	}
+
+	for _, err := range client.Keys().Iter(context.Background(), true) {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/keys", server.Path)
+	assert.Equal(t, url.Values{"all": {"true"}}, server.Query)
+
+	for _, err := range client.Keys().Iter(context.Background(), false, WithKeyValid(true)) {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, url.Values{"fields": {"all"}}, server.Query)
+}
+
+func TestClient_IterKeysError(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusForbidden
+	server.ResponseBody = APIError{Message: "forbidden"}
+
+	var errs []error
+	for _, err := range client.Keys().Iter(context.Background(), false) {
+		errs = append(errs, err)
+	}
+	assert.Len(t, errs, 1)
+	var apiErr APIError
+	assert.ErrorAs(t, errs[0], &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+	assert.Equal(t, "forbidden", apiErr.Message)
+}
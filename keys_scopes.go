@@ -0,0 +1,158 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// scopeAll grants every scope.
+	scopeAll = "all"
+	// scopeReadSuffix marks the read-only variant of a scope.
+	scopeReadSuffix = ":read"
+
+	scopeAuthKeys   = "auth_keys"
+	scopeOAuthKeys  = "oauth_keys"
+	scopePolicyRead = "policy_file:read"
+)
+
+// ScopeError is returned when the credential a client authenticates with lacks the scopes or tags
+// needed for a request. See [KeysResource.CheckCreateAuthKey] and [KeysResource.CheckCreateOAuthClient].
+type ScopeError struct {
+	// KeyID is the ID of the OAuth client or federated identity the client authenticates with.
+	KeyID string
+	// MissingScopes are the scopes that are needed but not granted to the credential.
+	MissingScopes []string
+	// UnownedTags are the requested tags that are neither held nor owned by the credential's tags.
+	UnownedTags []string
+}
+
+func (e *ScopeError) Error() string {
+	var problems []string
+	if len(e.MissingScopes) > 0 {
+		problems = append(problems, "missing scopes "+strings.Join(e.MissingScopes, ", "))
+	}
+	if len(e.UnownedTags) > 0 {
+		problems = append(problems, "cannot assign tags "+strings.Join(e.UnownedTags, ", "))
+	}
+	return fmt.Sprintf("credential %s is not permitted to make this request: %s", e.KeyID, strings.Join(problems, "; "))
+}
+
+// HasScope reports whether the key has been granted scope, either directly or through a broader
+// scope: "all" grants every scope, "all:read" grants every read-only scope, and each scope grants
+// its own read-only variant. Keys without scopes, such as API access tokens, are not limited by
+// scopes, so HasScope always reports true for them.
+func (k *Key) HasScope(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+	base, readOnly := strings.CutSuffix(scope, scopeReadSuffix)
+	for _, granted := range k.Scopes {
+		switch {
+		case granted == scope, granted == scopeAll:
+			return true
+		case readOnly && (granted == base || granted == scopeAll+scopeReadSuffix):
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCreateAuthKey checks that the key, as the credential of a client, is permitted to create the
+// auth key described by ckr, returning a [*ScopeError] if it is not. If acl is not nil, tags owned by
+// the key's tags according to its TagOwners are also permitted.
+func (k *Key) CheckCreateAuthKey(ckr CreateKeyRequest, acl *ACL) error {
+	return k.checkCreate([]string{scopeAuthKeys}, ckr.Capabilities.Devices.Create.Tags, acl)
+}
+
+// CheckCreateOAuthClient checks that the key, as the credential of a client, is permitted to create the
+// OAuth client described by ckr, returning a [*ScopeError] if it is not. An OAuth client may only be
+// granted scopes that the key has itself. If acl is not nil, tags owned by the key's tags according to
+// its TagOwners are also permitted.
+func (k *Key) CheckCreateOAuthClient(ckr CreateOAuthClientRequest, acl *ACL) error {
+	return k.checkCreate(append([]string{scopeOAuthKeys}, ckr.Scopes...), ckr.Tags, acl)
+}
+
+func (k *Key) checkCreate(scopes, tags []string, acl *ACL) error {
+	if !k.isScoped() {
+		return nil
+	}
+
+	e := &ScopeError{KeyID: k.ID}
+	for _, scope := range scopes {
+		if !k.HasScope(scope) && !slices.Contains(e.MissingScopes, scope) {
+			e.MissingScopes = append(e.MissingScopes, scope)
+		}
+	}
+	for _, tag := range tags {
+		if !k.ownsTag(tag, acl) {
+			e.UnownedTags = append(e.UnownedTags, tag)
+		}
+	}
+	if len(e.MissingScopes) > 0 || len(e.UnownedTags) > 0 {
+		return e
+	}
+	return nil
+}
+
+// isScoped reports whether the key is an OAuth client or federated identity, whose
+// permissions are limited to its scopes and tags.
+func (k *Key) isScoped() bool {
+	return k.KeyType == KeyTypeClient || k.KeyType == KeyTypeFederated
+}
+
+// ownsTag reports whether the key holds tag, or one of its tags owns tag according to acl.
+func (k *Key) ownsTag(tag string, acl *ACL) bool {
+	if slices.Contains(k.Tags, tag) {
+		return true
+	}
+	if acl == nil {
+		return false
+	}
+	return slices.ContainsFunc(k.Tags, func(owner string) bool { return acl.OwnsTag(owner, tag) })
+}
+
+// CheckCreateAuthKey checks, before calling [KeysResource.CreateAuthKey], that the credential the client
+// authenticates with is permitted to create the auth key described by ckr. This fails fast with a
+// [*ScopeError] naming the missing scopes and tags, rather than the API's generic 403.
+//
+// The credential is resolved with [KeysResource.GetCurrent]. If it has the policy_file:read scope, the
+// policy file's TagOwners are taken into account; otherwise only the credential's own tags are permitted.
+func (kr *KeysResource) CheckCreateAuthKey(ctx context.Context, ckr CreateKeyRequest) error {
+	current, acl, err := kr.currentWithPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	return current.CheckCreateAuthKey(ckr, acl)
+}
+
+// CheckCreateOAuthClient is like [KeysResource.CheckCreateAuthKey], for [KeysResource.CreateOAuthClient].
+func (kr *KeysResource) CheckCreateOAuthClient(ctx context.Context, ckr CreateOAuthClientRequest) error {
+	current, acl, err := kr.currentWithPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	return current.CheckCreateOAuthClient(ckr, acl)
+}
+
+// currentWithPolicy returns the key the client authenticates with and, if it is scoped
+// and may read it, the tailnet's policy file.
+func (kr *KeysResource) currentWithPolicy(ctx context.Context) (*Key, *ACL, error) {
+	current, err := kr.GetCurrent(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !current.isScoped() || len(current.Tags) == 0 || !current.HasScope(scopePolicyRead) {
+		return current, nil, nil
+	}
+	acl, err := kr.PolicyFile().Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, acl, nil
+}
@@ -6,10 +6,14 @@ package tailscale
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"slices"
 	"time"
 )
 
@@ -60,6 +64,8 @@ type LogstreamConfiguration struct {
 	S3AccessKeyID        string                `json:"s3AccessKeyId,omitempty"`
 	S3RoleARN            string                `json:"s3RoleArn,omitempty"`
 	S3ExternalID         string                `json:"s3ExternalId,omitempty"`
+	S3Endpoint           string                `json:"s3Endpoint,omitempty"`
+	S3ForcePathStyle     bool                  `json:"s3ForcePathStyle,omitempty"`
 	GCSBucket            string                `json:"gcsBucket,omitempty"`
 	GCSKeyPrefix         string                `json:"gcsKeyPrefix,omitempty"`
 	GCSScopes            []string              `json:"gcsScopes,omitzero"`
@@ -82,6 +88,8 @@ type SetLogstreamConfigurationRequest struct {
 	S3SecretAccessKey    string                `json:"s3SecretAccessKey,omitempty"`
 	S3RoleARN            string                `json:"s3RoleArn,omitempty"`
 	S3ExternalID         string                `json:"s3ExternalId,omitempty"`
+	S3Endpoint           string                `json:"s3Endpoint,omitempty"`
+	S3ForcePathStyle     bool                  `json:"s3ForcePathStyle,omitempty"`
 	GCSBucket            string                `json:"gcsBucket,omitempty"`
 	GCSKeyPrefix         string                `json:"gcsKeyPrefix,omitempty"`
 	GCSScopes            []string              `json:"gcsScopes,omitzero"`
@@ -130,6 +138,38 @@ func (lr *LoggingResource) DeleteLogstreamConfiguration(ctx context.Context, log
 	return lr.do(req, nil)
 }
 
+// LogstreamStatus describes how well a tailnet's logs are being streamed to their destination.
+type LogstreamStatus struct {
+	LastActivity       time.Time `json:"lastActivity"`       // when logs were last streamed
+	LastError          string    `json:"lastError"`          // the most recent error returned by the destination, if any
+	MaxBodySize        int64     `json:"maxBodySize"`        // the maximum size of a request body sent to the destination
+	NumBytesSent       int64     `json:"numBytesSent"`       // total bytes sent to the destination
+	NumEntriesSent     int64     `json:"numEntriesSent"`     // total log entries sent to the destination
+	NumSpoofedEntries  int64     `json:"numSpoofedEntries"`  // total log entries with spoofed data that were dropped
+	NumFailedRequests  int64     `json:"numFailedRequests"`  // total requests to the destination that failed
+	NumTotalRequests   int64     `json:"numTotalRequests"`   // total requests made to the destination
+	RateBytesSent      float64   `json:"rateBytesSent"`      // recent bytes sent per second
+	RateEntriesSent    float64   `json:"rateEntriesSent"`    // recent log entries sent per second
+	RateFailedRequests float64   `json:"rateFailedRequests"` // recent failed requests per second
+	RateTotalRequests  float64   `json:"rateTotalRequests"`  // recent requests per second
+}
+
+// Failing reports whether requests to the destination are currently failing.
+func (s *LogstreamStatus) Failing() bool {
+	return s.RateFailedRequests > 0
+}
+
+// LogstreamStatus retrieves the tailnet's [LogstreamStatus] for the given [LogType], so that failures
+// to deliver logs to the configured destination can be detected.
+func (lr *LoggingResource) LogstreamStatus(ctx context.Context, logType LogType) (*LogstreamStatus, error) {
+	req, err := lr.buildRequest(ctx, http.MethodGet, lr.buildTailnetURL("logging", logType, "stream", "status"))
+	if err != nil {
+		return nil, err
+	}
+
+	return body[LogstreamStatus](lr, req)
+}
+
 // AWSExternalID represents an AWS External ID that Tailscale can use to stream logs from a
 // particular Tailscale AWS account to a LogstreamS3Endpoint that uses S3RoleARNAuthentication.
 type AWSExternalID struct {
@@ -184,15 +224,95 @@ type TrafficStats struct {
 	TxBytes uint64 `json:"txBytes,omitempty"` // Transmitted bytes
 	RxPkts  uint64 `json:"rxPkts,omitempty"`  // Received packets
 	RxBytes uint64 `json:"rxBytes,omitempty"` // Received bytes
+
+	src, dst addrPortCache // parsed forms of Src and Dst
+}
+
+// SrcAddrPort returns Src parsed as an address and port. The result is cached until Src changes,
+// so it is not safe to call concurrently on the same TrafficStats.
+func (s *TrafficStats) SrcAddrPort() (netip.AddrPort, error) {
+	return s.src.parse(s.Src)
+}
+
+// DstAddrPort returns Dst parsed as an address and port. The result is cached until Dst changes,
+// so it is not safe to call concurrently on the same TrafficStats.
+func (s *TrafficStats) DstAddrPort() (netip.AddrPort, error) {
+	return s.dst.parse(s.Dst)
+}
+
+// addrPortCache caches the result of parsing an address and port.
+type addrPortCache struct {
+	raw      string
+	addrPort netip.AddrPort
+	err      error
+	parsed   bool
+}
+
+func (c *addrPortCache) parse(raw string) (netip.AddrPort, error) {
+	if !c.parsed || c.raw != raw {
+		ap, err := netip.ParseAddrPort(raw)
+		if err != nil {
+			err = fmt.Errorf("invalid traffic address %q: %w", raw, err)
+		}
+		*c = addrPortCache{raw: raw, addrPort: ap, err: err, parsed: true}
+	}
+	return c.addrPort, c.err
 }
 
 // NetworkFlowLogsRequest represents query parameters for fetching network flow logs.
 type NetworkFlowLogsRequest struct {
 	// Start must be set to a non-zero time within the log retention period (last 30 days).
-	// The server may adjust times that are too old.
 	Start time.Time
 	// End must be set to a non-zero time after Start.
 	End time.Time
+
+	// Checkpoint, if not nil, resumes a previous export from the point it records, and is
+	// updated as each log entry is processed, so that it can be saved and resumed from later.
+	Checkpoint *NetworkFlowLogCheckpoint
+	// MaxReconnects is the number of times to reconnect and resume from the last processed
+	// log entry if the connection drops, the server fails or requests are rate limited. If zero,
+	// the MaxRetries of the client's [RetryPolicy] is used, if any.
+	MaxReconnects int
+
+	// Filters, if not empty, are applied client-side as logs are streamed, so that only the
+	// traffic matching all of them is passed on. See [FlowLogFilter].
+	Filters []FlowLogFilter
+}
+
+// NetworkFlowLogCheckpoint records how far a network flow log export has progressed.
+// Logs are assumed to be returned in order of their Logged time.
+type NetworkFlowLogCheckpoint struct {
+	// Logged is the Logged time of the last processed log entry.
+	Logged time.Time `json:"logged"`
+	// Processed identifies the log entries at exactly Logged that have been processed,
+	// by their node ID and the start of their sample period.
+	Processed []string `json:"processed,omitempty"`
+}
+
+// processed reports whether log was already processed according to the checkpoint.
+func (c *NetworkFlowLogCheckpoint) processed(log *NetworkFlowLog) bool {
+	return log.Logged.Before(c.Logged) || (log.Logged.Equal(c.Logged) && slices.Contains(c.Processed, flowLogID(log)))
+}
+
+// clone returns a copy of the checkpoint that is not affected by later calls to record.
+func (c *NetworkFlowLogCheckpoint) clone() *NetworkFlowLogCheckpoint {
+	return &NetworkFlowLogCheckpoint{Logged: c.Logged, Processed: slices.Clone(c.Processed)}
+}
+
+// record advances the checkpoint past log.
+func (c *NetworkFlowLogCheckpoint) record(log *NetworkFlowLog) {
+	switch {
+	case log.Logged.After(c.Logged):
+		c.Logged = log.Logged
+		c.Processed = []string{flowLogID(log)}
+	case log.Logged.Equal(c.Logged):
+		c.Processed = append(c.Processed, flowLogID(log))
+	}
+}
+
+// flowLogID identifies a network flow log entry within those logged at the same time.
+func flowLogID(log *NetworkFlowLog) string {
+	return log.NodeID + "@" + log.Start.Format(time.RFC3339Nano)
 }
 
 // NetworkFlowLogHandler is a callback function for processing individual network flow log entries.
@@ -204,90 +324,302 @@ type NetworkFlowLogHandler func(log NetworkFlowLog) error
 // handler function for each log entry as it's parsed from the JSON response.
 // This approach is memory-efficient and handles large datasets without loading all logs into memory.
 //
-// Both start and end parameters are required by the server.
-// Times older than 30 days will be automatically adjusted by the server to the retention limit.
+// Both start and end parameters are required, and start must be within the 30 day retention
+// period. Invalid time windows are rejected before a request is made, see [NetworkFlowLogsRequest.Validate].
+//
+// Set params.Checkpoint to track progress and resume an interrupted export, and params.MaxReconnects
+// or [Client.RetryPolicy] to automatically reconnect if the connection drops. Log entries that were
+// already processed are not passed to handler again.
 func (lr *LoggingResource) GetNetworkFlowLogs(ctx context.Context, params NetworkFlowLogsRequest, handler NetworkFlowLogHandler) error {
+	return lr.streamNetworkFlowLogs(ctx, params, func(log NetworkFlowLog) error {
+		if err := handler(log); err != nil {
+			return handlerError{err}
+		}
+		return nil
+	})
+}
 
-	u := lr.buildTailnetURL("logging", "network")
-	u.RawQuery = url.Values{
-		"start": {params.Start.Format(time.RFC3339)},
-		"end":   {params.End.Format(time.RFC3339)},
-	}.Encode()
-
-	req, err := lr.buildRequest(ctx, http.MethodGet, u)
+// GetNetworkFlowLogsRaw returns the undecoded response body of the network flow logs within the
+// time window of params, for archiving log payloads verbatim or parsing them with a custom decoder.
+// The caller must close the returned body. The other fields of params are not used. Compressed
+// payloads are decompressed unless [Client.DisableLogDecompression] is set.
+func (lr *LoggingResource) GetNetworkFlowLogsRaw(ctx context.Context, params NetworkFlowLogsRequest) (io.ReadCloser, error) {
+	req, err := lr.buildNetworkFlowLogsRequest(ctx, params)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return lr.streamNetworkFlowLogs(req, handler)
+	return lr.doLogStream(req)
 }
 
-// checkDelim reads and verifies the next JSON delimiter from the decoder
-func checkDelim(dec *json.Decoder, want json.Delim, description string) error {
-	token, err := dec.Token()
-	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", description, err)
+// NetworkFlowLogs is like [LoggingResource.GetNetworkFlowLogs], but returns an iterator over the
+// logs for use with range-over-func pipelines. The request is made when iteration starts. If it
+// fails, the error is yielded with a zero [NetworkFlowLog] and iteration stops. Stopping iteration
+// early closes the response. Checkpoints and reconnection work as for GetNetworkFlowLogs.
+func (lr *LoggingResource) NetworkFlowLogs(ctx context.Context, params NetworkFlowLogsRequest) iter.Seq2[NetworkFlowLog, error] {
+	return func(yield func(NetworkFlowLog, error) bool) {
+		err := lr.streamNetworkFlowLogs(ctx, params, func(log NetworkFlowLog) error {
+			if !yield(log, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil {
+			yield(NetworkFlowLog{}, err)
+		}
 	}
-	if delim, ok := token.(json.Delim); !ok || delim != want {
-		return fmt.Errorf("expected %c for %s, got %v", want, description, token)
+}
+
+// buildNetworkFlowLogsRequest validates the time window of params and builds the request for the
+// network flow logs within it.
+func (lr *LoggingResource) buildNetworkFlowLogsRequest(ctx context.Context, params NetworkFlowLogsRequest) (*http.Request, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	return nil
+	return lr.buildNetworkFlowLogsWindowRequest(ctx, params)
 }
 
-// streamNetworkFlowLogs performs the streaming JSON parsing of network flow logs
-func (lr *LoggingResource) streamNetworkFlowLogs(req *http.Request, handler NetworkFlowLogHandler) error {
-	lr.init()
-	resp, err := lr.HTTP.Do(req)
-	if err != nil {
+// buildNetworkFlowLogsWindowRequest builds the request for the network flow logs within the time
+// window of params, without validating it.
+func (lr *LoggingResource) buildNetworkFlowLogsWindowRequest(ctx context.Context, params NetworkFlowLogsRequest) (*http.Request, error) {
+	u := lr.buildTailnetURL("logging", LogTypeNetwork)
+	u.RawQuery = url.Values{
+		"start": {params.Start.Format(time.RFC3339)},
+		"end":   {params.End.Format(time.RFC3339)},
+	}.Encode()
+
+	return lr.buildRequest(ctx, http.MethodGet, u)
+}
+
+// streamNetworkFlowLogs performs the streaming JSON parsing of network flow logs, reconnecting
+// and resuming from the last processed log entry as allowed by params.
+func (lr *LoggingResource) streamNetworkFlowLogs(ctx context.Context, params NetworkFlowLogsRequest, handle func(NetworkFlowLog) error) error {
+	checkpoint := params.Checkpoint
+	if checkpoint == nil {
+		checkpoint = &NetworkFlowLogCheckpoint{}
+	}
+	// Validate the window left after the checkpoint rather than params, so that a checkpoint can
+	// be resumed from after the original start has left the retention period.
+	remaining := params.resumeFrom(checkpoint)
+	if params.Start.Before(params.End) && !remaining.Start.Before(remaining.End) {
+		// Everything up to the end has already been processed.
+		return nil
+	}
+	if err := remaining.Validate(); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	filter := FlowLogAll(params.Filters...)
+	var policy RetryPolicy
+	if lr.RetryPolicy != nil {
+		policy = *lr.RetryPolicy
+	}
+	if params.MaxReconnects > 0 {
+		policy.MaxRetries = params.MaxReconnects
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Skip the entries already processed within the second that the window resumes from.
+		window := params.resumeFrom(checkpoint)
+		if !window.Start.Before(window.End) {
+			return nil
+		}
+		req, err := lr.buildNetworkFlowLogsWindowRequest(ctx, window)
+		if err != nil {
+			return err
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		// Only skip the entries processed before this attempt. Checking against the checkpoint
+		// as it is updated would drop entries that arrive out of Logged order.
+		seen := checkpoint.clone()
+		err = streamLogs(lr, req, func(log NetworkFlowLog) error {
+			if seen.processed(&log) {
+				return nil
+			}
+			if len(params.Filters) > 0 {
+				filtered, ok := filter.apply(log)
+				if !ok {
+					checkpoint.record(&log)
+					return nil
+				}
+				log = filtered
+			}
+			if err := handle(log); err != nil {
+				return err
+			}
+			checkpoint.record(&log)
+			return nil
+		})
+		if err == nil || attempt >= policy.MaxRetries || !isRetryableStreamError(ctx, err) {
+			return err
+		}
+		if err := policy.wait(ctx, attempt, err); err != nil {
+			return err
+		}
 	}
+}
 
-	decoder := json.NewDecoder(resp.Body)
+// handlerError wraps an error returned by a log handler, so that it is not mistaken for a
+// failure of the connection.
+type handlerError struct {
+	err error
+}
 
-	if err := checkDelim(decoder, '{', "opening brace"); err != nil {
-		return err
+func (e handlerError) Error() string {
+	return "handler error: " + e.err.Error()
+}
+
+func (e handlerError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableStreamError reports whether a streaming request that failed with err may succeed if
+// reconnected, as for [isRetryableError]. Errors returned by the handler are never retried.
+func isRetryableStreamError(ctx context.Context, err error) bool {
+	if errors.As(err, new(handlerError)) || errors.Is(err, ErrZstdLogPayload) {
+		return false
 	}
+	return isRetryableError(ctx, err)
+}
 
-	token, err := decoder.Token()
-	if err != nil {
-		return fmt.Errorf("failed to read field name: %w", err)
+// AuditLogAction describes the kind of change recorded by an [AuditLog].
+type AuditLogAction string
+
+const (
+	AuditLogActionCreate AuditLogAction = "CREATE"
+	AuditLogActionUpdate AuditLogAction = "UPDATE"
+	AuditLogActionDelete AuditLogAction = "DELETE"
+	AuditLogActionLogin  AuditLogAction = "LOGIN"
+	AuditLogActionLogout AuditLogAction = "LOGOUT"
+)
+
+// AuditLog represents a configuration audit log entry from the Tailscale API, recording a
+// single change made to the tailnet's configuration.
+type AuditLog struct {
+	EventGroupID  string          `json:"eventGroupID"`            // groups the entries caused by the same change
+	Origin        string          `json:"origin"`                  // where the change was made, e.g. "ADMIN_CONSOLE" or "API"
+	Actor         AuditLogActor   `json:"actor"`                   // who made the change
+	Target        AuditLogTarget  `json:"target"`                  // what was changed
+	Action        AuditLogAction  `json:"action"`                  // the kind of change
+	ActionDetails string          `json:"actionDetails,omitempty"` // a description of the change
+	EventTime     time.Time       `json:"eventTime"`               // when the change was made
+	Old           json.RawMessage `json:"old,omitempty"`           // the value before the change, if any
+	New           json.RawMessage `json:"new,omitempty"`           // the value after the change, if any
+	Error         string          `json:"error,omitempty"`         // the reason the change failed, if it did
+}
+
+// AuditLogActor describes who made the change recorded by an [AuditLog].
+type AuditLogActor struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // e.g. "USER" or "NODE"
+	LoginName   string `json:"loginName,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// AuditLogTarget describes what was changed by the change recorded by an [AuditLog].
+type AuditLogTarget struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type"`               // e.g. "NODE", "USER" or "TAILNET"
+	Property string `json:"property,omitempty"` // the property of the target that was changed, if any
+}
+
+// AuditLogsRequest represents query parameters for fetching configuration audit logs.
+// Start and End are applied by the server; the remaining fields filter the returned logs
+// client-side, and are ignored when empty.
+type AuditLogsRequest struct {
+	// Start must be set to a non-zero time.
+	Start time.Time
+	// End must be set to a non-zero time after Start.
+	End time.Time
+
+	// Actors only returns logs made by an actor whose ID or login name is listed.
+	Actors []string
+	// Actions only returns logs with one of the listed actions.
+	Actions []AuditLogAction
+	// TargetTypes only returns logs whose target has one of the listed types, e.g. "NODE".
+	TargetTypes []string
+	// TargetIDs only returns logs whose target has one of the listed IDs.
+	TargetIDs []string
+}
+
+// matches reports whether log passes the request's client-side filters.
+func (r *AuditLogsRequest) matches(log *AuditLog) bool {
+	if len(r.Actors) > 0 && !slices.Contains(r.Actors, log.Actor.ID) && !slices.Contains(r.Actors, log.Actor.LoginName) {
+		return false
 	}
-	if fieldName, ok := token.(string); !ok || fieldName != "logs" {
-		return fmt.Errorf("expected 'logs' field, got %v", token)
+	if len(r.Actions) > 0 && !slices.Contains(r.Actions, log.Action) {
+		return false
 	}
+	if len(r.TargetTypes) > 0 && !slices.Contains(r.TargetTypes, log.Target.Type) {
+		return false
+	}
+	if len(r.TargetIDs) > 0 && !slices.Contains(r.TargetIDs, log.Target.ID) {
+		return false
+	}
+	return true
+}
 
-	if err := checkDelim(decoder, '[', "logs array start"); err != nil {
+// AuditLogHandler is a callback function for processing individual configuration audit log entries.
+// It receives each log entry as it's parsed from the JSON stream.
+// Return an error to stop processing and bubble up the error.
+type AuditLogHandler func(log AuditLog) error
+
+// GetConfigurationAuditLogs streams the tailnet's configuration audit logs, calling the provided
+// handler function for each log entry that matches params as it's parsed from the JSON response.
+// Like [LoggingResource.GetNetworkFlowLogs], logs are not all loaded into memory at once.
+//
+// Both start and end parameters are required by the server.
+func (lr *LoggingResource) GetConfigurationAuditLogs(ctx context.Context, params AuditLogsRequest, handler AuditLogHandler) error {
+	req, err := lr.buildAuditLogsRequest(ctx, params)
+	if err != nil {
 		return err
 	}
 
-	for decoder.More() {
-		if err := req.Context().Err(); err != nil {
-			return err
-		}
-
-		var log NetworkFlowLog
-		if err := decoder.Decode(&log); err != nil {
-			return fmt.Errorf("failed to decode log entry: %w", err)
+	return streamLogs(lr, req, func(log AuditLog) error {
+		if !params.matches(&log) {
+			return nil
 		}
-
 		if err := handler(log); err != nil {
 			return fmt.Errorf("handler error: %w", err)
 		}
-	}
+		return nil
+	})
+}
 
-	if err := checkDelim(decoder, ']', "logs array end"); err != nil {
-		return err
-	}
+// ConfigurationAuditLogs is like [LoggingResource.GetConfigurationAuditLogs], but returns an iterator
+// over the matching logs, so that they can be consumed incrementally with a range loop. The request
+// is made when iteration starts. If it fails, the error is yielded with a zero [AuditLog] and
+// iteration stops. Stopping iteration early closes the response.
+func (lr *LoggingResource) ConfigurationAuditLogs(ctx context.Context, params AuditLogsRequest) iter.Seq2[AuditLog, error] {
+	return func(yield func(AuditLog, error) bool) {
+		req, err := lr.buildAuditLogsRequest(ctx, params)
+		if err != nil {
+			yield(AuditLog{}, err)
+			return
+		}
 
-	if err := checkDelim(decoder, '}', "closing brace"); err != nil {
-		return err
+		err = streamLogs(lr, req, func(log AuditLog) error {
+			if !params.matches(&log) {
+				return nil
+			}
+			if !yield(log, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil {
+			yield(AuditLog{}, err)
+		}
 	}
+}
+
+// buildAuditLogsRequest builds the request for the configuration audit logs within the time window of params.
+func (lr *LoggingResource) buildAuditLogsRequest(ctx context.Context, params AuditLogsRequest) (*http.Request, error) {
+	u := lr.buildTailnetURL("logging", LogTypeConfig)
+	u.RawQuery = url.Values{
+		"start": {params.Start.Format(time.RFC3339)},
+		"end":   {params.End.Format(time.RFC3339)},
+	}.Encode()
 
-	return nil
+	return lr.buildRequest(ctx, http.MethodGet, u)
 }
@@ -0,0 +1,157 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// TrafficTotals are the packet and byte counts summed over a set of [TrafficStats].
+type TrafficTotals struct {
+	TxPkts  uint64
+	TxBytes uint64
+	RxPkts  uint64
+	RxBytes uint64
+}
+
+// Bytes returns the total number of bytes transmitted and received.
+func (t TrafficTotals) Bytes() uint64 {
+	return t.TxBytes + t.RxBytes
+}
+
+// Packets returns the total number of packets transmitted and received.
+func (t TrafficTotals) Packets() uint64 {
+	return t.TxPkts + t.RxPkts
+}
+
+func (t *TrafficTotals) add(stats *TrafficStats) {
+	t.TxPkts += stats.TxPkts
+	t.TxBytes += stats.TxBytes
+	t.RxPkts += stats.RxPkts
+	t.RxBytes += stats.RxBytes
+}
+
+// Talker is a traffic source address along with the totals of the flows it was the source of.
+type Talker struct {
+	Addr string
+	TrafficTotals
+}
+
+// FlowLogAggregator sums the traffic of network flow logs as they are streamed, for building
+// traffic reports without storing the logs. Use [NewFlowLogAggregator] to create one and pass its
+// Add method as the handler to [LoggingResource.GetNetworkFlowLogs], or use
+// [LoggingResource.AggregateNetworkFlowLogs]. It is not safe for concurrent use.
+type FlowLogAggregator struct {
+	classes []TrafficClass
+
+	total      TrafficTotals
+	byNode     map[string]*TrafficTotals
+	byProtocol map[int]*TrafficTotals
+	bySource   map[string]*TrafficTotals
+}
+
+// NewFlowLogAggregator returns a [FlowLogAggregator] that sums the given classes of traffic. If no
+// classes are given, virtual, subnet and exit traffic are summed. Physical traffic is excluded by
+// default, as it carries the other classes of traffic and would count them twice.
+func NewFlowLogAggregator(classes ...TrafficClass) *FlowLogAggregator {
+	if len(classes) == 0 {
+		classes = []TrafficClass{TrafficClassVirtual, TrafficClassSubnet, TrafficClassExit}
+	}
+	return &FlowLogAggregator{
+		classes:    classes,
+		byNode:     make(map[string]*TrafficTotals),
+		byProtocol: make(map[int]*TrafficTotals),
+		bySource:   make(map[string]*TrafficTotals),
+	}
+}
+
+// Add adds the traffic of log to the totals. It always returns nil, and has the signature of a
+// [NetworkFlowLogHandler] so that it can be used as one.
+func (a *FlowLogAggregator) Add(log NetworkFlowLog) error {
+	for _, class := range a.classes {
+		var stats []TrafficStats
+		switch class {
+		case TrafficClassVirtual:
+			stats = log.VirtualTraffic
+		case TrafficClassSubnet:
+			stats = log.SubnetTraffic
+		case TrafficClassExit:
+			stats = log.ExitTraffic
+		case TrafficClassPhysical:
+			stats = log.PhysicalTraffic
+		}
+		for i := range stats {
+			s := &stats[i]
+			a.total.add(s)
+			totalsFor(a.byNode, log.NodeID).add(s)
+			totalsFor(a.byProtocol, s.Proto).add(s)
+			source := s.Src
+			if addr, ok := parseTrafficAddr(s.Src); ok {
+				source = addr.String()
+			}
+			totalsFor(a.bySource, source).add(s)
+		}
+	}
+	return nil
+}
+
+// Total returns the totals of all traffic added.
+func (a *FlowLogAggregator) Total() TrafficTotals {
+	return a.total
+}
+
+// NodeTotals returns the traffic totals of each node, keyed by node ID.
+func (a *FlowLogAggregator) NodeTotals() map[string]TrafficTotals {
+	return copyTotals(a.byNode)
+}
+
+// ProtocolTotals returns the traffic totals of each IP protocol, keyed by protocol number.
+func (a *FlowLogAggregator) ProtocolTotals() map[int]TrafficTotals {
+	return copyTotals(a.byProtocol)
+}
+
+// TopTalkers returns up to n source addresses with the most bytes transmitted and received,
+// ordered from most to fewest bytes. If n is not positive, every source address is returned.
+func (a *FlowLogAggregator) TopTalkers(n int) []Talker {
+	talkers := make([]Talker, 0, len(a.bySource))
+	for addr, t := range a.bySource {
+		talkers = append(talkers, Talker{Addr: addr, TrafficTotals: *t})
+	}
+	slices.SortFunc(talkers, func(x, y Talker) int {
+		return cmp.Or(cmp.Compare(y.Bytes(), x.Bytes()), cmp.Compare(x.Addr, y.Addr))
+	})
+	if n > 0 && len(talkers) > n {
+		talkers = talkers[:n]
+	}
+	return talkers
+}
+
+// AggregateNetworkFlowLogs streams the network flow logs selected by params into a new
+// [FlowLogAggregator] for the given classes of traffic, as described by [NewFlowLogAggregator].
+func (lr *LoggingResource) AggregateNetworkFlowLogs(ctx context.Context, params NetworkFlowLogsRequest, classes ...TrafficClass) (*FlowLogAggregator, error) {
+	a := NewFlowLogAggregator(classes...)
+	if err := lr.GetNetworkFlowLogs(ctx, params, a.Add); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func totalsFor[K comparable](m map[K]*TrafficTotals, key K) *TrafficTotals {
+	t, ok := m[key]
+	if !ok {
+		t = &TrafficTotals{}
+		m[key] = t
+	}
+	return t
+}
+
+func copyTotals[K comparable](m map[K]*TrafficTotals) map[K]TrafficTotals {
+	out := make(map[K]TrafficTotals, len(m))
+	for k, t := range m {
+		out[k] = *t
+	}
+	return out
+}
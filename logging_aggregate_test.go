@@ -0,0 +1,77 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_AggregateNetworkFlowLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{
+		{
+			Logged: now, NodeID: "node1",
+			VirtualTraffic: []TrafficStats{
+				{Proto: 6, Src: "100.64.0.1:1234", Dst: "100.64.0.2:443", TxPkts: 10, TxBytes: 1000, RxPkts: 5, RxBytes: 500},
+				{Proto: 17, Src: "100.64.0.1:5353", Dst: "100.64.0.3:53", TxPkts: 1, TxBytes: 100},
+			},
+			PhysicalTraffic: []TrafficStats{
+				{Proto: 17, Src: "192.168.1.1:41641", Dst: "203.0.113.1:41641", TxPkts: 11, TxBytes: 1200},
+			},
+		},
+		{
+			Logged: now, NodeID: "node2",
+			SubnetTraffic: []TrafficStats{
+				{Proto: 6, Src: "100.64.0.2:443", Dst: "10.0.0.5:22", TxPkts: 20, TxBytes: 3000},
+			},
+			ExitTraffic: []TrafficStats{
+				{Proto: 6, Src: "[fd7a:115c:a1e0::2]:1234", Dst: "[2001:db8::1]:443", RxPkts: 2, RxBytes: 50},
+			},
+		},
+	}}
+
+	a, err := client.Logging().AggregateNetworkFlowLogs(context.Background(), NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now})
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/network", server.Path)
+
+	assert.Equal(t, TrafficTotals{TxPkts: 31, TxBytes: 4100, RxPkts: 7, RxBytes: 550}, a.Total())
+	assert.Equal(t, uint64(4650), a.Total().Bytes())
+	assert.Equal(t, uint64(38), a.Total().Packets())
+	assert.Equal(t, map[string]TrafficTotals{
+		"node1": {TxPkts: 11, TxBytes: 1100, RxPkts: 5, RxBytes: 500},
+		"node2": {TxPkts: 20, TxBytes: 3000, RxPkts: 2, RxBytes: 50},
+	}, a.NodeTotals())
+	assert.Equal(t, map[int]TrafficTotals{
+		6:  {TxPkts: 30, TxBytes: 4000, RxPkts: 7, RxBytes: 550},
+		17: {TxPkts: 1, TxBytes: 100},
+	}, a.ProtocolTotals())
+	assert.Equal(t, []Talker{
+		{Addr: "100.64.0.2", TrafficTotals: TrafficTotals{TxPkts: 20, TxBytes: 3000}},
+		{Addr: "100.64.0.1", TrafficTotals: TrafficTotals{TxPkts: 11, TxBytes: 1100, RxPkts: 5, RxBytes: 500}},
+	}, a.TopTalkers(2))
+	assert.Len(t, a.TopTalkers(0), 3)
+}
+
+func TestFlowLogAggregator_Classes(t *testing.T) {
+	t.Parallel()
+
+	a := NewFlowLogAggregator(TrafficClassPhysical)
+	assert.NoError(t, a.Add(NetworkFlowLog{
+		NodeID:          "node1",
+		VirtualTraffic:  []TrafficStats{{Proto: 6, TxBytes: 100}},
+		PhysicalTraffic: []TrafficStats{{Proto: 17, TxBytes: 150}},
+	}))
+	assert.Equal(t, TrafficTotals{TxBytes: 150}, a.Total())
+	assert.Equal(t, map[int]TrafficTotals{17: {TxBytes: 150}}, a.ProtocolTotals())
+}
@@ -0,0 +1,201 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// AuditLogFormat is a format that configuration audit logs can be exported in by an [AuditLogExporter].
+type AuditLogFormat string
+
+const (
+	// AuditLogFormatJSONLines writes each [AuditLog] as a line of JSON, as returned by the API.
+	AuditLogFormatJSONLines AuditLogFormat = "jsonl"
+	// AuditLogFormatCEF writes each [AuditLog] as a line in the ArcSight Common Event Format.
+	AuditLogFormatCEF AuditLogFormat = "cef"
+	// AuditLogFormatLEEF writes each [AuditLog] as a line in the IBM QRadar Log Event Extended
+	// Format, version 1.0, with tab separated attributes.
+	AuditLogFormatLEEF AuditLogFormat = "leef"
+)
+
+const (
+	siemVendor  = "Tailscale"
+	siemProduct = "Tailscale"
+	siemVersion = "v2"
+)
+
+// AuditLogExporter writes configuration audit logs to an [io.Writer] in an [AuditLogFormat], for
+// ingestion by a SIEM such as Splunk or Elastic. Output is buffered, so call Flush once all logs
+// have been written. It is not safe for concurrent use.
+type AuditLogExporter struct {
+	format AuditLogFormat
+	buf    *bufio.Writer
+	json   *json.Encoder
+}
+
+// NewAuditLogExporter returns an [AuditLogExporter] that writes to w in the given format.
+func NewAuditLogExporter(w io.Writer, format AuditLogFormat) (*AuditLogExporter, error) {
+	switch format {
+	case AuditLogFormatJSONLines, AuditLogFormatCEF, AuditLogFormatLEEF:
+	default:
+		return nil, fmt.Errorf("unknown audit log format %q", format)
+	}
+	buf := bufio.NewWriter(w)
+	return &AuditLogExporter{format: format, buf: buf, json: json.NewEncoder(buf)}, nil
+}
+
+// Write writes log. It has the signature of an [AuditLogHandler] so that it can be used as one.
+func (e *AuditLogExporter) Write(log AuditLog) error {
+	var line string
+	switch e.format {
+	case AuditLogFormatJSONLines:
+		return e.json.Encode(log)
+	case AuditLogFormatCEF:
+		line = auditLogCEF(&log)
+	default:
+		line = auditLogLEEF(&log)
+	}
+	_, err := e.buf.WriteString(line + "\n")
+	return err
+}
+
+// Flush writes any buffered output to the underlying writer.
+func (e *AuditLogExporter) Flush() error {
+	return e.buf.Flush()
+}
+
+// ExportConfigurationAuditLogs streams the configuration audit logs selected by params to w in the
+// given format. See [AuditLogExporter].
+func (lr *LoggingResource) ExportConfigurationAuditLogs(ctx context.Context, params AuditLogsRequest, w io.Writer, format AuditLogFormat) error {
+	e, err := NewAuditLogExporter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := lr.GetConfigurationAuditLogs(ctx, params, e.Write); err != nil {
+		return errors.Join(err, e.Flush())
+	}
+	return e.Flush()
+}
+
+// auditLogField is a key and value of a CEF extension or LEEF attribute.
+type auditLogField struct {
+	key, value string
+}
+
+// auditLogFields returns the fields of log, keyed by the CEF extension keys that suit them.
+// Empty values are omitted.
+func auditLogFields(log *AuditLog) []auditLogField {
+	outcome := "success"
+	if log.Error != "" {
+		outcome = "failure"
+	}
+	all := []auditLogField{
+		{"rt", strconv.FormatInt(log.EventTime.UnixMilli(), 10)},
+		{"act", string(log.Action)},
+		{"suid", log.Actor.ID},
+		{"suser", log.Actor.LoginName},
+		{"cs1Label", "actorType"}, {"cs1", log.Actor.Type},
+		{"cs2Label", "targetType"}, {"cs2", log.Target.Type},
+		{"duid", log.Target.ID},
+		{"duser", log.Target.Name},
+		{"cs3Label", "targetProperty"}, {"cs3", log.Target.Property},
+		{"cs4Label", "origin"}, {"cs4", log.Origin},
+		{"cs5Label", "eventGroupId"}, {"cs5", log.EventGroupID},
+		{"outcome", outcome},
+		{"reason", log.Error},
+		{"msg", log.ActionDetails},
+	}
+	var fields []auditLogField
+	for i := 0; i < len(all); i++ {
+		f := all[i]
+		// Omit labels along with their empty values.
+		if strings.HasSuffix(f.key, "Label") && all[i+1].value == "" {
+			i++
+			continue
+		}
+		if f.value != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// auditLogName returns a short description of log.
+func auditLogName(log *AuditLog) string {
+	if log.ActionDetails != "" {
+		return log.ActionDetails
+	}
+	return strings.TrimSpace(string(log.Action) + " " + log.Target.Type)
+}
+
+// auditLogCEF formats log as a CEF event.
+func auditLogCEF(log *AuditLog) string {
+	severity := "3"
+	if log.Error != "" {
+		severity = "7"
+	}
+	header := []string{siemVendor, siemProduct, siemVersion, string(log.Action), auditLogName(log), severity}
+	for i, h := range header {
+		header[i] = cefHeaderEscaper.Replace(h)
+	}
+	var extensions []string
+	for _, f := range auditLogFields(log) {
+		extensions = append(extensions, f.key+"="+cefExtensionEscaper.Replace(f.value))
+	}
+	return "CEF:0|" + strings.Join(header, "|") + "|" + strings.Join(extensions, " ")
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// leefAttributes maps CEF extension keys to the LEEF attributes used in their place. Custom
+// string labels are dropped, as LEEF attributes are named after the labels instead.
+var leefAttributes = map[string]string{
+	"act":     "cat",
+	"suid":    "accountId",
+	"suser":   "usrName",
+	"duid":    "targetId",
+	"duser":   "targetName",
+	"outcome": "outcome",
+	"reason":  "reason",
+	"msg":     "msg",
+}
+
+// auditLogLEEF formats log as a LEEF 1.0 event.
+func auditLogLEEF(log *AuditLog) string {
+	header := []string{siemVendor, siemProduct, siemVersion, string(log.Action)}
+	for i, h := range header {
+		header[i] = leefEscaper.Replace(strings.ReplaceAll(h, "|", " "))
+	}
+	attributes := []string{
+		"devTime=" + log.EventTime.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		"devTimeFormat=yyyy-MM-dd'T'HH:mm:ss.SSSX",
+	}
+	var label string
+	for _, f := range auditLogFields(log) {
+		switch {
+		case f.key == "rt":
+		case strings.HasSuffix(f.key, "Label"):
+			label = f.value
+		case strings.HasPrefix(f.key, "cs"):
+			attributes = append(attributes, label+"="+leefEscaper.Replace(f.value))
+		default:
+			attributes = append(attributes, leefAttributes[f.key]+"="+leefEscaper.Replace(f.value))
+		}
+	}
+	return "LEEF:1.0|" + strings.Join(header, "|") + "|" + strings.Join(attributes, "\t")
+}
+
+// leefEscaper replaces the characters that would break up a LEEF event or its attributes.
+var leefEscaper = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
@@ -0,0 +1,79 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogExporter(t *testing.T) {
+	t.Parallel()
+
+	eventTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	logs := []AuditLog{
+		{
+			EventGroupID:  "group1",
+			Origin:        "ADMIN_CONSOLE",
+			Actor:         AuditLogActor{ID: "user1", Type: "USER", LoginName: "amelie@example.com"},
+			Target:        AuditLogTarget{ID: "node1", Name: "laptop", Type: "NODE", Property: "KEY_EXPIRY"},
+			Action:        AuditLogActionUpdate,
+			ActionDetails: "disabled key expiry | a=b",
+			EventTime:     eventTime,
+		},
+		{
+			Actor:     AuditLogActor{ID: "user1", Type: "USER"},
+			Target:    AuditLogTarget{ID: "example.com", Type: "TAILNET"},
+			Action:    AuditLogActionCreate,
+			EventTime: eventTime,
+			Error:     "permission\tdenied",
+		},
+	}
+
+	tests := map[AuditLogFormat]string{
+		AuditLogFormatJSONLines: `{"eventGroupID":"group1","origin":"ADMIN_CONSOLE","actor":{"id":"user1","type":"USER","loginName":"amelie@example.com"},"target":{"id":"node1","name":"laptop","type":"NODE","property":"KEY_EXPIRY"},"action":"UPDATE","actionDetails":"disabled key expiry | a=b","eventTime":"2025-01-02T03:04:05Z"}
+{"eventGroupID":"","origin":"","actor":{"id":"user1","type":"USER"},"target":{"id":"example.com","type":"TAILNET"},"action":"CREATE","eventTime":"2025-01-02T03:04:05Z","error":"permission\tdenied"}
+`,
+		AuditLogFormatCEF: `CEF:0|Tailscale|Tailscale|v2|UPDATE|disabled key expiry \| a=b|3|rt=1735787045000 act=UPDATE suid=user1 suser=amelie@example.com cs1Label=actorType cs1=USER cs2Label=targetType cs2=NODE duid=node1 duser=laptop cs3Label=targetProperty cs3=KEY_EXPIRY cs4Label=origin cs4=ADMIN_CONSOLE cs5Label=eventGroupId cs5=group1 outcome=success msg=disabled key expiry | a\=b
+CEF:0|Tailscale|Tailscale|v2|CREATE|CREATE TAILNET|7|rt=1735787045000 act=CREATE suid=user1 cs1Label=actorType cs1=USER cs2Label=targetType cs2=TAILNET duid=example.com outcome=failure reason=permission	denied
+`,
+		AuditLogFormatLEEF: "LEEF:1.0|Tailscale|Tailscale|v2|UPDATE|devTime=2025-01-02T03:04:05.000Z\tdevTimeFormat=yyyy-MM-dd'T'HH:mm:ss.SSSX\tcat=UPDATE\taccountId=user1\tusrName=amelie@example.com\tactorType=USER\ttargetType=NODE\ttargetId=node1\ttargetName=laptop\ttargetProperty=KEY_EXPIRY\torigin=ADMIN_CONSOLE\teventGroupId=group1\toutcome=success\tmsg=disabled key expiry | a=b\n" +
+			"LEEF:1.0|Tailscale|Tailscale|v2|CREATE|devTime=2025-01-02T03:04:05.000Z\tdevTimeFormat=yyyy-MM-dd'T'HH:mm:ss.SSSX\tcat=CREATE\taccountId=user1\tactorType=USER\ttargetType=TAILNET\ttargetId=example.com\toutcome=failure\treason=permission denied\n",
+	}
+	for format, want := range tests {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			e, err := NewAuditLogExporter(&buf, format)
+			assert.NoError(t, err)
+			for _, log := range logs {
+				assert.NoError(t, e.Write(log))
+			}
+			assert.NoError(t, e.Flush())
+			assert.Equal(t, want, buf.String())
+		})
+	}
+
+	_, err := NewAuditLogExporter(&bytes.Buffer{}, "syslog")
+	assert.EqualError(t, err, `unknown audit log format "syslog"`)
+}
+
+func TestClient_ExportConfigurationAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{"logs": []AuditLog{{Action: AuditLogActionDelete, Target: AuditLogTarget{Type: "NODE"}}}}
+
+	var buf bytes.Buffer
+	now := time.Now()
+	err := client.Logging().ExportConfigurationAuditLogs(context.Background(), AuditLogsRequest{Start: now.Add(-time.Hour), End: now}, &buf, AuditLogFormatCEF)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/configuration", server.Path)
+	assert.Contains(t, buf.String(), "CEF:0|Tailscale|Tailscale|v2|DELETE|DELETE NODE|3|")
+}
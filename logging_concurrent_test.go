@@ -0,0 +1,91 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetNetworkFlowLogsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	start := end.Add(-10 * time.Hour)
+	var logs []NetworkFlowLog
+	for i := range 40 {
+		logs = append(logs, NetworkFlowLog{Logged: start.Add(time.Duration(i) * 15 * time.Minute), NodeID: "node"})
+	}
+	logs = append(logs, NetworkFlowLog{Logged: end, NodeID: "node"})
+
+	var inFlight, maxInFlight atomic.Int32
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(t, "/api/v2/tailnet/example.com/logging/network", r.URL.Path)
+		from, err := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+		assert.NoError(t, err)
+		to, err := time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+		assert.NoError(t, err)
+
+		// Both ends of the window are inclusive, as with the API.
+		var matching []NetworkFlowLog
+		for _, log := range logs {
+			if !log.Logged.Before(from) && !log.Logged.After(to) {
+				matching = append(matching, log)
+			}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"logs": matching}))
+	}))
+
+	var actual []NetworkFlowLog
+	err := client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), NetworkFlowLogsRequest{
+		Start: start,
+		End:   end,
+	}, time.Hour, 3, func(log NetworkFlowLog) error {
+		actual = append(actual, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, logs, actual)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+}
+
+func TestClient_GetNetworkFlowLogsConcurrently_Error(t *testing.T) {
+	t.Parallel()
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == end.Add(-2*time.Hour).Format(time.RFC3339) {
+			w.WriteHeader(http.StatusBadRequest)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "bad window"}))
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"logs": []NetworkFlowLog{}}))
+	}))
+
+	err := client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), NetworkFlowLogsRequest{
+		Start: end.Add(-5 * time.Hour),
+		End:   end,
+	}, time.Hour, 2, func(NetworkFlowLog) error { return nil })
+	assert.ErrorContains(t, err, "bad window")
+
+	err = client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), NetworkFlowLogsRequest{}, 0, 2, nil)
+	assert.Error(t, err)
+}
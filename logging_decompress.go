@@ -0,0 +1,77 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ErrZstdLogPayload is returned when the API returns a zstd-compressed log payload, which the client
+// cannot decompress. Set [Client.DisableLogDecompression] and use
+// [LoggingResource.GetNetworkFlowLogsRaw] to receive such payloads as-is.
+var ErrZstdLogPayload = errors.New("zstd-compressed log payloads are not supported")
+
+// doLogStream is like [Client.doStream], but decompresses gzip-compressed payloads unless
+// [Client.DisableLogDecompression] is set. Compressed payloads are recognized by their magic
+// number, as the API may return log content in the tailnet's configured [CompressionFormat]
+// without a Content-Encoding header.
+func (lr *LoggingResource) doLogStream(req *http.Request) (io.ReadCloser, error) {
+	body, err := lr.doStream(req)
+	if err != nil || lr.DisableLogDecompression {
+		return body, err
+	}
+
+	br := bufio.NewReader(body)
+	// Peek returns an error for payloads shorter than a magic number, which are left as-is.
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			body.Close()
+			return nil, fmt.Errorf("failed to decompress gzip log payload: %w", err)
+		}
+		return &decompressedBody{Reader: zr, closers: []io.Closer{zr, body}}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		body.Close()
+		return nil, ErrZstdLogPayload
+	default:
+		return &decompressedBody{Reader: br, closers: []io.Closer{body}}, nil
+	}
+}
+
+// streamLogs is like [streamArray] for the "logs" array of a logging response, but
+// decompresses the response as described by [LoggingResource.doLogStream].
+func streamLogs[T any](lr *LoggingResource, req *http.Request, handle func(T) error) error {
+	body, err := lr.doLogStream(req)
+	if err != nil {
+		return err
+	}
+	return decodeArray(req.Context(), body, "logs", handle)
+}
+
+// decompressedBody reads from a decompressing Reader and closes it along with the underlying body.
+type decompressedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	var errs []error
+	for _, c := range b.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_LogDecompression(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	payload, err := json.Marshal(map[string]any{"logs": []NetworkFlowLog{{Logged: now, NodeID: "node1"}}})
+	assert.NoError(t, err)
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, err = zw.Write(payload)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = compressed.Bytes()
+	params := NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}
+
+	var logs []NetworkFlowLog
+	err = client.Logging().GetNetworkFlowLogs(context.Background(), params, func(log NetworkFlowLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []NetworkFlowLog{{Logged: now, NodeID: "node1"}}, logs)
+
+	body, err := client.Logging().GetNetworkFlowLogsRaw(context.Background(), params)
+	assert.NoError(t, err)
+	raw, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, payload, raw)
+
+	// Uncompressed payloads are passed through.
+	server.ResponseBody = payload
+	body, err = client.Logging().GetNetworkFlowLogsRaw(context.Background(), params)
+	assert.NoError(t, err)
+	raw, err = io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, payload, raw)
+
+	server.ResponseBody = []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}
+	params.MaxReconnects = 3
+	err = client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error { return nil })
+	assert.ErrorIs(t, err, ErrZstdLogPayload)
+
+	// With decompression disabled, compressed payloads are returned as-is.
+	client.DisableLogDecompression = true
+	server.ResponseBody = compressed.Bytes()
+	body, err = client.Logging().GetNetworkFlowLogsRaw(context.Background(), params)
+	assert.NoError(t, err)
+	raw, err = io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, compressed.Bytes(), raw)
+}
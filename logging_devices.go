@@ -0,0 +1,118 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// DeviceTrafficSummary summarizes the network flow logs of a single node.
+type DeviceTrafficSummary struct {
+	NodeID string
+	// Device is the device with NodeID, or nil if it is no longer in the device list.
+	Device *Device
+	// FirstSeen and LastSeen are the earliest Start and latest End of the node's log entries.
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Logs is the number of log entries of the node.
+	Logs int
+	// TrafficTotals are the totals of the node's virtual, subnet and exit traffic.
+	TrafficTotals
+	// Peers are the totals of the node's traffic to and from each remote address. Traffic is
+	// attributed to its destination address, or its source address if the destination is one of
+	// the device's own addresses.
+	Peers map[string]TrafficTotals
+}
+
+// deviceTrafficSummary accumulates a [DeviceTrafficSummary].
+type deviceTrafficSummary struct {
+	summary DeviceTrafficSummary
+	own     []string
+	peers   map[string]*TrafficTotals
+}
+
+// GetDeviceTrafficSummaries streams the network flow logs selected by params, grouping them by node,
+// and then calls handler with a summary of each node's traffic, from the most to the fewest bytes.
+// Summaries are joined against the device list, so that they include each device's hostname and user.
+// Only one summary per node is held in memory, not the logs themselves.
+func (lr *LoggingResource) GetDeviceTrafficSummaries(ctx context.Context, params NetworkFlowLogsRequest, handler func(DeviceTrafficSummary) error) error {
+	devices, err := lr.Devices().List(ctx)
+	if err != nil {
+		return err
+	}
+	byNodeID := make(map[string]*Device, len(devices))
+	for i := range devices {
+		byNodeID[devices[i].NodeID] = &devices[i]
+	}
+
+	summaries := make(map[string]*deviceTrafficSummary)
+	err = lr.GetNetworkFlowLogs(ctx, params, func(log NetworkFlowLog) error {
+		s, ok := summaries[log.NodeID]
+		if !ok {
+			s = &deviceTrafficSummary{
+				summary: DeviceTrafficSummary{NodeID: log.NodeID, Device: byNodeID[log.NodeID], FirstSeen: log.Start, LastSeen: log.End},
+				peers:   make(map[string]*TrafficTotals),
+			}
+			if s.summary.Device != nil {
+				s.own = s.summary.Device.Addresses
+			}
+			summaries[log.NodeID] = s
+		}
+		s.add(&log)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]DeviceTrafficSummary, 0, len(summaries))
+	for _, s := range summaries {
+		s.summary.Peers = copyTotals(s.peers)
+		sorted = append(sorted, s.summary)
+	}
+	slices.SortFunc(sorted, func(x, y DeviceTrafficSummary) int {
+		return cmp.Or(cmp.Compare(y.Bytes(), x.Bytes()), cmp.Compare(x.NodeID, y.NodeID))
+	})
+	for _, summary := range sorted {
+		if err := handler(summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *deviceTrafficSummary) add(log *NetworkFlowLog) {
+	s.summary.Logs++
+	if log.Start.Before(s.summary.FirstSeen) {
+		s.summary.FirstSeen = log.Start
+	}
+	if log.End.After(s.summary.LastSeen) {
+		s.summary.LastSeen = log.End
+	}
+	for _, stats := range [][]TrafficStats{log.VirtualTraffic, log.SubnetTraffic, log.ExitTraffic} {
+		for i := range stats {
+			st := &stats[i]
+			s.summary.TrafficTotals.add(st)
+			totalsFor(s.peers, s.peer(st)).add(st)
+		}
+	}
+}
+
+// peer returns the remote address of st.
+func (s *deviceTrafficSummary) peer(st *TrafficStats) string {
+	dst, src := st.Dst, st.Src
+	if addr, ok := parseTrafficAddr(dst); ok {
+		dst = addr.String()
+	}
+	if addr, ok := parseTrafficAddr(src); ok {
+		src = addr.String()
+	}
+	if slices.Contains(s.own, dst) {
+		return src
+	}
+	return dst
+}
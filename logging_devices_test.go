@@ -0,0 +1,66 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetDeviceTrafficSummaries(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/tailnet/example.com/devices":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"devices": []Device{
+				{NodeID: "node1", Hostname: "laptop", User: "amelie@example.com", Addresses: []string{"100.64.0.1"}},
+			}}))
+		case "/api/v2/tailnet/example.com/logging/network":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"logs": []NetworkFlowLog{
+				{NodeID: "node1", Start: now.Add(-2 * time.Minute), End: now.Add(-time.Minute), VirtualTraffic: []TrafficStats{
+					{Proto: 6, Src: "100.64.0.1:51234", Dst: "100.64.0.2:22", TxBytes: 100, RxBytes: 50},
+				}},
+				{NodeID: "node2", Start: now.Add(-2 * time.Minute), End: now.Add(-time.Minute), VirtualTraffic: []TrafficStats{
+					{Proto: 6, Src: "100.64.0.3:51234", Dst: "100.64.0.2:22", TxBytes: 10},
+				}},
+				{NodeID: "node1", Start: now.Add(-time.Minute), End: now, VirtualTraffic: []TrafficStats{
+					{Proto: 6, Src: "100.64.0.3:40000", Dst: "100.64.0.1:443", RxBytes: 25},
+				}},
+			}}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	var summaries []DeviceTrafficSummary
+	err := client.Logging().GetDeviceTrafficSummaries(context.Background(), NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}, func(s DeviceTrafficSummary) error {
+		summaries = append(summaries, s)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 2)
+
+	assert.Equal(t, "node1", summaries[0].NodeID)
+	assert.Equal(t, "laptop", summaries[0].Device.Hostname)
+	assert.Equal(t, "amelie@example.com", summaries[0].Device.User)
+	assert.Equal(t, 2, summaries[0].Logs)
+	assert.Equal(t, now.Add(-2*time.Minute), summaries[0].FirstSeen)
+	assert.Equal(t, now, summaries[0].LastSeen)
+	assert.Equal(t, TrafficTotals{TxBytes: 100, RxBytes: 75}, summaries[0].TrafficTotals)
+	assert.Equal(t, map[string]TrafficTotals{
+		"100.64.0.2": {TxBytes: 100, RxBytes: 50},
+		"100.64.0.3": {RxBytes: 25},
+	}, summaries[0].Peers)
+
+	assert.Equal(t, "node2", summaries[1].NodeID)
+	assert.Nil(t, summaries[1].Device)
+	assert.Equal(t, map[string]TrafficTotals{"100.64.0.2": {TxBytes: 10}}, summaries[1].Peers)
+}
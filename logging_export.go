@@ -0,0 +1,156 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// FlowLogFormat is a format that network flow logs can be exported in by a [FlowLogExporter].
+type FlowLogFormat string
+
+const (
+	// FlowLogFormatNDJSON writes each [NetworkFlowLog] as a line of JSON, as returned by the API.
+	FlowLogFormatNDJSON FlowLogFormat = "ndjson"
+	// FlowLogFormatCSV writes each [FlowLogRow] as a CSV record, after a header record.
+	FlowLogFormatCSV FlowLogFormat = "csv"
+	// FlowLogFormatRows writes each [FlowLogRow] as a line of JSON. The rows have a flat, fixed
+	// schema, which suits columnar formats such as Parquet.
+	FlowLogFormatRows FlowLogFormat = "rows"
+)
+
+// FlowLogRow is a single traffic record of a [NetworkFlowLog], flattened along with the
+// fields of the log entry it belongs to.
+type FlowLogRow struct {
+	Logged  time.Time    `json:"logged"`
+	NodeID  string       `json:"nodeId"`
+	Start   time.Time    `json:"start"`
+	End     time.Time    `json:"end"`
+	Class   TrafficClass `json:"class"`
+	Proto   int          `json:"proto"`
+	Src     string       `json:"src"`
+	Dst     string       `json:"dst"`
+	TxPkts  uint64       `json:"txPkts"`
+	TxBytes uint64       `json:"txBytes"`
+	RxPkts  uint64       `json:"rxPkts"`
+	RxBytes uint64       `json:"rxBytes"`
+}
+
+// flowLogRowHeader is the header record written by [FlowLogFormatCSV].
+var flowLogRowHeader = []string{"logged", "nodeId", "start", "end", "class", "proto", "src", "dst", "txPkts", "txBytes", "rxPkts", "rxBytes"}
+
+func (r *FlowLogRow) csvRecord() []string {
+	return []string{
+		r.Logged.Format(time.RFC3339Nano),
+		r.NodeID,
+		r.Start.Format(time.RFC3339Nano),
+		r.End.Format(time.RFC3339Nano),
+		string(r.Class),
+		strconv.Itoa(r.Proto),
+		r.Src,
+		r.Dst,
+		strconv.FormatUint(r.TxPkts, 10),
+		strconv.FormatUint(r.TxBytes, 10),
+		strconv.FormatUint(r.RxPkts, 10),
+		strconv.FormatUint(r.RxBytes, 10),
+	}
+}
+
+// FlattenNetworkFlowLog returns a [FlowLogRow] for each traffic record of log, in the order
+// virtual, subnet, exit and physical traffic. A log entry without traffic yields no rows.
+func FlattenNetworkFlowLog(log NetworkFlowLog) []FlowLogRow {
+	var rows []FlowLogRow
+	for _, traffic := range []struct {
+		class TrafficClass
+		stats []TrafficStats
+	}{
+		{TrafficClassVirtual, log.VirtualTraffic},
+		{TrafficClassSubnet, log.SubnetTraffic},
+		{TrafficClassExit, log.ExitTraffic},
+		{TrafficClassPhysical, log.PhysicalTraffic},
+	} {
+		for _, s := range traffic.stats {
+			rows = append(rows, FlowLogRow{
+				Logged:  log.Logged,
+				NodeID:  log.NodeID,
+				Start:   log.Start,
+				End:     log.End,
+				Class:   traffic.class,
+				Proto:   s.Proto,
+				Src:     s.Src,
+				Dst:     s.Dst,
+				TxPkts:  s.TxPkts,
+				TxBytes: s.TxBytes,
+				RxPkts:  s.RxPkts,
+				RxBytes: s.RxBytes,
+			})
+		}
+	}
+	return rows
+}
+
+// FlowLogExporter writes network flow logs to an [io.Writer] in a [FlowLogFormat]. Output is
+// buffered, so call Flush once all logs have been written. It is not safe for concurrent use.
+type FlowLogExporter struct {
+	format FlowLogFormat
+	out    *recordWriter
+}
+
+// NewFlowLogExporter returns a [FlowLogExporter] that writes to w in the given format.
+func NewFlowLogExporter(w io.Writer, format FlowLogFormat) (*FlowLogExporter, error) {
+	e := &FlowLogExporter{format: format}
+	switch format {
+	case FlowLogFormatNDJSON, FlowLogFormatRows:
+		e.out = newNDJSONWriter(w)
+	case FlowLogFormatCSV:
+		e.out = newCSVWriter(w, flowLogRowHeader)
+	default:
+		return nil, fmt.Errorf("unknown flow log format %q", format)
+	}
+	return e, nil
+}
+
+// Write writes log. It has the signature of a [NetworkFlowLogHandler] so that it can be used as one.
+func (e *FlowLogExporter) Write(log NetworkFlowLog) error {
+	if e.format == FlowLogFormatNDJSON {
+		return e.out.writeJSON(log)
+	}
+	for _, row := range FlattenNetworkFlowLog(log) {
+		var err error
+		if e.format == FlowLogFormatRows {
+			err = e.out.writeJSON(row)
+		} else {
+			err = e.out.writeCSV(row.csvRecord())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered output to the underlying writer. For [FlowLogFormatCSV], the
+// header record is written even if no logs were.
+func (e *FlowLogExporter) Flush() error {
+	return e.out.flush()
+}
+
+// ExportNetworkFlowLogs streams the network flow logs selected by params to w in the given format.
+// See [FlowLogExporter].
+func (lr *LoggingResource) ExportNetworkFlowLogs(ctx context.Context, params NetworkFlowLogsRequest, w io.Writer, format FlowLogFormat) error {
+	e, err := NewFlowLogExporter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := lr.GetNetworkFlowLogs(ctx, params, e.Write); err != nil {
+		// Write out what was exported before the failure, so that it can be resumed from.
+		return errors.Join(err, e.Flush())
+	}
+	return e.Flush()
+}
@@ -0,0 +1,86 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFlowLogs() []NetworkFlowLog {
+	logged := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []NetworkFlowLog{
+		{
+			Logged: logged,
+			NodeID: "node1",
+			Start:  logged.Add(-5 * time.Second),
+			End:    logged,
+			VirtualTraffic: []TrafficStats{
+				{Proto: 6, Src: "100.64.0.1:1234", Dst: "100.64.0.2:443", TxPkts: 10, TxBytes: 1000},
+			},
+			PhysicalTraffic: []TrafficStats{
+				{Proto: 17, Src: "192.168.1.1:41641", Dst: "203.0.113.1:41641", RxPkts: 2, RxBytes: 200},
+			},
+		},
+		{Logged: logged, NodeID: "node2", Start: logged.Add(-5 * time.Second), End: logged},
+	}
+}
+
+func TestFlowLogExporter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[FlowLogFormat]string{
+		FlowLogFormatNDJSON: `{"logged":"2025-01-02T03:04:05Z","nodeId":"node1","start":"2025-01-02T03:04:00Z","end":"2025-01-02T03:04:05Z","virtualTraffic":[{"proto":6,"src":"100.64.0.1:1234","dst":"100.64.0.2:443","txPkts":10,"txBytes":1000}],"physicalTraffic":[{"proto":17,"src":"192.168.1.1:41641","dst":"203.0.113.1:41641","rxPkts":2,"rxBytes":200}]}
+{"logged":"2025-01-02T03:04:05Z","nodeId":"node2","start":"2025-01-02T03:04:00Z","end":"2025-01-02T03:04:05Z"}
+`,
+		FlowLogFormatCSV: `logged,nodeId,start,end,class,proto,src,dst,txPkts,txBytes,rxPkts,rxBytes
+2025-01-02T03:04:05Z,node1,2025-01-02T03:04:00Z,2025-01-02T03:04:05Z,virtual,6,100.64.0.1:1234,100.64.0.2:443,10,1000,0,0
+2025-01-02T03:04:05Z,node1,2025-01-02T03:04:00Z,2025-01-02T03:04:05Z,physical,17,192.168.1.1:41641,203.0.113.1:41641,0,0,2,200
+`,
+		FlowLogFormatRows: `{"logged":"2025-01-02T03:04:05Z","nodeId":"node1","start":"2025-01-02T03:04:00Z","end":"2025-01-02T03:04:05Z","class":"virtual","proto":6,"src":"100.64.0.1:1234","dst":"100.64.0.2:443","txPkts":10,"txBytes":1000,"rxPkts":0,"rxBytes":0}
+{"logged":"2025-01-02T03:04:05Z","nodeId":"node1","start":"2025-01-02T03:04:00Z","end":"2025-01-02T03:04:05Z","class":"physical","proto":17,"src":"192.168.1.1:41641","dst":"203.0.113.1:41641","txPkts":0,"txBytes":0,"rxPkts":2,"rxBytes":200}
+`,
+	}
+	for format, want := range tests {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			e, err := NewFlowLogExporter(&buf, format)
+			assert.NoError(t, err)
+			for _, log := range testFlowLogs() {
+				assert.NoError(t, e.Write(log))
+			}
+			assert.NoError(t, e.Flush())
+			assert.Equal(t, want, buf.String())
+		})
+	}
+
+	_, err := NewFlowLogExporter(&bytes.Buffer{}, "parquet")
+	assert.EqualError(t, err, `unknown flow log format "parquet"`)
+}
+
+func TestClient_ExportNetworkFlowLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{"logs": testFlowLogs()}
+
+	var buf bytes.Buffer
+	err := client.Logging().ExportNetworkFlowLogs(context.Background(), NetworkFlowLogsLastHours(1), &buf, FlowLogFormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/network", server.Path)
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	// An empty CSV export still has a header.
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{}}
+	buf.Reset()
+	err = client.Logging().ExportNetworkFlowLogs(context.Background(), NetworkFlowLogsLastHours(1), &buf, FlowLogFormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, "logged,nodeId,start,end,class,proto,src,dst,txPkts,txBytes,rxPkts,rxBytes\n", buf.String())
+}
@@ -0,0 +1,129 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/netip"
+	"slices"
+)
+
+// TrafficClass identifies the kind of traffic recorded by a [TrafficStats] within a [NetworkFlowLog].
+type TrafficClass string
+
+const (
+	TrafficClassVirtual  TrafficClass = "virtual"  // [NetworkFlowLog.VirtualTraffic]
+	TrafficClassSubnet   TrafficClass = "subnet"   // [NetworkFlowLog.SubnetTraffic]
+	TrafficClassExit     TrafficClass = "exit"     // [NetworkFlowLog.ExitTraffic]
+	TrafficClassPhysical TrafficClass = "physical" // [NetworkFlowLog.PhysicalTraffic]
+)
+
+// FlowLogFilter selects the traffic of network flow logs to keep. It is called with each traffic
+// record of a log entry, along with the class of traffic it belongs to. For log entries without any
+// traffic records it is called once with an empty class and nil stats.
+//
+// Filters are set in [NetworkFlowLogsRequest.Filters] and applied while logs are streamed: records
+// that don't match are removed from each log entry, and entries left without any records are skipped.
+type FlowLogFilter func(log *NetworkFlowLog, class TrafficClass, stats *TrafficStats) bool
+
+// FlowLogNodes matches the traffic of the nodes with the given IDs.
+func FlowLogNodes(nodeIDs ...string) FlowLogFilter {
+	return func(log *NetworkFlowLog, _ TrafficClass, _ *TrafficStats) bool {
+		return slices.Contains(nodeIDs, log.NodeID)
+	}
+}
+
+// FlowLogProtocols matches traffic using one of the given IP protocol numbers, e.g. 6 for TCP.
+func FlowLogProtocols(protos ...int) FlowLogFilter {
+	return func(_ *NetworkFlowLog, _ TrafficClass, stats *TrafficStats) bool {
+		return stats != nil && slices.Contains(protos, stats.Proto)
+	}
+}
+
+// FlowLogPrefixes matches traffic whose source or destination address is within one of the given prefixes.
+func FlowLogPrefixes(prefixes ...netip.Prefix) FlowLogFilter {
+	return func(_ *NetworkFlowLog, _ TrafficClass, stats *TrafficStats) bool {
+		if stats == nil {
+			return false
+		}
+		for _, s := range []string{stats.Src, stats.Dst} {
+			addr, ok := parseTrafficAddr(s)
+			if ok && slices.ContainsFunc(prefixes, func(p netip.Prefix) bool { return p.Contains(addr) }) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FlowLogTrafficClasses matches traffic of the given classes.
+func FlowLogTrafficClasses(classes ...TrafficClass) FlowLogFilter {
+	return func(_ *NetworkFlowLog, class TrafficClass, _ *TrafficStats) bool {
+		return slices.Contains(classes, class)
+	}
+}
+
+// FlowLogAll matches traffic that matches every one of filters.
+func FlowLogAll(filters ...FlowLogFilter) FlowLogFilter {
+	return func(log *NetworkFlowLog, class TrafficClass, stats *TrafficStats) bool {
+		for _, f := range filters {
+			if !f(log, class, stats) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// FlowLogAny matches traffic that matches at least one of filters.
+func FlowLogAny(filters ...FlowLogFilter) FlowLogFilter {
+	return func(log *NetworkFlowLog, class TrafficClass, stats *TrafficStats) bool {
+		for _, f := range filters {
+			if f(log, class, stats) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FlowLogNot matches traffic that doesn't match filter.
+func FlowLogNot(filter FlowLogFilter) FlowLogFilter {
+	return func(log *NetworkFlowLog, class TrafficClass, stats *TrafficStats) bool {
+		return !filter(log, class, stats)
+	}
+}
+
+// apply returns log with only the traffic records that match f, reporting whether any remain.
+func (f FlowLogFilter) apply(log NetworkFlowLog) (NetworkFlowLog, bool) {
+	if len(log.VirtualTraffic)+len(log.SubnetTraffic)+len(log.ExitTraffic)+len(log.PhysicalTraffic) == 0 {
+		return log, f(&log, "", nil)
+	}
+
+	keep := func(class TrafficClass, stats []TrafficStats) []TrafficStats {
+		var kept []TrafficStats
+		for i := range stats {
+			if f(&log, class, &stats[i]) {
+				kept = append(kept, stats[i])
+			}
+		}
+		return kept
+	}
+	filtered := log
+	filtered.VirtualTraffic = keep(TrafficClassVirtual, log.VirtualTraffic)
+	filtered.SubnetTraffic = keep(TrafficClassSubnet, log.SubnetTraffic)
+	filtered.ExitTraffic = keep(TrafficClassExit, log.ExitTraffic)
+	filtered.PhysicalTraffic = keep(TrafficClassPhysical, log.PhysicalTraffic)
+	ok := len(filtered.VirtualTraffic)+len(filtered.SubnetTraffic)+len(filtered.ExitTraffic)+len(filtered.PhysicalTraffic) > 0
+	return filtered, ok
+}
+
+// parseTrafficAddr parses the address of a traffic source or destination, which is
+// usually an address and port such as "100.64.0.1:443" or "[fd7a:115c:a1e0::1]:443".
+func parseTrafficAddr(s string) (netip.Addr, bool) {
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr(), true
+	}
+	addr, err := netip.ParseAddr(s)
+	return addr, err == nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowLogFilter(t *testing.T) {
+	t.Parallel()
+
+	log := NetworkFlowLog{
+		NodeID: "node1",
+		VirtualTraffic: []TrafficStats{
+			{Proto: 6, Src: "100.64.0.1:1234", Dst: "100.64.0.2:443"},
+			{Proto: 17, Src: "100.64.0.1:1234", Dst: "100.64.0.3:53"},
+		},
+		SubnetTraffic: []TrafficStats{
+			{Proto: 6, Src: "100.64.0.1:1234", Dst: "10.0.0.5:22"},
+		},
+		ExitTraffic: []TrafficStats{
+			{Proto: 6, Src: "[fd7a:115c:a1e0::1]:1234", Dst: "[2001:db8::1]:443"},
+		},
+	}
+
+	tests := map[string]struct {
+		filter FlowLogFilter
+		want   NetworkFlowLog
+		ok     bool
+	}{
+		"node": {
+			filter: FlowLogNodes("node1"),
+			want:   log,
+			ok:     true,
+		},
+		"other node": {
+			filter: FlowLogNodes("node2"),
+		},
+		"protocol": {
+			filter: FlowLogProtocols(17),
+			want:   NetworkFlowLog{NodeID: "node1", VirtualTraffic: log.VirtualTraffic[1:2]},
+			ok:     true,
+		},
+		"prefix": {
+			filter: FlowLogPrefixes(netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")),
+			want:   NetworkFlowLog{NodeID: "node1", SubnetTraffic: log.SubnetTraffic, ExitTraffic: log.ExitTraffic},
+			ok:     true,
+		},
+		"class and protocol": {
+			filter: FlowLogAll(FlowLogTrafficClasses(TrafficClassVirtual, TrafficClassExit), FlowLogProtocols(6)),
+			want:   NetworkFlowLog{NodeID: "node1", VirtualTraffic: log.VirtualTraffic[:1], ExitTraffic: log.ExitTraffic},
+			ok:     true,
+		},
+		"any and not": {
+			filter: FlowLogAny(FlowLogTrafficClasses(TrafficClassSubnet), FlowLogNot(FlowLogProtocols(6))),
+			want:   NetworkFlowLog{NodeID: "node1", VirtualTraffic: log.VirtualTraffic[1:2], SubnetTraffic: log.SubnetTraffic},
+			ok:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := tt.filter.apply(log)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	// Entries without traffic only match filters that don't look at traffic.
+	empty := NetworkFlowLog{NodeID: "node1"}
+	_, ok := FlowLogNodes("node1").apply(empty)
+	assert.True(t, ok)
+	_, ok = FlowLogProtocols(6).apply(empty)
+	assert.False(t, ok)
+}
+
+func TestClient_GetNetworkFlowLogs_Filters(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{
+		{Logged: now, NodeID: "node1", Start: now.Add(-2 * time.Minute), VirtualTraffic: []TrafficStats{{Proto: 6}, {Proto: 17}}},
+		{Logged: now, NodeID: "node2", Start: now.Add(-2 * time.Minute), VirtualTraffic: []TrafficStats{{Proto: 6}}},
+		{Logged: now, NodeID: "node1", Start: now.Add(-time.Minute), PhysicalTraffic: []TrafficStats{{Proto: 17}}},
+	}}
+
+	checkpoint := &NetworkFlowLogCheckpoint{}
+	var actual []NetworkFlowLog
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), NetworkFlowLogsRequest{
+		Start:      now.Add(-time.Hour),
+		End:        now,
+		Checkpoint: checkpoint,
+		Filters:    []FlowLogFilter{FlowLogNodes("node1"), FlowLogProtocols(6)},
+	}, func(log NetworkFlowLog) error {
+		actual = append(actual, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []NetworkFlowLog{
+		{Logged: now, NodeID: "node1", Start: now.Add(-2 * time.Minute), VirtualTraffic: []TrafficStats{{Proto: 6}}},
+	}, actual)
+	// Entries that were filtered out still advance the checkpoint.
+	assert.Len(t, checkpoint.Processed, 3)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/netip"
+	"strconv"
+)
+
+// ipProtocolNames maps the IP protocol numbers commonly seen in network flow logs to their
+// IANA keywords, in lower case.
+var ipProtocolNames = map[int]string{
+	1:   "icmp",
+	2:   "igmp",
+	6:   "tcp",
+	17:  "udp",
+	41:  "ipv6",
+	47:  "gre",
+	50:  "esp",
+	51:  "ah",
+	58:  "ipv6-icmp",
+	89:  "ospf",
+	132: "sctp",
+}
+
+// IPProtocolName returns the name of the IP protocol with the given number, such as "tcp" for 6.
+// Protocols without a known name are returned as their number.
+func IPProtocolName(proto int) string {
+	if name, ok := ipProtocolNames[proto]; ok {
+		return name
+	}
+	return strconv.Itoa(proto)
+}
+
+// servicePort is a transport protocol and port that a well-known service listens on.
+type servicePort struct {
+	proto int
+	port  uint16
+}
+
+// wellKnownServices maps the ports of common services to their names.
+var wellKnownServices = map[servicePort]string{
+	{6, 21}:     "ftp",
+	{6, 22}:     "ssh",
+	{6, 23}:     "telnet",
+	{6, 25}:     "smtp",
+	{6, 53}:     "dns",
+	{17, 53}:    "dns",
+	{17, 67}:    "dhcp",
+	{17, 68}:    "dhcp",
+	{6, 80}:     "http",
+	{6, 110}:    "pop3",
+	{17, 123}:   "ntp",
+	{6, 143}:    "imap",
+	{17, 161}:   "snmp",
+	{6, 389}:    "ldap",
+	{6, 443}:    "https",
+	{17, 443}:   "quic",
+	{6, 445}:    "smb",
+	{17, 514}:   "syslog",
+	{6, 587}:    "submission",
+	{6, 636}:    "ldaps",
+	{6, 993}:    "imaps",
+	{6, 995}:    "pop3s",
+	{6, 2049}:   "nfs",
+	{6, 3306}:   "mysql",
+	{6, 3389}:   "rdp",
+	{17, 3478}:  "stun",
+	{6, 5432}:   "postgresql",
+	{6, 5900}:   "vnc",
+	{6, 6379}:   "redis",
+	{6, 6443}:   "kubernetes",
+	{6, 8080}:   "http-alt",
+	{6, 27017}:  "mongodb",
+	{17, 41641}: "tailscale",
+}
+
+// ProtoName returns the name of the IP protocol of the traffic, as described by [IPProtocolName].
+func (s *TrafficStats) ProtoName() string {
+	return IPProtocolName(s.Proto)
+}
+
+// Service returns the name of the well-known service, such as "ssh" or "https", that the traffic
+// was sent to or, failing that, sent from. It returns "" if neither port is well known.
+func (s *TrafficStats) Service() string {
+	for _, parse := range []func() (netip.AddrPort, error){s.DstAddrPort, s.SrcAddrPort} {
+		ap, err := parse()
+		if err != nil {
+			continue
+		}
+		if name, ok := wellKnownServices[servicePort{s.Proto, ap.Port()}]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// EnrichedTrafficStats is a [TrafficStats] along with human-readable names for its protocol and service.
+type EnrichedTrafficStats struct {
+	TrafficStats
+	ProtoName string `json:"protoName"`         // see [TrafficStats.ProtoName]
+	Service   string `json:"service,omitempty"` // see [TrafficStats.Service]
+}
+
+// EnrichTrafficStats returns stats with their protocols and services named, for generating
+// human-readable reports.
+func EnrichTrafficStats(stats []TrafficStats) []EnrichedTrafficStats {
+	enriched := make([]EnrichedTrafficStats, len(stats))
+	for i := range stats {
+		s := &stats[i]
+		enriched[i] = EnrichedTrafficStats{TrafficStats: *s, ProtoName: s.ProtoName(), Service: s.Service()}
+	}
+	return enriched
+}
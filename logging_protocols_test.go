@@ -0,0 +1,42 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficStats_ProtoNameAndService(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tcp", IPProtocolName(6))
+	assert.Equal(t, "253", IPProtocolName(253))
+
+	stats := []TrafficStats{
+		{Proto: 6, Src: "100.64.0.1:51234", Dst: "100.64.0.2:22", TxBytes: 10},
+		{Proto: 6, Src: "100.64.0.2:443", Dst: "100.64.0.1:51234"},
+		{Proto: 17, Src: "[fd7a:115c:a1e0::1]:41641", Dst: "[fd7a:115c:a1e0::2]:41641"},
+		{Proto: 17, Src: "100.64.0.1:50000", Dst: "100.64.0.2:22"},
+		{Proto: 1, Src: "100.64.0.1", Dst: "100.64.0.2"},
+	}
+	enriched := EnrichTrafficStats(stats)
+	var got [][2]string
+	for _, e := range enriched {
+		got = append(got, [2]string{e.ProtoName, e.Service})
+	}
+	assert.Equal(t, [][2]string{
+		{"tcp", "ssh"},
+		{"tcp", "https"},
+		{"udp", "tailscale"},
+		{"udp", ""},
+		{"icmp", ""},
+	}, got)
+
+	b, err := json.Marshal(enriched[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"proto":6,"src":"100.64.0.1:51234","dst":"100.64.0.2:22","txBytes":10,"protoName":"tcp","service":"ssh"}`, string(b))
+}
@@ -4,10 +4,13 @@
 package tailscale
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -33,6 +36,8 @@ func TestClient_LogstreamConfiguration(t *testing.T) {
 		S3AccessKeyID:        "my-access-key-id",
 		S3RoleARN:            "my-role-arn",
 		S3ExternalID:         "my-external-id",
+		S3Endpoint:           "https://minio.example.com:9000",
+		S3ForcePathStyle:     true,
 	}
 	server.ResponseBody = expectedLogstream
 
@@ -64,6 +69,8 @@ func TestClient_SetLogstreamConfiguration(t *testing.T) {
 		S3SecretAccessKey:    "my-secret-access-key",
 		S3RoleARN:            "my-role-arn",
 		S3ExternalID:         "my-external-id",
+		S3Endpoint:           "https://minio.example.com:9000",
+		S3ForcePathStyle:     true,
 	}
 	server.ResponseBody = nil
 
@@ -208,3 +215,382 @@ func TestClient_GetNetworkFlowLogs_HandlerError(t *testing.T) {
 }
 
 
+
+func TestClient_GetConfigurationAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expectedLogs := []AuditLog{
+		{
+			EventGroupID: "group1",
+			Origin:       "ADMIN_CONSOLE",
+			Actor:        AuditLogActor{ID: "u1", Type: "USER", LoginName: "alice@example.com"},
+			Target:       AuditLogTarget{ID: "n1", Name: "server", Type: "NODE", Property: "KEY_EXPIRY"},
+			Action:       AuditLogActionUpdate,
+			EventTime:    now,
+			Old:          json.RawMessage(`{"keyExpiryDisabled":false}`),
+			New:          json.RawMessage(`{"keyExpiryDisabled":true}`),
+		},
+		{
+			EventGroupID: "group2",
+			Origin:       "API",
+			Actor:        AuditLogActor{ID: "k1", Type: "USER"},
+			Target:       AuditLogTarget{ID: "example.com", Type: "TAILNET"},
+			Action:       AuditLogActionCreate,
+			EventTime:    now.Add(time.Second),
+		},
+	}
+	server.ResponseBody = map[string]any{
+		"version": "1.1",
+		"tailnet": "example.com",
+		"logs":    expectedLogs,
+	}
+
+	var actualLogs []AuditLog
+	err := client.Logging().GetConfigurationAuditLogs(context.Background(), AuditLogsRequest{
+		Start: now.Add(-time.Hour),
+		End:   now,
+	}, func(log AuditLog) error {
+		actualLogs = append(actualLogs, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/configuration", server.Path)
+	assert.Equal(t, now.Add(-time.Hour).Format(time.RFC3339), server.Query.Get("start"))
+	assert.Equal(t, now.Format(time.RFC3339), server.Query.Get("end"))
+	assert.Equal(t, expectedLogs, actualLogs)
+}
+
+func TestClient_GetConfigurationAuditLogs_APIError(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusForbidden
+	server.ResponseBody = APIError{Message: "forbidden"}
+
+	err := client.Logging().GetConfigurationAuditLogs(context.Background(), AuditLogsRequest{}, func(AuditLog) error {
+		t.Fatal("handler should not be called")
+		return nil
+	})
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+}
+
+func TestClient_ConfigurationAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	logs := []AuditLog{
+		{EventGroupID: "1", Actor: AuditLogActor{ID: "u1", LoginName: "alice@example.com"}, Target: AuditLogTarget{ID: "n1", Type: "NODE"}, Action: AuditLogActionUpdate, EventTime: now},
+		{EventGroupID: "2", Actor: AuditLogActor{ID: "u2", LoginName: "bob@example.com"}, Target: AuditLogTarget{ID: "n1", Type: "NODE"}, Action: AuditLogActionUpdate, EventTime: now},
+		{EventGroupID: "3", Actor: AuditLogActor{ID: "u1", LoginName: "alice@example.com"}, Target: AuditLogTarget{ID: "example.com", Type: "TAILNET"}, Action: AuditLogActionUpdate, EventTime: now},
+		{EventGroupID: "4", Actor: AuditLogActor{ID: "u1", LoginName: "alice@example.com"}, Target: AuditLogTarget{ID: "n2", Type: "NODE"}, Action: AuditLogActionDelete, EventTime: now},
+		{EventGroupID: "5", Actor: AuditLogActor{ID: "u1", LoginName: "alice@example.com"}, Target: AuditLogTarget{ID: "n3", Type: "NODE"}, Action: AuditLogActionUpdate, EventTime: now},
+	}
+	server.ResponseBody = map[string]any{"logs": logs}
+
+	params := AuditLogsRequest{
+		Start:       now.Add(-time.Hour),
+		End:         now,
+		Actors:      []string{"alice@example.com"},
+		Actions:     []AuditLogAction{AuditLogActionUpdate},
+		TargetTypes: []string{"NODE"},
+	}
+	var ids []string
+	for log, err := range client.Logging().ConfigurationAuditLogs(context.Background(), params) {
+		assert.NoError(t, err)
+		ids = append(ids, log.EventGroupID)
+	}
+	assert.Equal(t, []string{"1", "5"}, ids)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/configuration", server.Path)
+
+	// Filters apply to the handler API too.
+	params = AuditLogsRequest{Actors: []string{"u2"}}
+	ids = nil
+	err := client.Logging().GetConfigurationAuditLogs(context.Background(), params, func(log AuditLog) error {
+		ids = append(ids, log.EventGroupID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids)
+
+	// Stopping early yields nothing further.
+	params = AuditLogsRequest{TargetIDs: []string{"n1"}}
+	ids = nil
+	for log, err := range client.Logging().ConfigurationAuditLogs(context.Background(), params) {
+		assert.NoError(t, err)
+		ids = append(ids, log.EventGroupID)
+		break
+	}
+	assert.Equal(t, []string{"1"}, ids)
+}
+
+func TestClient_NetworkFlowLogs(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expectedLogs := []NetworkFlowLog{
+		{Logged: now, NodeID: "node1", Start: now.Add(-time.Minute), End: now},
+		{Logged: now.Add(time.Second), NodeID: "node2", Start: now.Add(-time.Minute), End: now},
+		{Logged: now.Add(2 * time.Second), NodeID: "node3", Start: now.Add(-time.Minute), End: now},
+	}
+	server.ResponseBody = map[string]any{"logs": expectedLogs}
+
+	params := NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}
+	var actualLogs []NetworkFlowLog
+	for log, err := range client.Logging().NetworkFlowLogs(context.Background(), params) {
+		assert.NoError(t, err)
+		actualLogs = append(actualLogs, log)
+	}
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/network", server.Path)
+	assert.Equal(t, expectedLogs, actualLogs)
+
+	actualLogs = nil
+	for log, err := range client.Logging().NetworkFlowLogs(context.Background(), params) {
+		assert.NoError(t, err)
+		actualLogs = append(actualLogs, log)
+		if len(actualLogs) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, expectedLogs[:2], actualLogs)
+}
+
+func TestClient_NetworkFlowLogs_Error(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusBadRequest
+	server.ResponseBody = APIError{Message: "start is required"}
+
+	var errs []error
+	for _, err := range client.Logging().NetworkFlowLogs(context.Background(), NetworkFlowLogsLastHours(1)) {
+		errs = append(errs, err)
+	}
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "start is required")
+}
+
+func TestClient_GetNetworkFlowLogs_Resume(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	logs := []NetworkFlowLog{
+		{Logged: now.Add(-3 * time.Minute), NodeID: "node1"},
+		{Logged: now.Add(-2*time.Minute + 500*time.Millisecond), NodeID: "node1"},
+		{Logged: now.Add(-2*time.Minute + 500*time.Millisecond), NodeID: "node2"},
+		{Logged: now.Add(-time.Minute), NodeID: "node3"},
+	}
+
+	var requests int
+	var starts []string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+		assert.NoError(t, err)
+		starts = append(starts, r.URL.Query().Get("start"))
+
+		var matching []NetworkFlowLog
+		for _, log := range logs {
+			if !log.Logged.Before(start) {
+				matching = append(matching, log)
+			}
+		}
+		b, err := json.Marshal(map[string]any{"logs": matching})
+		assert.NoError(t, err)
+		if requests == 1 {
+			// Drop the connection part way through the third entry.
+			cut := bytes.Index(b, []byte(`"node2"`))
+			b = b[:cut]
+		}
+		_, err = w.Write(b)
+		assert.NoError(t, err)
+	}))
+
+	checkpoint := &NetworkFlowLogCheckpoint{}
+	params := NetworkFlowLogsRequest{
+		Start:         now.Add(-time.Hour),
+		End:           now,
+		Checkpoint:    checkpoint,
+		MaxReconnects: 1,
+	}
+	var nodeIDs []string
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), params, func(log NetworkFlowLog) error {
+		nodeIDs = append(nodeIDs, log.NodeID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node1", "node1", "node2", "node3"}, nodeIDs)
+	assert.Equal(t, []string{
+		now.Add(-time.Hour).Format(time.RFC3339),
+		now.Add(-2 * time.Minute).Format(time.RFC3339),
+	}, starts)
+	assert.Equal(t, &NetworkFlowLogCheckpoint{Logged: logs[3].Logged, Processed: []string{"node3@0001-01-01T00:00:00Z"}}, checkpoint)
+
+	// Resuming from the saved checkpoint yields nothing new.
+	starts = nil
+	nodeIDs = nil
+	params.MaxReconnects = 0
+	err = client.Logging().GetNetworkFlowLogs(context.Background(), params, func(log NetworkFlowLog) error {
+		nodeIDs = append(nodeIDs, log.NodeID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, nodeIDs)
+	assert.Equal(t, []string{now.Add(-time.Minute).Format(time.RFC3339)}, starts)
+}
+
+func TestClient_GetNetworkFlowLogs_NoReconnectOnHandlerError(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"logs": []NetworkFlowLog{{NodeID: "node1"}},
+		}))
+	}))
+
+	params := NetworkFlowLogsLastHours(1)
+	params.MaxReconnects = 3
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error {
+		return fmt.Errorf("test handler error")
+	})
+	assert.ErrorContains(t, err, "handler error: test handler error")
+	assert.Equal(t, 1, requests)
+}
+
+func TestClient_GetNetworkFlowLogsRaw(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	now := time.Now().UTC().Truncate(time.Second)
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{{Logged: now, NodeID: "node1"}}}
+
+	body, err := client.Logging().GetNetworkFlowLogsRaw(context.Background(), NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now})
+	assert.NoError(t, err)
+	raw, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.NoError(t, body.Close())
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/network", server.Path)
+	assert.Equal(t, now.Add(-time.Hour).Format(time.RFC3339), server.Query.Get("start"))
+
+	var decoded map[string][]NetworkFlowLog
+	assert.NoError(t, json.Unmarshal(raw, &decoded))
+	assert.Equal(t, []NetworkFlowLog{{Logged: now, NodeID: "node1"}}, decoded["logs"])
+
+	server.ResponseCode = http.StatusForbidden
+	server.ResponseBody = APIError{Message: "forbidden"}
+	body, err = client.Logging().GetNetworkFlowLogsRaw(context.Background(), NetworkFlowLogsLastHours(1))
+	assert.Nil(t, body)
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+}
+
+func TestTrafficStats_AddrPort(t *testing.T) {
+	t.Parallel()
+
+	s := TrafficStats{Src: "100.64.0.1:1234", Dst: "[fd7a:115c:a1e0::1]:443"}
+	src, err := s.SrcAddrPort()
+	assert.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddrPort("100.64.0.1:1234"), src)
+	dst, err := s.DstAddrPort()
+	assert.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddrPort("[fd7a:115c:a1e0::1]:443"), dst)
+
+	// The cached result is refreshed when the address changes.
+	s.Src = "100.64.0.2:80"
+	src, err = s.SrcAddrPort()
+	assert.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddrPort("100.64.0.2:80"), src)
+
+	s.Dst = "not-an-address"
+	dst, err = s.DstAddrPort()
+	assert.EqualError(t, err, `invalid traffic address "not-an-address": not an ip:port`)
+	assert.False(t, dst.IsValid())
+
+	// Parsed addresses are not part of the JSON representation.
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"src":"100.64.0.2:80","dst":"not-an-address"}`, string(b))
+}
+
+func TestClient_LogstreamStatus(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	expected := &LogstreamStatus{
+		LastActivity:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		LastError:          "401 Unauthorized: invalid token",
+		NumEntriesSent:     1000,
+		NumFailedRequests:  3,
+		NumTotalRequests:   20,
+		RateFailedRequests: 0.5,
+	}
+	server.ResponseBody = expected
+
+	actual, err := client.Logging().LogstreamStatus(context.Background(), LogTypeNetwork)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/logging/network/stream/status", server.Path)
+	assert.Equal(t, expected, actual)
+	assert.True(t, actual.Failing())
+}
+
+func TestClient_GetNetworkFlowLogs_OutOfOrder(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	now := time.Now().UTC().Truncate(time.Second)
+	logs := []NetworkFlowLog{
+		{Logged: now.Add(-1 * time.Minute), NodeID: "node1"},
+		{Logged: now.Add(-2 * time.Minute), NodeID: "node2"},
+		{Logged: now.Add(-1 * time.Minute), NodeID: "node3"},
+	}
+	server.ResponseBody = map[string]any{"logs": logs}
+	params := NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}
+
+	var actual []NetworkFlowLog
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), params, func(log NetworkFlowLog) error {
+		actual = append(actual, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, logs, actual)
+
+	actual = nil
+	err = client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), params, time.Hour, 2, func(log NetworkFlowLog) error {
+		actual = append(actual, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, logs, actual)
+
+	// Entries covered by a checkpoint passed in are still skipped.
+	actual = nil
+	params.Checkpoint = &NetworkFlowLogCheckpoint{Logged: now.Add(-90 * time.Second)}
+	err = client.Logging().GetNetworkFlowLogs(context.Background(), params, func(log NetworkFlowLog) error {
+		actual = append(actual, log)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []NetworkFlowLog{logs[0], logs[2]}, actual)
+}
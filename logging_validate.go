@@ -0,0 +1,213 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LogstreamProblem is a single reason that a [SetLogstreamConfigurationRequest] is invalid.
+type LogstreamProblem struct {
+	// Field is the JSON name of the offending field, e.g. "s3Bucket".
+	Field string
+	// Reason describes what is wrong with the field.
+	Reason string
+}
+
+func (p LogstreamProblem) String() string {
+	return p.Field + ": " + p.Reason
+}
+
+// LogstreamValidationError is returned when a [SetLogstreamConfigurationRequest] is invalid.
+type LogstreamValidationError struct {
+	Problems []LogstreamProblem
+}
+
+func (e *LogstreamValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		problems[i] = p.String()
+	}
+	return "invalid log streaming configuration: " + strings.Join(problems, "; ")
+}
+
+// Validate checks that the request has everything its destination type needs, such as a URL and
+// token for HTTP destinations, or a bucket and credentials for S3 and GCS. It returns a
+// [*LogstreamValidationError] listing every problem found.
+func (r *SetLogstreamConfigurationRequest) Validate() error {
+	var problems []LogstreamProblem
+	problem := func(field, format string, args ...any) {
+		problems = append(problems, LogstreamProblem{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+	required := func(field, value string) {
+		if value == "" {
+			problem(field, "is required for %s destinations", r.DestinationType)
+		}
+	}
+
+	switch r.DestinationType {
+	case "":
+		problem("destinationType", "is required")
+	case LogstreamSplunkEndpoint, LogstreamElasticEndpoint, LogstreamPantherEndpoint,
+		LogstreamCriblEndpoint, LogstreamDatadogEndpoint, LogstreamAxiomEndpoint:
+		if r.URL == "" {
+			required("url", r.URL)
+		} else if !isHTTPURL(r.URL) {
+			problem("url", "%q is not an http or https URL", r.URL)
+		}
+		required("token", r.Token)
+	case LogstreamS3Endpoint:
+		required("s3Bucket", r.S3Bucket)
+		required("s3Region", r.S3Region)
+		if r.S3Endpoint != "" && !isHTTPURL(r.S3Endpoint) {
+			problem("s3Endpoint", "%q is not an http or https URL", r.S3Endpoint)
+		}
+		switch r.S3AuthenticationType {
+		case S3AccessKeyAuthentication:
+			required("s3AccessKeyId", r.S3AccessKeyID)
+			required("s3SecretAccessKey", r.S3SecretAccessKey)
+		case S3RoleARNAuthentication:
+			if r.S3RoleARN == "" {
+				required("s3RoleArn", r.S3RoleARN)
+			} else if !strings.HasPrefix(r.S3RoleARN, "arn:") {
+				problem("s3RoleArn", "%q is not an ARN", r.S3RoleARN)
+			}
+			required("s3ExternalId", r.S3ExternalID)
+		case "":
+			problem("s3AuthenticationType", "is required for %s destinations", r.DestinationType)
+		default:
+			problem("s3AuthenticationType", "unknown authentication type %q", r.S3AuthenticationType)
+		}
+	case LogstreamGCSEndpoint:
+		required("gcsBucket", r.GCSBucket)
+		required("gcsCredentials", r.GCSCredentials)
+		problems = append(problems, gcsProblems(r.GCSBucket, r.GCSCredentials)...)
+	default:
+		problem("destinationType", "unknown destination type %q", r.DestinationType)
+	}
+
+	switch r.CompressionFormat {
+	case "", CompressionFormatNone, CompressionFormatZstd, CompressionFormatGzip:
+	default:
+		problem("compressionFormat", "unknown compression format %q", r.CompressionFormat)
+	}
+	if r.UploadPeriodMinutes < 0 {
+		problem("uploadPeriodMinutes", "must not be negative")
+	}
+
+	if len(problems) > 0 {
+		return &LogstreamValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidateLogstreamConfiguration checks request before it is passed to
+// [LoggingResource.SetLogstreamConfiguration], so that misconfigured destinations are caught at
+// configuration time rather than silently dropping logs. The request is checked with
+// [SetLogstreamConfigurationRequest.Validate] and, for S3 destinations that use
+// [S3RoleARNAuthentication], the role's trust policy is checked with
+// [LoggingResource.ValidateAWSTrustPolicy]. Problems are returned as a [*LogstreamValidationError].
+// GCS destinations are checked with [ValidateGCSCredentials] as part of Validate.
+func (lr *LoggingResource) ValidateLogstreamConfiguration(ctx context.Context, request SetLogstreamConfigurationRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	if request.DestinationType != LogstreamS3Endpoint || request.S3AuthenticationType != S3RoleARNAuthentication {
+		return nil
+	}
+
+	err := lr.ValidateAWSTrustPolicy(ctx, request.S3ExternalID, request.S3RoleARN)
+	// Other client errors mean the role could not be assumed as configured; authentication,
+	// authorization and server errors say nothing about the configuration.
+	var apiErr APIError
+	if errors.As(err, &apiErr) && apiErr.Status < http.StatusInternalServerError &&
+		apiErr.Status != http.StatusUnauthorized && apiErr.Status != http.StatusForbidden {
+		return &LogstreamValidationError{Problems: []LogstreamProblem{
+			{Field: "s3RoleArn", Reason: "trust policy validation failed: " + apiErr.Message},
+		}}
+	}
+	return err
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// gcsServiceAccountKey holds the fields of a GCS service account key that are checked by
+// [ValidateGCSCredentials].
+type gcsServiceAccountKey struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+}
+
+// gcsBucketName matches valid GCS bucket names, apart from the length limits which depend on
+// whether the name contains dots.
+var gcsBucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*[a-z0-9]$`)
+
+// ValidateGCSCredentials checks, before they are passed to
+// [LoggingResource.SetLogstreamConfiguration], that bucket is a valid GCS bucket name and that
+// credentials is a JSON service account key with a usable private key. It mirrors
+// [LoggingResource.ValidateAWSTrustPolicy] for [LogstreamGCSEndpoint] destinations, but the
+// API has no endpoint for checking the account's access to the bucket, so that is not checked.
+// Problems are returned as a [*LogstreamValidationError].
+func ValidateGCSCredentials(bucket, credentials string) error {
+	if problems := gcsProblems(bucket, credentials); len(problems) > 0 {
+		return &LogstreamValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// gcsProblems returns the problems with a GCS bucket and credentials. Empty values are not
+// reported, so that callers can report them as required fields.
+func gcsProblems(bucket, credentials string) []LogstreamProblem {
+	var problems []LogstreamProblem
+	problem := func(field, format string, args ...any) {
+		problems = append(problems, LogstreamProblem{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	maxLen := 63
+	if strings.Contains(bucket, ".") {
+		maxLen = 222
+	}
+	if bucket != "" && (len(bucket) < 3 || len(bucket) > maxLen || !gcsBucketName.MatchString(bucket)) {
+		problem("gcsBucket", "%q is not a valid bucket name", bucket)
+	}
+
+	if credentials == "" {
+		return problems
+	}
+	var key gcsServiceAccountKey
+	if err := json.Unmarshal([]byte(credentials), &key); err != nil {
+		problem("gcsCredentials", "must be a JSON service account key")
+		return problems
+	}
+	if key.Type != "service_account" {
+		problem("gcsCredentials", "has type %q, not service_account", key.Type)
+	}
+	if key.ProjectID == "" {
+		problem("gcsCredentials", "is missing project_id")
+	}
+	if !strings.Contains(key.ClientEmail, "@") {
+		problem("gcsCredentials", "is missing a valid client_email")
+	}
+	if block, _ := pem.Decode([]byte(key.PrivateKey)); block == nil {
+		problem("gcsCredentials", "is missing a PEM encoded private_key")
+	} else if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		problem("gcsCredentials", "has an invalid private_key: %v", err)
+	}
+	return problems
+}
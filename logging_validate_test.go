@@ -0,0 +1,158 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogstreamConfigurationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		req  SetLogstreamConfigurationRequest
+		want []LogstreamProblem
+	}{
+		"valid splunk": {
+			req: SetLogstreamConfigurationRequest{DestinationType: LogstreamSplunkEndpoint, URL: "https://splunk.example.com", Token: "token"},
+		},
+		"splunk without token and bad url": {
+			req: SetLogstreamConfigurationRequest{DestinationType: LogstreamSplunkEndpoint, URL: "splunk.example.com"},
+			want: []LogstreamProblem{
+				{Field: "url", Reason: `"splunk.example.com" is not an http or https URL`},
+				{Field: "token", Reason: "is required for splunk destinations"},
+			},
+		},
+		"valid s3 access key": {
+			req: SetLogstreamConfigurationRequest{
+				DestinationType: LogstreamS3Endpoint, S3Bucket: "bucket", S3Region: "us-east-1",
+				S3AuthenticationType: S3AccessKeyAuthentication, S3AccessKeyID: "id", S3SecretAccessKey: "secret",
+			},
+		},
+		"s3 with invalid endpoint": {
+			req: SetLogstreamConfigurationRequest{
+				DestinationType: LogstreamS3Endpoint, S3Bucket: "bucket", S3Region: "us-east-1", S3Endpoint: "minio.example.com",
+				S3AuthenticationType: S3AccessKeyAuthentication, S3AccessKeyID: "id", S3SecretAccessKey: "secret",
+			},
+			want: []LogstreamProblem{
+				{Field: "s3Endpoint", Reason: `"minio.example.com" is not an http or https URL`},
+			},
+		},
+		"s3 role without external id": {
+			req: SetLogstreamConfigurationRequest{
+				DestinationType: LogstreamS3Endpoint, S3Bucket: "bucket",
+				S3AuthenticationType: S3RoleARNAuthentication, S3RoleARN: "role",
+			},
+			want: []LogstreamProblem{
+				{Field: "s3Region", Reason: "is required for s3 destinations"},
+				{Field: "s3RoleArn", Reason: `"role" is not an ARN`},
+				{Field: "s3ExternalId", Reason: "is required for s3 destinations"},
+			},
+		},
+		"gcs with invalid credentials": {
+			req: SetLogstreamConfigurationRequest{DestinationType: LogstreamGCSEndpoint, GCSBucket: "bucket", GCSCredentials: "not json"},
+			want: []LogstreamProblem{
+				{Field: "gcsCredentials", Reason: "must be a JSON service account key"},
+			},
+		},
+		"unknown destination and compression": {
+			req: SetLogstreamConfigurationRequest{DestinationType: "syslog", CompressionFormat: "lz4", UploadPeriodMinutes: -1},
+			want: []LogstreamProblem{
+				{Field: "destinationType", Reason: `unknown destination type "syslog"`},
+				{Field: "compressionFormat", Reason: `unknown compression format "lz4"`},
+				{Field: "uploadPeriodMinutes", Reason: "must not be negative"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *LogstreamValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Problems)
+		})
+	}
+
+	err := (&SetLogstreamConfigurationRequest{}).Validate()
+	assert.EqualError(t, err, "invalid log streaming configuration: destinationType: is required")
+}
+
+func TestClient_ValidateLogstreamConfiguration(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusBadRequest
+	server.ResponseBody = APIError{Message: "unable to assume role"}
+
+	req := SetLogstreamConfigurationRequest{
+		DestinationType:      LogstreamS3Endpoint,
+		S3Bucket:             "bucket",
+		S3Region:             "us-east-1",
+		S3AuthenticationType: S3RoleARNAuthentication,
+		S3RoleARN:            "arn:aws:iam::123456789012:role/tailscale",
+		S3ExternalID:         "external-id",
+	}
+	err := client.Logging().ValidateLogstreamConfiguration(context.Background(), req)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/aws-external-id/external-id/validate-aws-trust-policy", server.Path)
+	var validationErr *LogstreamValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []LogstreamProblem{
+		{Field: "s3RoleArn", Reason: "trust policy validation failed: unable to assume role"},
+	}, validationErr.Problems)
+
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = nil
+	assert.NoError(t, client.Logging().ValidateLogstreamConfiguration(context.Background(), req))
+
+	// Invalid requests are rejected without calling the API.
+	server.Method = ""
+	err = client.Logging().ValidateLogstreamConfiguration(context.Background(), SetLogstreamConfigurationRequest{DestinationType: LogstreamS3Endpoint})
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Empty(t, server.Method)
+}
+
+func TestValidateGCSCredentials(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "my-project",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email": "logs@my-project.iam.gserviceaccount.com",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ValidateGCSCredentials("my-bucket", string(credentials)))
+	assert.NoError(t, ValidateGCSCredentials("logs.example.com", string(credentials)))
+
+	err = ValidateGCSCredentials("My_Bucket-", `{"type": "authorized_user", "private_key": "not a key"}`)
+	var validationErr *LogstreamValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []LogstreamProblem{
+		{Field: "gcsBucket", Reason: `"My_Bucket-" is not a valid bucket name`},
+		{Field: "gcsCredentials", Reason: `has type "authorized_user", not service_account`},
+		{Field: "gcsCredentials", Reason: "is missing project_id"},
+		{Field: "gcsCredentials", Reason: "is missing a valid client_email"},
+		{Field: "gcsCredentials", Reason: "is missing a PEM encoded private_key"},
+	}, validationErr.Problems)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// flowLogRetention is how long network flow logs are retained for.
+	flowLogRetention = 30 * 24 * time.Hour
+	// flowLogRetentionLeeway allows for the time between constructing a request at the start of
+	// the retention period and validating it, and for clock skew.
+	flowLogRetentionLeeway = time.Minute
+)
+
+// NetworkFlowLogsLastHours returns a [NetworkFlowLogsRequest] for the last n hours, up to now.
+func NetworkFlowLogsLastHours(n int) NetworkFlowLogsRequest {
+	end := time.Now().Truncate(time.Second)
+	return NetworkFlowLogsRequest{Start: end.Add(-time.Duration(n) * time.Hour), End: end}
+}
+
+// NetworkFlowLogsDay returns a [NetworkFlowLogsRequest] for the calendar day containing t, in the
+// location of t. If the day is today, the request ends now.
+func NetworkFlowLogsDay(t time.Time) NetworkFlowLogsRequest {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := start.AddDate(0, 0, 1)
+	if now := time.Now().Truncate(time.Second); end.After(now) {
+		end = now
+	}
+	return NetworkFlowLogsRequest{Start: start, End: end}
+}
+
+// Validate checks that the time window of the request is accepted by the API: Start and End are
+// set, Start is before End, and Start is within the 30 day retention period.
+func (r NetworkFlowLogsRequest) Validate() error {
+	switch {
+	case r.Start.IsZero() && r.End.IsZero():
+		return errors.New("network flow logs request must have a start and end time")
+	case r.Start.IsZero():
+		return errors.New("network flow logs request must have a start time")
+	case r.End.IsZero():
+		return errors.New("network flow logs request must have an end time")
+	case !r.Start.Before(r.End):
+		return fmt.Errorf("network flow logs request start %s is not before end %s", r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+	}
+	if oldest := time.Now().Add(-flowLogRetention - flowLogRetentionLeeway); r.Start.Before(oldest) {
+		return fmt.Errorf("network flow logs request start %s is outside the 30 day retention period", r.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// resumeFrom returns the time window of r that is left after checkpoint. The API only accepts whole
+// seconds, so the window starts at the start of the second in which the checkpoint was logged.
+func (r NetworkFlowLogsRequest) resumeFrom(checkpoint *NetworkFlowLogCheckpoint) NetworkFlowLogsRequest {
+	if resume := checkpoint.Logged.Truncate(time.Second); resume.After(r.Start) {
+		r.Start = resume
+	}
+	return r
+}
@@ -0,0 +1,129 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkFlowLogsRequest_Windows(t *testing.T) {
+	t.Parallel()
+
+	r := NetworkFlowLogsLastHours(3)
+	assert.Equal(t, 3*time.Hour, r.End.Sub(r.Start))
+	assert.WithinDuration(t, time.Now(), r.End, time.Second)
+	assert.NoError(t, r.Validate())
+	assert.NoError(t, NetworkFlowLogsLastHours(30*24).Validate())
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	yesterday := time.Now().In(loc).AddDate(0, 0, -1)
+	r = NetworkFlowLogsDay(yesterday)
+	assert.Equal(t, time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, loc), r.Start)
+	assert.Equal(t, r.Start.AddDate(0, 0, 1), r.End)
+	assert.NoError(t, r.Validate())
+
+	r = NetworkFlowLogsDay(time.Now())
+	assert.WithinDuration(t, time.Now(), r.End, time.Second)
+}
+
+func TestNetworkFlowLogsRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	recent := time.Now().Truncate(time.Second)
+
+	tests := map[string]struct {
+		req     NetworkFlowLogsRequest
+		wantErr string
+	}{
+		"zero":        {req: NetworkFlowLogsRequest{}, wantErr: "network flow logs request must have a start and end time"},
+		"no start":    {req: NetworkFlowLogsRequest{End: recent}, wantErr: "network flow logs request must have a start time"},
+		"no end":      {req: NetworkFlowLogsRequest{Start: recent}, wantErr: "network flow logs request must have an end time"},
+		"empty":       {req: NetworkFlowLogsRequest{Start: now, End: now}, wantErr: "network flow logs request start 2025-01-02T03:04:05Z is not before end 2025-01-02T03:04:05Z"},
+		"too old":     {req: NetworkFlowLogsRequest{Start: now, End: recent}, wantErr: "network flow logs request start 2025-01-02T03:04:05Z is outside the 30 day retention period"},
+		"just inside": {req: NetworkFlowLogsRequest{Start: recent.Add(-flowLogRetention), End: recent}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Invalid windows are rejected before a request is made.
+	client, server := NewTestHarness(t)
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), NetworkFlowLogsRequest{Start: now, End: recent}, func(NetworkFlowLog) error { return nil })
+	assert.Error(t, err)
+	assert.Empty(t, server.Method)
+}
+
+func TestClient_GetNetworkFlowLogs_ResumeFinished(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{}}
+
+	params := NetworkFlowLogsLastHours(1)
+	for _, logged := range []time.Time{params.End, params.End.Add(time.Minute)} {
+		params.Checkpoint = &NetworkFlowLogCheckpoint{Logged: logged}
+		err := client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error {
+			t.Error("unexpected log")
+			return nil
+		})
+		assert.NoError(t, err)
+		err = client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), params, time.Minute, 2, func(NetworkFlowLog) error {
+			t.Error("unexpected log")
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, server.Method)
+
+	// The caller's window is still validated.
+	params = NetworkFlowLogsRequest{Start: params.End, End: params.Start, Checkpoint: &NetworkFlowLogCheckpoint{Logged: params.End}}
+	assert.Error(t, client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error { return nil }))
+}
+
+func TestClient_GetNetworkFlowLogs_ResumeAgedOut(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{"logs": []NetworkFlowLog{}}
+
+	// A long export that started before the retention period can still be resumed from a recent
+	// checkpoint, as only the remaining window is requested.
+	end := time.Now().UTC().Truncate(time.Second)
+	logged := end.Add(-time.Hour).Add(500 * time.Millisecond)
+	params := NetworkFlowLogsRequest{
+		Start:      end.Add(-40 * 24 * time.Hour),
+		End:        end,
+		Checkpoint: &NetworkFlowLogCheckpoint{Logged: logged},
+	}
+	assert.Error(t, NetworkFlowLogsRequest{Start: params.Start, End: params.End}.Validate())
+
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, logged.Truncate(time.Second).Format(time.RFC3339), server.Query.Get("start"))
+	assert.Equal(t, end.Format(time.RFC3339), server.Query.Get("end"))
+
+	server.Query = nil
+	err = client.Logging().GetNetworkFlowLogsConcurrently(context.Background(), params, time.Hour, 2, func(NetworkFlowLog) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, logged.Truncate(time.Second).Format(time.RFC3339), server.Query.Get("start"))
+
+	// Without a checkpoint, the aged-out start is rejected.
+	params.Checkpoint = nil
+	assert.Error(t, client.Logging().GetNetworkFlowLogs(context.Background(), params, func(NetworkFlowLog) error { return nil }))
+}
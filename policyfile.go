@@ -4,11 +4,19 @@
 package tailscale
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/tailscale/hujson"
 )
 
 // CheckPeriodAlways is a magic value corresponding to the [SSHCheckPeriod]
@@ -55,6 +63,86 @@ type PolicyFileResource struct {
 	*Client
 }
 
+// Actions of entries in the acls and ssh sections of a policy file, for use in [ACLEntry.Action]
+// and [ACLSSH.Action]. Entries in the acls section only support ACLActionAccept.
+const (
+	ACLActionAccept = "accept"
+	ACLActionCheck  = "check"
+)
+
+// IP protocols that may be matched by [ACLEntry.Protocol]. Protocols may also be given by their
+// IANA protocol number, e.g. "6" for TCP.
+// More details: https://tailscale.com/kb/1337/policy-syntax#proto
+const (
+	ACLProtocolIGMP     = "igmp"
+	ACLProtocolIPv4     = "ipv4"
+	ACLProtocolIPInIP   = "ip-in-ip"
+	ACLProtocolTCP      = "tcp"
+	ACLProtocolEGP      = "egp"
+	ACLProtocolIGP      = "igp"
+	ACLProtocolUDP      = "udp"
+	ACLProtocolGRE      = "gre"
+	ACLProtocolESP      = "esp"
+	ACLProtocolAH       = "ah"
+	ACLProtocolSCTP     = "sctp"
+	ACLProtocolICMP     = "icmp"
+	ACLProtocolIPv6ICMP = "ipv6-icmp"
+)
+
+const (
+	AutogroupMember       Autogroup = "autogroup:member"
+	AutogroupTagged       Autogroup = "autogroup:tagged"
+	AutogroupSelf         Autogroup = "autogroup:self"
+	AutogroupInternet     Autogroup = "autogroup:internet"
+	AutogroupShared       Autogroup = "autogroup:shared"
+	AutogroupNonRoot      Autogroup = "autogroup:nonroot"
+	AutogroupDangerAll    Autogroup = "autogroup:danger-all"
+	AutogroupOwner        Autogroup = "autogroup:owner"
+	AutogroupAdmin        Autogroup = "autogroup:admin"
+	AutogroupITAdmin      Autogroup = "autogroup:it-admin"
+	AutogroupNetworkAdmin Autogroup = "autogroup:network-admin"
+	AutogroupBillingAdmin Autogroup = "autogroup:billing-admin"
+	AutogroupAuditor      Autogroup = "autogroup:auditor"
+)
+
+// IsValidACLAction reports whether action is a known action of an entry in the acls or ssh
+// section of a policy file.
+func IsValidACLAction(action string) bool {
+	return action == ACLActionAccept || action == ACLActionCheck
+}
+
+// IsValidACLProtocol reports whether proto is a known protocol name or an IANA protocol number.
+func IsValidACLProtocol(proto string) bool {
+	switch proto {
+	case ACLProtocolIGMP, ACLProtocolIPv4, ACLProtocolIPInIP, ACLProtocolTCP, ACLProtocolEGP,
+		ACLProtocolIGP, ACLProtocolUDP, ACLProtocolGRE, ACLProtocolESP, ACLProtocolAH,
+		ACLProtocolSCTP, ACLProtocolICMP, ACLProtocolIPv6ICMP:
+		return true
+	}
+	n, err := strconv.Atoi(proto)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Autogroup is a built-in group that may be referenced from a policy file.
+// More details: https://tailscale.com/kb/1337/policy-syntax#autogroups
+type Autogroup string
+
+// IsValid reports whether g is a known autogroup.
+func (g Autogroup) IsValid() bool {
+	switch g {
+	case AutogroupMember, AutogroupTagged, AutogroupSelf, AutogroupInternet, AutogroupShared,
+		AutogroupNonRoot, AutogroupDangerAll, AutogroupOwner, AutogroupAdmin, AutogroupITAdmin,
+		AutogroupNetworkAdmin, AutogroupBillingAdmin, AutogroupAuditor:
+		return true
+	}
+	return false
+}
+
+// String returns g as a reference suitable for use in src, dst or users lists.
+func (g Autogroup) String() string {
+	return string(g)
+}
+
 // ACL contains the schema for a tailnet policy file. More details: https://tailscale.com/kb/1018/acls/
 type ACL struct {
 	ACLs                []ACLEntry          `json:"acls,omitempty" hujson:"ACLs,omitempty"`
@@ -70,10 +158,12 @@ type ACL struct {
 	OneCGNATRoute       string              `json:"oneCGNATRoute,omitempty" hujson:"OneCGNATRoute,omitempty"`
 	RandomizeClientPort bool                `json:"randomizeClientPort,omitempty" hujson:"RandomizeClientPort,omitempty"`
 	Grants              []Grant             `json:"grants,omitempty" hujson:"Grants,omitempty"`
-	IPSets              map[string][]string `json:"ipsets,omitempty" hujson:"IPSets,omitempty"`
+	// IPSets maps IP set names (e.g. "ipset:prod") to their entries. See [ParseIPSetEntry]
+	// and [ACL.IPSetEntries] for working with entries in typed form.
+	IPSets map[string][]string `json:"ipsets,omitempty" hujson:"IPSets,omitempty"`
 
-	Postures             map[string][]string `json:"postures,omitempty" hujson:"Postures,omitempty"`
-	DefaultSourcePosture []string            `json:"defaultSrcPosture,omitempty" hujson:"DefaultSrcPosture,omitempty"`
+	Postures             map[string][]PostureCondition `json:"postures,omitempty" hujson:"Postures,omitempty"`
+	DefaultSourcePosture []string                      `json:"defaultSrcPosture,omitempty" hujson:"DefaultSrcPosture,omitempty"`
 
 	// AttrConfig maps attribute names to their configuration for custom device attributes.
 	AttrConfig map[string]ACLAttrConfig `json:"attrConfig,omitempty" hujson:"AttrConfig,omitempty"`
@@ -91,9 +181,109 @@ type RawACL struct {
 	ETag string
 }
 
+// aclSectionOrder is the canonical order of top-level sections emitted by [ACL.MarshalHuJSON].
+// Sections not listed here are emitted afterwards in alphabetical order.
+var aclSectionOrder = []string{
+	"groups",
+	"hosts",
+	"ipsets",
+	"tagOwners",
+	"postures",
+	"defaultSrcPosture",
+	"attrConfig",
+	"acls",
+	"grants",
+	"ssh",
+	"nodeAttrs",
+	"autoApprovers",
+	"tests",
+	"derpMap",
+	"disableIPv4",
+	"oneCGNATRoute",
+	"randomizeClientPort",
+}
+
+// MarshalHuJSON returns acl as a consistently formatted HuJSON document, with top-level
+// sections in a canonical order and all map keys sorted. Marshaling the same policy
+// always produces the same output, which keeps diffs of generated policy files minimal.
+func (acl *ACL) MarshalHuJSON() ([]byte, error) {
+	b, err := json.MarshalIndent(acl, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	v, err := hujson.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	obj := v.Value.(*hujson.Object)
+	rank := func(m hujson.ObjectMember) (int, string) {
+		name := m.Name.Value.(hujson.Literal).String()
+		if i := slices.Index(aclSectionOrder, name); i != -1 {
+			return i, name
+		}
+		return len(aclSectionOrder), name
+	}
+	slices.SortStableFunc(obj.Members, func(a, b hujson.ObjectMember) int {
+		ai, an := rank(a)
+		bi, bn := rank(b)
+		if ai != bi {
+			return ai - bi
+		}
+		return strings.Compare(an, bn)
+	})
+	v.Format()
+	return v.Pack(), nil
+}
+
 type ACLAutoApprovers struct {
 	Routes   map[string][]string `json:"routes,omitempty" hujson:"Routes,omitempty"`
 	ExitNode []string            `json:"exitNode,omitempty" hujson:"ExitNode,omitempty"`
+	// Services maps service names (e.g. "svc:web") to the tags, groups and users whose
+	// devices are automatically approved to host them.
+	Services map[string][]string `json:"services,omitempty" hujson:"Services,omitempty"`
+}
+
+// ParsedRoutes returns Routes keyed by [netip.Prefix].
+func (a *ACLAutoApprovers) ParsedRoutes() (map[netip.Prefix][]string, error) {
+	routes := make(map[netip.Prefix][]string, len(a.Routes))
+	for route, approvers := range a.Routes {
+		prefix, err := netip.ParsePrefix(route)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route %q: %w", route, err)
+		}
+		routes[prefix] = approvers
+	}
+	return routes, nil
+}
+
+// SetRouteApprovers sets the approvers of route, replacing any existing approvers.
+func (a *ACLAutoApprovers) SetRouteApprovers(route netip.Prefix, approvers ...string) {
+	if a.Routes == nil {
+		a.Routes = make(map[string][]string)
+	}
+	a.Routes[route.Masked().String()] = approvers
+}
+
+// RouteApprovers returns the approvers of every route that contains route, i.e. everyone whose
+// devices may advertise route without manual approval. Keys of Routes that aren't valid
+// prefixes are ignored.
+func (a *ACLAutoApprovers) RouteApprovers(route netip.Prefix) []string {
+	var approvers []string
+	for r, as := range a.Routes {
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			continue
+		}
+		if prefix.Bits() <= route.Bits() && prefix.Contains(route.Addr()) {
+			for _, a := range as {
+				if !slices.Contains(approvers, a) {
+					approvers = append(approvers, a)
+				}
+			}
+		}
+	}
+	slices.Sort(approvers)
+	return approvers
 }
 
 type ACLEntry struct {
@@ -107,6 +297,8 @@ type ACLEntry struct {
 	SourcePosture []string `json:"srcPosture,omitempty" hujson:"SrcPosture,omitempty"`
 }
 
+// ACLTest is an entry in the tests section of a policy file. Tests are evaluated against
+// both the acls and grants sections. More details: https://tailscale.com/kb/1337/policy-syntax#tests
 type ACLTest struct {
 	User            string         `json:"user,omitempty" hujson:"User,omitempty"`
 	Allow           []string       `json:"allow,omitempty" hujson:"Allow,omitempty"`
@@ -114,6 +306,10 @@ type ACLTest struct {
 	Source          string         `json:"src,omitempty" hujson:"Src,omitempty"`
 	Accept          []string       `json:"accept,omitempty" hujson:"Accept,omitempty"`
 	SrcPostureAttrs map[string]any `json:"srcPostureAttrs,omitempty" hujson:"SrcPostureAttrs,omitempty"`
+	// Proto restricts the Accept and Deny destinations to the given IP protocol.
+	Proto string `json:"proto,omitempty" hujson:"Proto,omitempty"`
+	// SrcPosture lists postures (e.g. "posture:latestMac") that the source is assumed to satisfy.
+	SrcPosture []string `json:"srcPosture,omitempty" hujson:"SrcPosture,omitempty"`
 }
 
 type ACLDERPMap struct {
@@ -149,6 +345,30 @@ type ACLSSH struct {
 	CheckPeriod     SSHCheckPeriod `json:"checkPeriod,omitempty" hujson:"CheckPeriod,omitempty"`
 	Recorder        []string       `json:"recorder,omitempty" hujson:"Recorder,omitempty"`
 	EnforceRecorder bool           `json:"enforceRecorder,omitempty" hujson:"EnforceRecorder,omitempty"`
+	// AcceptEnv lists the environment variable names, which may contain * and ? wildcards,
+	// that clients are allowed to forward to the destination.
+	AcceptEnv []string `json:"acceptEnv,omitempty" hujson:"AcceptEnv,omitempty"`
+}
+
+// SSHRecorder identifies a session recorder that Tailscale SSH sessions are streamed to.
+// It is either a tag applied to recorder nodes (e.g. "tag:recorder") or the IP address and
+// port of a specific recorder (e.g. "100.64.0.1:80").
+// More details: https://tailscale.com/kb/1246/tailscale-ssh-session-recording
+type SSHRecorder string
+
+// Validate returns an error if r is neither a tag nor an IP address and port.
+// Entries of [ACLSSH.Recorder] can be checked with SSHRecorder(recorder).Validate().
+func (r SSHRecorder) Validate() error {
+	if strings.HasPrefix(string(r), "tag:") {
+		if len(r) == len("tag:") {
+			return fmt.Errorf("invalid recorder %q: empty tag name", string(r))
+		}
+		return nil
+	}
+	if _, err := netip.ParseAddrPort(string(r)); err != nil {
+		return fmt.Errorf("invalid recorder %q: must be a tag or an IP address and port", string(r))
+	}
+	return nil
 }
 
 type NodeAttrGrant struct {
@@ -165,13 +385,153 @@ type NodeAttrGrantApp struct {
 	Domains    []string `json:"domains,omitempty" hujson:"Domains,omitempty"`
 }
 
+// Grant is an entry in the grants section of a policy file, which grants access from
+// Source to Destination for the given IP protocols and ports, and/or application capabilities.
+// More details: https://tailscale.com/kb/1324/grants
 type Grant struct {
 	Source      []string                    `json:"src,omitempty" hujson:"Src,omitempty"`
 	Destination []string                    `json:"dst,omitempty" hujson:"Dst,omitempty"`
 	IP          []string                    `json:"ip,omitempty" hujson:"IP,omitempty"`
 	App         map[string][]map[string]any `json:"app,omitempty" hujson:"App,omitempty"`
-	SrcPosture  []string                    `json:"srcPosture,omitempty" hujson:"SrcPosture,omitempty"`
-	Via         []string                    `json:"via,omitempty" hujson:"Via,omitempty"`
+	// SrcPosture lists the device posture conditions (e.g. "posture:latestMac") that a
+	// source device must satisfy for the grant to apply.
+	SrcPosture []string `json:"srcPosture,omitempty" hujson:"SrcPosture,omitempty"`
+	// Via lists the tags of the exit nodes, subnet routers or app connectors that traffic
+	// matching this grant must be routed through.
+	Via []string `json:"via,omitempty" hujson:"Via,omitempty"`
+}
+
+// GrantAppCapabilities maps application capability names (e.g. "tailscale.com/cap/golink")
+// to their values, each as raw JSON.
+type GrantAppCapabilities map[string][]json.RawMessage
+
+// AppCapabilities returns the application capabilities of the grant as [GrantAppCapabilities].
+// The values are encoded from [Grant.App], so the keys of each object are sorted.
+func (g *Grant) AppCapabilities() (GrantAppCapabilities, error) {
+	if g.App == nil {
+		return nil, nil
+	}
+	caps := make(GrantAppCapabilities, len(g.App))
+	for name, values := range g.App {
+		raw := make([]json.RawMessage, 0, len(values))
+		for _, v := range values {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode app capability %q: %w", name, err)
+			}
+			raw = append(raw, b)
+		}
+		caps[name] = raw
+	}
+	return caps, nil
+}
+
+// SetAppCapabilities replaces the application capabilities of the grant with caps.
+// Each capability value must be a JSON object. Numbers are stored in [Grant.App] as
+// [json.Number], so that they are not rounded.
+func (g *Grant) SetAppCapabilities(caps GrantAppCapabilities) error {
+	if caps == nil {
+		g.App = nil
+		return nil
+	}
+	app := make(map[string][]map[string]any, len(caps))
+	for name, values := range caps {
+		decoded := make([]map[string]any, 0, len(values))
+		for _, v := range values {
+			var m map[string]any
+			decoder := json.NewDecoder(bytes.NewReader(v))
+			decoder.UseNumber()
+			if err := decoder.Decode(&m); err != nil {
+				return fmt.Errorf("app capability %q: value must be a JSON object: %w", name, err)
+			}
+			decoded = append(decoded, m)
+		}
+		app[name] = decoded
+	}
+	g.App = app
+	return nil
+}
+
+// DecodeAppCapability decodes every value of the named capability into a T.
+// Returns a nil slice if the capability is not present.
+func DecodeAppCapability[T any](caps GrantAppCapabilities, name string) ([]T, error) {
+	values := caps[name]
+	if values == nil {
+		return nil, nil
+	}
+	out := make([]T, 0, len(values))
+	for _, v := range values {
+		var t T
+		if err := json.Unmarshal(v, &t); err != nil {
+			return nil, fmt.Errorf("failed to decode app capability %q: %w", name, err)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+const (
+	IPSetAdd    IPSetOperation = "add"
+	IPSetRemove IPSetOperation = "remove"
+)
+
+// IPSetOperation is the operation applied by an [IPSetEntry].
+type IPSetOperation string
+
+// IPSetEntry is a single entry of an IP set in a policy file. Entries are applied in order,
+// adding or removing the addresses referenced by Value, which may be an IP address, CIDR range,
+// IP range, host alias or another IP set. More details: https://tailscale.com/kb/1387/ipsets
+type IPSetEntry struct {
+	Operation IPSetOperation
+	Value     string
+}
+
+// ParseIPSetEntry parses an IP set entry such as "192.0.2.0/24", "add 192.0.2.0/24" or
+// "remove ipset:legacy". Entries without an explicit operation are treated as [IPSetAdd].
+func ParseIPSetEntry(s string) (IPSetEntry, error) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 1:
+		return IPSetEntry{Operation: IPSetAdd, Value: fields[0]}, nil
+	case len(fields) == 2 && (fields[0] == string(IPSetAdd) || fields[0] == string(IPSetRemove)):
+		return IPSetEntry{Operation: IPSetOperation(fields[0]), Value: fields[1]}, nil
+	default:
+		return IPSetEntry{}, fmt.Errorf("invalid ipset entry %q", s)
+	}
+}
+
+// String returns the entry as it appears in a policy file.
+func (e IPSetEntry) String() string {
+	return string(e.Operation) + " " + e.Value
+}
+
+// IPSetEntries returns the parsed entries of the named IP set, or nil if it isn't defined.
+func (acl *ACL) IPSetEntries(name string) ([]IPSetEntry, error) {
+	raw, ok := acl.IPSets[name]
+	if !ok {
+		return nil, nil
+	}
+	entries := make([]IPSetEntry, 0, len(raw))
+	for _, r := range raw {
+		e, err := ParseIPSetEntry(r)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// SetIPSet defines the named IP set with the given entries, replacing any existing definition.
+func (acl *ACL) SetIPSet(name string, entries ...IPSetEntry) {
+	if acl.IPSets == nil {
+		acl.IPSets = make(map[string][]string)
+	}
+	raw := make([]string, 0, len(entries))
+	for _, e := range entries {
+		raw = append(raw, e.String())
+	}
+	acl.IPSets[name] = raw
 }
 
 // ACLAttrConfig represents configuration for a custom device attribute.
@@ -272,6 +632,87 @@ func (pr *PolicyFileResource) SetAndGet(ctx context.Context, acl ACL, etag strin
 	return out, nil
 }
 
+// SetRaw sets the tailnet's policy file to the HuJSON in acl. If acl.ETag is not empty, it is
+// used in the "If-Match" HTTP request header, so that the update fails if the policy file has
+// changed since it was retrieved with [PolicyFileResource.Raw].
+func (pr *PolicyFileResource) SetRaw(ctx context.Context, acl RawACL) error {
+	req, err := pr.buildSetRawRequest(ctx, acl)
+	if err != nil {
+		return err
+	}
+
+	return pr.do(req, nil)
+}
+
+// SetRawAndGet is like [PolicyFileResource.SetRaw], but returns the resulting policy file
+// as a [RawACL] with its new ETag.
+func (pr *PolicyFileResource) SetRawAndGet(ctx context.Context, acl RawACL) (*RawACL, error) {
+	req, err := pr.buildSetRawRequest(ctx, acl)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/hujson")
+
+	var resp []byte
+	header, err := pr.doWithResponseHeaders(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RawACL{
+		HuJSON: string(resp),
+		ETag:   header.Get("Etag"),
+	}, nil
+}
+
+func (pr *PolicyFileResource) buildSetRawRequest(ctx context.Context, acl RawACL) (*http.Request, error) {
+	headers := make(map[string]string)
+	if acl.ETag != "" {
+		headers["If-Match"] = fmt.Sprintf("%q", strings.Trim(acl.ETag, `"`))
+	}
+
+	return pr.buildRequest(ctx, http.MethodPost, pr.buildTailnetURL("acl"),
+		requestHeaders(headers),
+		requestBody(acl.HuJSON),
+		requestContentType("application/hujson"))
+}
+
+// defaultPolicyUpdateAttempts is the number of attempts made by [PolicyFileResource.Update].
+const defaultPolicyUpdateAttempts = 5
+
+// Update performs an ETag-protected read-modify-write of the tailnet's [ACL]. It gets the current
+// [ACL], calls mutate to modify it and sets the result using the ETag as "If-Match". If the policy
+// file was changed concurrently, the whole cycle is retried with fresh state, up to 5 attempts.
+// If mutate returns an error, Update stops and returns that error. Returns the resulting [ACL].
+func (pr *PolicyFileResource) Update(ctx context.Context, mutate func(acl *ACL) error) (*ACL, error) {
+	return pr.UpdateWithRetry(ctx, defaultPolicyUpdateAttempts, mutate)
+}
+
+// UpdateWithRetry is like [PolicyFileResource.Update], but makes up to maxAttempts attempts.
+func (pr *PolicyFileResource) UpdateWithRetry(ctx context.Context, maxAttempts int, mutate func(acl *ACL) error) (*ACL, error) {
+	var err error
+	for range max(maxAttempts, 1) {
+		var acl *ACL
+		acl, err = pr.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := mutate(acl); err != nil {
+			return nil, err
+		}
+
+		var out *ACL
+		out, err = pr.SetAndGet(ctx, *acl, acl.ETag)
+		if err == nil {
+			return out, nil
+		}
+		if !IsPreconditionFailed(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("policy file was modified concurrently, giving up after %d attempts: %w", max(maxAttempts, 1), err)
+}
+
 // Validate validates the provided ACL via the API. acl can either be an [ACL], or a HuJSON string.
 func (pr *PolicyFileResource) Validate(ctx context.Context, acl any) error {
 	reqOpts := []requestOption{
@@ -299,3 +740,84 @@ func (pr *PolicyFileResource) Validate(ctx context.Context, acl any) error {
 	}
 	return nil
 }
+
+// OwnsTag reports whether owner may apply tag according to the TagOwners section of the
+// policy. owner is a user login name, a group or a tag. Users are also matched through
+// their membership of any group listed as an owner of the tag.
+func (acl *ACL) OwnsTag(owner, tag string) bool {
+	for _, o := range acl.TagOwners[tag] {
+		if o == owner {
+			return true
+		}
+		if strings.HasPrefix(o, "group:") && slices.Contains(acl.Groups[o], owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// ACLTestFailure describes a single failed assertion of a policy file test.
+type ACLTestFailure struct {
+	// Source is the user or source of the test that failed, as reported by the API.
+	Source string
+	// Address is the destination address being asserted, e.g. "tag:prod:80". It is empty
+	// if the failure could not be parsed into an address, want and got.
+	Address string
+	// Want is the expected result of the assertion, e.g. "Accept".
+	Want string
+	// Got is the actual result of the assertion, e.g. "Drop".
+	Got string
+	// Message is the original failure message returned by the API.
+	Message string
+}
+
+// aclTestFailureRegexp matches failure messages like `address "tag:prod:80": want: Accept, got: Drop`.
+var aclTestFailureRegexp = regexp.MustCompile(`^address "([^"]*)": want: ([^,]*), got: (.*)$`)
+
+func parseACLTestFailures(data []APIErrorData) []ACLTestFailure {
+	var failures []ACLTestFailure
+	for _, d := range data {
+		for _, msg := range d.Errors {
+			failure := ACLTestFailure{Source: d.User, Message: msg}
+			if m := aclTestFailureRegexp.FindStringSubmatch(msg); m != nil {
+				failure.Address, failure.Want, failure.Got = m[1], m[2], m[3]
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// RunTests validates the provided ACL via the API and returns the failures of the tests it
+// contains. acl can either be an [ACL], or a HuJSON string. An empty result means every test
+// passed. The API reports failed tests in a successful response; any other error, for example
+// because the policy file is malformed, is returned unchanged.
+func (pr *PolicyFileResource) RunTests(ctx context.Context, acl any) ([]ACLTestFailure, error) {
+	reqOpts := []requestOption{
+		requestBody(acl),
+	}
+	switch v := acl.(type) {
+	case ACL:
+	case string:
+		reqOpts = append(reqOpts, requestContentType("application/hujson"))
+	default:
+		return nil, fmt.Errorf("expected ACL content as a string or as ACL struct; got %T", v)
+	}
+
+	req, err := pr.buildRequest(ctx, http.MethodPost, pr.buildTailnetURL("acl", "validate"), reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var response APIError
+	if err := pr.do(req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Data) > 0 {
+		return parseACLTestFailures(response.Data), nil
+	}
+	if response.Message != "" {
+		return nil, fmt.Errorf("ACL validation failed: %s", response.Message)
+	}
+	return nil, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AppConnectorsAttr is the key in [NodeAttrGrant.App] under which app connectors are configured.
+// More details: https://tailscale.com/kb/1281/app-connectors
+const AppConnectorsAttr = "tailscale.com/app-connectors"
+
+// NewAppConnectorNodeAttr returns a [NodeAttrGrant] that configures the given app connectors
+// for the devices matched by target.
+func NewAppConnectorNodeAttr(target []string, apps ...*NodeAttrGrantApp) NodeAttrGrant {
+	return NodeAttrGrant{
+		Target: target,
+		App:    map[string][]*NodeAttrGrantApp{AppConnectorsAttr: apps},
+	}
+}
+
+// AddAppConnector appends a nodeAttrs entry configuring app for the devices matched by target.
+func (b *ACLBuilder) AddAppConnector(target []string, app *NodeAttrGrantApp) *ACLBuilder {
+	b.acl.NodeAttrs = append(b.acl.NodeAttrs, NewAppConnectorNodeAttr(target, app))
+	return b
+}
+
+// AppConnectors returns every app connector configured in the nodeAttrs section, in policy order.
+func (acl *ACL) AppConnectors() []*NodeAttrGrantApp {
+	var apps []*NodeAttrGrantApp
+	for _, n := range acl.NodeAttrs {
+		apps = append(apps, n.App[AppConnectorsAttr]...)
+	}
+	return apps
+}
+
+// AppConnector returns the app connector with the given name, or nil if there is none.
+func (acl *ACL) AppConnector(name string) *NodeAttrGrantApp {
+	for _, app := range acl.AppConnectors() {
+		if app != nil && app.Name == name {
+			return app
+		}
+	}
+	return nil
+}
+
+// AppConnectorsForDomain returns the app connectors that route traffic for domain, taking
+// wildcard domains like "*.example.com" into account.
+func (acl *ACL) AppConnectorsForDomain(domain string) []*NodeAttrGrantApp {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	var apps []*NodeAttrGrantApp
+	for _, app := range acl.AppConnectors() {
+		if app == nil {
+			continue
+		}
+		for _, d := range app.Domains {
+			d = strings.ToLower(d)
+			if d == domain || (strings.HasPrefix(d, "*.") && strings.HasSuffix(domain, d[1:])) {
+				apps = append(apps, app)
+				break
+			}
+		}
+	}
+	return apps
+}
+
+// Validate returns an error if app has no name, no connectors, connectors that aren't tags,
+// or invalid domains.
+func (app *NodeAttrGrantApp) Validate() error {
+	return errors.Join(app.problems()...)
+}
+
+// problems returns each of the problems reported by [NodeAttrGrantApp.Validate].
+func (app *NodeAttrGrantApp) problems() []error {
+	var errs []error
+	if app.Name == "" {
+		errs = append(errs, errors.New("app connector has no name"))
+	}
+	if len(app.Connectors) == 0 {
+		errs = append(errs, errors.New("app connector has no connectors"))
+	}
+	for _, c := range app.Connectors {
+		if c != "*" && !strings.HasPrefix(c, "tag:") {
+			errs = append(errs, fmt.Errorf("invalid connector %q: must be a tag", c))
+		}
+	}
+	for _, d := range app.Domains {
+		if err := ValidateAppConnectorDomain(d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ValidateAppConnectorDomain returns an error if domain is not a valid app connector domain:
+// a fully qualified domain name, optionally prefixed with "*." to match all of its subdomains.
+func ValidateAppConnectorDomain(domain string) error {
+	name := strings.TrimPrefix(domain, "*.")
+	if len(name) > 253 {
+		return fmt.Errorf("invalid domain %q: longer than 253 characters", domain)
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("invalid domain %q: must contain at least two labels", domain)
+	}
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return fmt.Errorf("invalid domain %q: invalid label %q", domain, label)
+		}
+	}
+	return nil
+}
+
+// isDomainLabel reports whether s is a valid DNS label: 1 to 63 letters, digits and hyphens,
+// not starting or ending with a hyphen.
+func isDomainLabel(s string) bool {
+	if len(s) == 0 || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if r != '-' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
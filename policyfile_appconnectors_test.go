@@ -0,0 +1,66 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACL_AppConnectors(t *testing.T) {
+	t.Parallel()
+
+	github := &NodeAttrGrantApp{Name: "github", Connectors: []string{"tag:connector"}, Domains: []string{"github.com", "*.github.com"}}
+	okta := &NodeAttrGrantApp{Name: "okta", Connectors: []string{"tag:connector"}, Domains: []string{"example.okta.com"}}
+
+	acl, err := NewACL().
+		TagOwner("tag:connector", "autogroup:admin").
+		AddAppConnector([]string{"*"}, github).
+		AddAppConnector([]string{"*"}, okta).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, NodeAttrGrant{
+		Target: []string{"*"},
+		App:    map[string][]*NodeAttrGrantApp{"tailscale.com/app-connectors": {github}},
+	}, acl.NodeAttrs[0])
+
+	assert.Equal(t, []*NodeAttrGrantApp{github, okta}, acl.AppConnectors())
+	assert.Equal(t, okta, acl.AppConnector("okta"))
+	assert.Nil(t, acl.AppConnector("missing"))
+	assert.Equal(t, []*NodeAttrGrantApp{github}, acl.AppConnectorsForDomain("api.GitHub.com."))
+	assert.Equal(t, []*NodeAttrGrantApp{github}, acl.AppConnectorsForDomain("github.com"))
+	assert.Empty(t, acl.AppConnectorsForDomain("notgithub.com"))
+}
+
+func TestNodeAttrGrantApp_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&NodeAttrGrantApp{Name: "app", Connectors: []string{"tag:c"}, Domains: []string{"*.example.com"}}).Validate())
+
+	err := (&NodeAttrGrantApp{Connectors: []string{"connector"}, Domains: []string{"-bad.example.com", "localhost"}}).Validate()
+	assert.EqualError(t, err, `app connector has no name
+invalid connector "connector": must be a tag
+invalid domain "-bad.example.com": invalid label "-bad"
+invalid domain "localhost": must contain at least two labels`)
+
+	acl := &ACL{NodeAttrs: []NodeAttrGrant{
+		NewAppConnectorNodeAttr([]string{"*"}, &NodeAttrGrantApp{Name: "app", Connectors: []string{"tag:missing"}, Domains: []string{"*.*.example.com"}}),
+	}}
+	assert.ElementsMatch(t, []ACLProblem{
+		{Path: `nodeAttrs[0].app["tailscale.com/app-connectors"][0]`, Message: `invalid domain "*.*.example.com": invalid label "*"`},
+		{Path: `nodeAttrs[0].app["tailscale.com/app-connectors"][0].connectors[0]`, Message: `undefined tag "tag:missing"`},
+	}, acl.Lint())
+}
+
+func TestValidateAppConnectorDomain(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateAppConnectorDomain("example.com"))
+	assert.NoError(t, ValidateAppConnectorDomain("*.example.com"))
+	assert.NoError(t, ValidateAppConnectorDomain("a-b.c1.example.com"))
+	assert.Error(t, ValidateAppConnectorDomain("example..com"))
+	assert.Error(t, ValidateAppConnectorDomain("ex_ample.com"))
+	assert.Error(t, ValidateAppConnectorDomain("*example.com"))
+}
@@ -0,0 +1,193 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ACLAudit is the result of [AuditACL].
+type ACLAudit struct {
+	// UnownedTags maps tags that are applied to devices but have no entry in tagOwners
+	// to the IDs of the devices carrying them.
+	UnownedTags map[string][]string
+	// UnusedGroups lists groups that are defined but never referenced by any rule.
+	UnusedGroups []string
+	// UnusedHosts lists hosts that are defined but never referenced by any rule.
+	UnusedHosts []string
+	// ShadowedRules lists entries in the acls section that can never match anything
+	// not already matched by an earlier entry.
+	ShadowedRules []ACLShadowedRule
+}
+
+// ACLShadowedRule identifies an entry in the acls section that is made redundant by an earlier entry.
+type ACLShadowedRule struct {
+	// Index is the index of the redundant entry.
+	Index int
+	// ShadowedBy is the index of the earlier entry that covers it.
+	ShadowedBy int
+}
+
+// Empty reports whether the audit found nothing to report.
+func (a *ACLAudit) Empty() bool {
+	return len(a.UnownedTags) == 0 && len(a.UnusedGroups) == 0 && len(a.UnusedHosts) == 0 && len(a.ShadowedRules) == 0
+}
+
+// Audit fetches the policy file and devices of the tailnet and returns the result of [AuditACL].
+func (pr *PolicyFileResource) Audit(ctx context.Context) (*ACLAudit, error) {
+	acl, err := pr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := pr.Devices().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return AuditACL(acl, devices), nil
+}
+
+// AuditACL analyses acl for issues that are valid policy but often indicate mistakes: tags on
+// devices that nobody owns, groups and hosts that are never used, and rules in the acls
+// section that are shadowed by earlier rules. The shadowing check is syntactic and
+// conservative; it doesn't expand groups, hosts or port ranges, so it can miss rules that
+// are only redundant once those are resolved. devices may be nil to skip the tag check.
+func AuditACL(acl *ACL, devices []Device) *ACLAudit {
+	audit := &ACLAudit{}
+
+	for _, d := range devices {
+		for _, tag := range d.Tags {
+			if _, ok := acl.TagOwners[tag]; ok {
+				continue
+			}
+			if audit.UnownedTags == nil {
+				audit.UnownedTags = make(map[string][]string)
+			}
+			audit.UnownedTags[tag] = append(audit.UnownedTags[tag], d.ID)
+		}
+	}
+
+	used := aclReferences(acl)
+	for _, name := range slices.Sorted(maps.Keys(acl.Groups)) {
+		if !used[name] {
+			audit.UnusedGroups = append(audit.UnusedGroups, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(acl.Hosts)) {
+		if !used[name] {
+			audit.UnusedHosts = append(audit.UnusedHosts, name)
+		}
+	}
+
+	for i, e := range acl.ACLs {
+		for j := range i {
+			if aclEntryCovers(acl.ACLs[j], e) {
+				audit.ShadowedRules = append(audit.ShadowedRules, ACLShadowedRule{Index: i, ShadowedBy: j})
+				break
+			}
+		}
+	}
+
+	return audit
+}
+
+// aclReferences returns the set of names referenced by the rules of acl, with ports stripped
+// from destinations. The tests section is not considered, since it doesn't grant access.
+func aclReferences(acl *ACL) map[string]bool {
+	refs := make(map[string]bool)
+	add := func(names ...string) {
+		for _, n := range names {
+			refs[n] = true
+		}
+	}
+	for _, owners := range acl.TagOwners {
+		add(owners...)
+	}
+	for _, entries := range acl.IPSets {
+		for _, raw := range entries {
+			if e, err := ParseIPSetEntry(raw); err == nil {
+				add(e.Value)
+			}
+		}
+	}
+	for _, e := range acl.ACLs {
+		add(e.Source...)
+		add(e.Users...)
+		for _, d := range e.Destination {
+			add(stripPorts(d))
+		}
+		for _, d := range e.Ports {
+			add(stripPorts(d))
+		}
+	}
+	for _, g := range acl.Grants {
+		add(g.Source...)
+		add(g.Destination...)
+		add(g.Via...)
+	}
+	for _, s := range acl.SSH {
+		add(s.Source...)
+		add(s.Destination...)
+	}
+	for _, n := range acl.NodeAttrs {
+		add(n.Target...)
+	}
+	if acl.AutoApprovers != nil {
+		for _, approvers := range acl.AutoApprovers.Routes {
+			add(approvers...)
+		}
+		add(acl.AutoApprovers.ExitNode...)
+		for _, approvers := range acl.AutoApprovers.Services {
+			add(approvers...)
+		}
+	}
+	return refs
+}
+
+// aclEntryCovers reports whether every connection allowed by b is also allowed by a.
+func aclEntryCovers(a, b ACLEntry) bool {
+	if a.Protocol != "" && a.Protocol != b.Protocol {
+		return false
+	}
+	for _, p := range a.SourcePosture {
+		if !slices.Contains(b.SourcePosture, p) {
+			return false
+		}
+	}
+	aSrc := append(slices.Clone(a.Source), a.Users...)
+	for _, src := range append(slices.Clone(b.Source), b.Users...) {
+		if !slices.Contains(aSrc, "*") && !slices.Contains(aSrc, src) {
+			return false
+		}
+	}
+	aDst := append(slices.Clone(a.Destination), a.Ports...)
+	for _, dst := range append(slices.Clone(b.Destination), b.Ports...) {
+		if !slices.ContainsFunc(aDst, func(d string) bool { return aclDestinationCovers(d, dst) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// aclDestinationCovers reports whether the destination a, e.g. "tag:prod:*", includes every
+// host and port of the destination b, e.g. "tag:prod:80,443".
+func aclDestinationCovers(a, b string) bool {
+	aHost, aPorts := splitPorts(a)
+	bHost, bPorts := splitPorts(b)
+	if aHost != "*" && aHost != bHost {
+		return false
+	}
+	if aPorts == "*" {
+		return true
+	}
+	allowed := strings.Split(aPorts, ",")
+	for _, p := range strings.Split(bPorts, ",") {
+		if !slices.Contains(allowed, p) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditACL(t *testing.T) {
+	t.Parallel()
+
+	acl := &ACL{
+		Groups: map[string][]string{
+			"group:dev":    {"alice@example.com"},
+			"group:ops":    {"bob@example.com"},
+			"group:unused": {"carol@example.com"},
+		},
+		Hosts: map[string]string{
+			"db":     "100.64.0.1",
+			"legacy": "100.64.0.2",
+			"router": "100.64.0.3",
+		},
+		TagOwners: map[string][]string{
+			"tag:prod": {"group:ops"},
+		},
+		IPSets: map[string][]string{
+			"ipset:infra": {"add router"},
+		},
+		ACLs: []ACLEntry{
+			{Action: ACLActionAccept, Source: []string{"group:dev"}, Destination: []string{"db:*", "tag:prod:80,443"}},
+			{Action: ACLActionAccept, Source: []string{"group:dev"}, Destination: []string{"db:5432"}},
+			{Action: ACLActionAccept, Protocol: ACLProtocolTCP, Source: []string{"group:dev"}, Destination: []string{"tag:prod:443"}},
+			{Action: ACLActionAccept, Source: []string{"group:dev", "group:ops"}, Destination: []string{"db:5432"}},
+			{Action: ACLActionAccept, Source: []string{"*"}, Destination: []string{"*:*"}},
+			{Action: ACLActionAccept, Source: []string{"group:ops"}, Destination: []string{"tag:prod:22"}},
+		},
+		Tests: []ACLTest{
+			{Source: "group:unused", Accept: []string{"legacy:22"}},
+		},
+	}
+	devices := []Device{
+		{ID: "1", Tags: []string{"tag:prod"}},
+		{ID: "2", Tags: []string{"tag:staging", "tag:prod"}},
+		{ID: "3", Tags: []string{"tag:staging"}},
+	}
+
+	audit := AuditACL(acl, devices)
+	assert.Equal(t, &ACLAudit{
+		UnownedTags:  map[string][]string{"tag:staging": {"2", "3"}},
+		UnusedGroups: []string{"group:unused"},
+		UnusedHosts:  []string{"legacy"},
+		ShadowedRules: []ACLShadowedRule{
+			{Index: 1, ShadowedBy: 0},
+			{Index: 2, ShadowedBy: 0},
+			{Index: 5, ShadowedBy: 4},
+		},
+	}, audit)
+	assert.False(t, audit.Empty())
+
+	assert.True(t, AuditACL(&ACL{}, nil).Empty())
+}
+
+func TestClient_AuditACL(t *testing.T) {
+	t.Parallel()
+
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp any
+		switch r.URL.Path {
+		case "/api/v2/tailnet/example.com/acl":
+			resp = ACL{TagOwners: map[string][]string{"tag:prod": {"autogroup:admin"}}}
+		case "/api/v2/tailnet/example.com/devices":
+			resp = map[string][]Device{"devices": {{ID: "1", Tags: []string{"tag:dev"}}}}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+
+	audit, err := client.PolicyFile().Audit(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"tag:dev": {"1"}}, audit.UnownedTags)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"slices"
+	"strings"
+)
+
+// ACLBuilder assembles an [ACL] using chained method calls. Use [NewACL] to create one and
+// [ACLBuilder.Build] to obtain the result.
+//
+//	acl, err := tailscale.NewACL().
+//		Group("group:dev", "alice@example.com", "bob@example.com").
+//		TagOwner("tag:dev", "group:dev").
+//		AcceptTCP("group:dev", "tag:dev", "22", "443").
+//		SSHAccept([]string{"group:dev"}, []string{"tag:dev"}, "root").
+//		Build()
+type ACLBuilder struct {
+	acl ACL
+}
+
+// NewACL returns a new, empty [ACLBuilder].
+func NewACL() *ACLBuilder {
+	return &ACLBuilder{}
+}
+
+// Group adds members to the named group, creating it if necessary.
+func (b *ACLBuilder) Group(name string, members ...string) *ACLBuilder {
+	if b.acl.Groups == nil {
+		b.acl.Groups = make(map[string][]string)
+	}
+	b.acl.Groups[name] = append(b.acl.Groups[name], members...)
+	return b
+}
+
+// TagOwner adds owners to the given tag, defining it if necessary.
+func (b *ACLBuilder) TagOwner(tag string, owners ...string) *ACLBuilder {
+	if b.acl.TagOwners == nil {
+		b.acl.TagOwners = make(map[string][]string)
+	}
+	b.acl.TagOwners[tag] = append(b.acl.TagOwners[tag], owners...)
+	return b
+}
+
+// Host defines a host alias for the given IP address or CIDR range.
+func (b *ACLBuilder) Host(name, address string) *ACLBuilder {
+	if b.acl.Hosts == nil {
+		b.acl.Hosts = make(map[string]string)
+	}
+	b.acl.Hosts[name] = address
+	return b
+}
+
+// Rule appends an arbitrary entry to the acls section.
+func (b *ACLBuilder) Rule(entry ACLEntry) *ACLBuilder {
+	b.acl.ACLs = append(b.acl.ACLs, entry)
+	return b
+}
+
+// Accept appends an entry to the acls section allowing src to reach dst on the given ports, over any protocol.
+// If no ports are given, all ports are allowed.
+func (b *ACLBuilder) Accept(src, dst string, ports ...string) *ACLBuilder {
+	return b.accept("", src, dst, ports)
+}
+
+// AcceptTCP is like [ACLBuilder.Accept], but only allows TCP.
+func (b *ACLBuilder) AcceptTCP(src, dst string, ports ...string) *ACLBuilder {
+	return b.accept(ACLProtocolTCP, src, dst, ports)
+}
+
+// AcceptUDP is like [ACLBuilder.Accept], but only allows UDP.
+func (b *ACLBuilder) AcceptUDP(src, dst string, ports ...string) *ACLBuilder {
+	return b.accept(ACLProtocolUDP, src, dst, ports)
+}
+
+func (b *ACLBuilder) accept(proto, src, dst string, ports []string) *ACLBuilder {
+	if len(ports) == 0 {
+		ports = []string{"*"}
+	}
+	return b.Rule(ACLEntry{
+		Action:      ACLActionAccept,
+		Protocol:    proto,
+		Source:      []string{src},
+		Destination: []string{dst + ":" + strings.Join(ports, ",")},
+	})
+}
+
+// Grant appends an entry to the grants section.
+func (b *ACLBuilder) Grant(grant Grant) *ACLBuilder {
+	b.acl.Grants = append(b.acl.Grants, grant)
+	return b
+}
+
+// SSHRule appends an arbitrary rule to the ssh section.
+func (b *ACLBuilder) SSHRule(rule ACLSSH) *ACLBuilder {
+	b.acl.SSH = append(b.acl.SSH, rule)
+	return b
+}
+
+// SSHAccept appends a rule to the ssh section allowing src to connect to dst as any of users.
+func (b *ACLBuilder) SSHAccept(src, dst []string, users ...string) *ACLBuilder {
+	return b.SSHRule(ACLSSH{
+		Action:      ACLActionAccept,
+		Source:      slices.Clone(src),
+		Destination: slices.Clone(dst),
+		Users:       users,
+	})
+}
+
+// SSHCheck is like [ACLBuilder.SSHAccept], but requires users to re-authenticate
+// after checkPeriod has elapsed. A zero checkPeriod uses the default of 12 hours.
+func (b *ACLBuilder) SSHCheck(src, dst []string, checkPeriod SSHCheckPeriod, users ...string) *ACLBuilder {
+	return b.SSHRule(ACLSSH{
+		Action:      ACLActionCheck,
+		Source:      slices.Clone(src),
+		Destination: slices.Clone(dst),
+		Users:       users,
+		CheckPeriod: checkPeriod,
+	})
+}
+
+// Test appends an entry to the tests section.
+func (b *ACLBuilder) Test(test ACLTest) *ACLBuilder {
+	b.acl.Tests = append(b.acl.Tests, test)
+	return b
+}
+
+// Build returns the assembled [ACL]. If [ACL.Lint] finds any problems with it, they are
+// returned as an [*ACLLintError] along with the [ACL].
+func (b *ACLBuilder) Build() (*ACL, error) {
+	acl := b.acl
+	if problems := acl.Lint(); len(problems) > 0 {
+		return &acl, &ACLLintError{Problems: problems}
+	}
+	return &acl, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLBuilder(t *testing.T) {
+	t.Parallel()
+
+	acl, err := NewACL().
+		Group("group:dev", "alice@example.com").
+		Group("group:dev", "bob@example.com").
+		TagOwner("tag:dev", "group:dev").
+		Host("db", "100.64.0.1").
+		AcceptTCP("group:dev", "tag:dev", "22", "443").
+		AcceptUDP("group:dev", "db", "53").
+		Accept("autogroup:member", "autogroup:self").
+		SSHAccept([]string{"group:dev"}, []string{"tag:dev"}, "root").
+		SSHCheck([]string{"group:dev"}, []string{"autogroup:self"}, SSHCheckPeriod(time.Hour), "autogroup:nonroot").
+		Test(ACLTest{Source: "alice@example.com", Accept: []string{"tag:dev:22"}}).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &ACL{
+		Groups:    map[string][]string{"group:dev": {"alice@example.com", "bob@example.com"}},
+		TagOwners: map[string][]string{"tag:dev": {"group:dev"}},
+		Hosts:     map[string]string{"db": "100.64.0.1"},
+		ACLs: []ACLEntry{
+			{Action: "accept", Protocol: "tcp", Source: []string{"group:dev"}, Destination: []string{"tag:dev:22,443"}},
+			{Action: "accept", Protocol: "udp", Source: []string{"group:dev"}, Destination: []string{"db:53"}},
+			{Action: "accept", Source: []string{"autogroup:member"}, Destination: []string{"autogroup:self:*"}},
+		},
+		SSH: []ACLSSH{
+			{Action: "accept", Source: []string{"group:dev"}, Destination: []string{"tag:dev"}, Users: []string{"root"}},
+			{Action: "check", Source: []string{"group:dev"}, Destination: []string{"autogroup:self"}, Users: []string{"autogroup:nonroot"}, CheckPeriod: SSHCheckPeriod(time.Hour)},
+		},
+		Tests: []ACLTest{
+			{Source: "alice@example.com", Accept: []string{"tag:dev:22"}},
+		},
+	}, acl)
+}
+
+func TestACLBuilder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	acl, err := NewACL().AcceptTCP("group:missing", "tag:dev", "22").Build()
+	var lintErr *ACLLintError
+	assert.ErrorAs(t, err, &lintErr)
+	assert.Len(t, lintErr.Problems, 2)
+	assert.Len(t, acl.ACLs, 1)
+	assert.EqualError(t, err, `invalid policy file: acls[0].src[0]: undefined group "group:missing"; acls[0].dst[0]: undefined tag "tag:dev"`)
+}
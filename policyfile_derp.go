@@ -0,0 +1,45 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// This package deliberately does not depend on tailscale.com, so the conversions below work on any
+// value with the JSON shape of tailscale.com/tailcfg.DERPMap. Both types encode their fields under
+// the same names, differing only in case, which encoding/json matches case-insensitively.
+
+// ConvertTo copies m into dst, which is typically a *tailcfg.DERPMap:
+//
+//	var dm tailcfg.DERPMap
+//	if err := acl.DERPMap.ConvertTo(&dm); err != nil { ... }
+//
+// Fields of dst that have no equivalent in [ACLDERPMap] are left unset.
+func (m *ACLDERPMap) ConvertTo(dst any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("converting DERP map to %T: %w", dst, err)
+	}
+	return nil
+}
+
+// NewACLDERPMap returns an [ACLDERPMap] populated from src, which is typically a
+// *tailcfg.DERPMap. Fields of src that cannot be expressed in a policy file, such as
+// region coordinates, are dropped.
+func NewACLDERPMap(src any) (*ACLDERPMap, error) {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	var m ACLDERPMap
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("converting %T to DERP map: %w", src, err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The following types mirror the shape of tailscale.com/tailcfg.DERPMap.
+type tailcfgDERPMap struct {
+	HomeParams         *struct{ RegionScore map[int]float64 }
+	Regions            map[int]*tailcfgDERPRegion
+	OmitDefaultRegions bool
+}
+
+type tailcfgDERPRegion struct {
+	RegionID   int
+	RegionCode string
+	RegionName string
+	Latitude   float64
+	Longitude  float64
+	Avoid      bool
+	Nodes      []*tailcfgDERPNode
+}
+
+type tailcfgDERPNode struct {
+	Name     string
+	RegionID int
+	HostName string
+	CertName string
+	IPv4     string
+	IPv6     string
+	STUNPort int
+	STUNOnly bool
+	DERPPort int
+}
+
+func TestACLDERPMap_Conversion(t *testing.T) {
+	t.Parallel()
+
+	src := &tailcfgDERPMap{
+		OmitDefaultRegions: true,
+		Regions: map[int]*tailcfgDERPRegion{
+			900: {
+				RegionID:   900,
+				RegionCode: "custom",
+				RegionName: "Custom",
+				Latitude:   51.5,
+				Nodes: []*tailcfgDERPNode{
+					{Name: "900a", RegionID: 900, HostName: "derp.example.com", IPv4: "192.0.2.1", STUNPort: -1, DERPPort: 8443},
+				},
+			},
+		},
+	}
+
+	m, err := NewACLDERPMap(src)
+	assert.NoError(t, err)
+	assert.Equal(t, &ACLDERPMap{
+		OmitDefaultRegions: true,
+		Regions: map[int]*ACLDERPRegion{
+			900: {
+				RegionID:   900,
+				RegionCode: "custom",
+				RegionName: "Custom",
+				Nodes: []*ACLDERPNode{
+					{Name: "900a", RegionID: 900, HostName: "derp.example.com", IPv4: "192.0.2.1", STUNPort: -1, DERPPort: 8443},
+				},
+			},
+		},
+	}, m)
+
+	var dst tailcfgDERPMap
+	assert.NoError(t, m.ConvertTo(&dst))
+	src.Regions[900].Latitude = 0
+	assert.Equal(t, src, &dst)
+
+	assert.Error(t, m.ConvertTo(&[]string{}))
+}
@@ -0,0 +1,251 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ACLDiff is a structural change set between two policy files, as produced by [DiffACL].
+type ACLDiff struct {
+	ACLs      ACLListDiff[ACLEntry]
+	Grants    ACLListDiff[Grant]
+	SSH       ACLListDiff[ACLSSH]
+	Tests     ACLListDiff[ACLTest]
+	Groups    []ACLMembershipDiff
+	TagOwners []ACLMembershipDiff
+	Hosts     []ACLHostDiff
+	// Other lists the names of any other top-level sections that differ, e.g. "derpMap".
+	Other []string
+}
+
+// ACLListDiff describes the changes to an ordered section of a policy file, such as acls or grants.
+type ACLListDiff[T any] struct {
+	Added    []T
+	Removed  []T
+	Modified []ACLModification[T]
+}
+
+// Empty reports whether the section is unchanged.
+func (d ACLListDiff[T]) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// ACLModification is an entry of an ordered section that was changed in place.
+type ACLModification[T any] struct {
+	Old T
+	New T
+}
+
+// ACLMembershipDiff describes the changes to the members of a group, or to the owners of a tag.
+// A group or tag that was created or deleted has all of its members added or removed.
+type ACLMembershipDiff struct {
+	Name    string
+	Added   []string
+	Removed []string
+}
+
+// ACLHostDiff describes a change to a host alias. Old is empty if the host was added and New
+// is empty if it was removed.
+type ACLHostDiff struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// DiffACL compares two policy files and returns the structural changes from old to new.
+func DiffACL(old, new *ACL) ACLDiff {
+	d := ACLDiff{
+		ACLs:      diffACLList(old.ACLs, new.ACLs),
+		Grants:    diffACLList(old.Grants, new.Grants),
+		SSH:       diffACLList(old.SSH, new.SSH),
+		Tests:     diffACLList(old.Tests, new.Tests),
+		Groups:    diffMembership(old.Groups, new.Groups),
+		TagOwners: diffMembership(old.TagOwners, new.TagOwners),
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(mergeKeys(old.Hosts, new.Hosts))) {
+		if old.Hosts[name] != new.Hosts[name] {
+			d.Hosts = append(d.Hosts, ACLHostDiff{Name: name, Old: old.Hosts[name], New: new.Hosts[name]})
+		}
+	}
+
+	// Compare every other section via its JSON encoding.
+	oldRest, newRest := *old, *new
+	for _, acl := range []*ACL{&oldRest, &newRest} {
+		acl.ACLs, acl.Grants, acl.SSH, acl.Tests = nil, nil, nil, nil
+		acl.Groups, acl.TagOwners, acl.Hosts = nil, nil, nil
+		acl.ETag = ""
+	}
+	oldSections, newSections := aclSections(&oldRest), aclSections(&newRest)
+	for _, name := range slices.Sorted(maps.Keys(mergeKeys(oldSections, newSections))) {
+		if string(oldSections[name]) != string(newSections[name]) {
+			d.Other = append(d.Other, name)
+		}
+	}
+
+	return d
+}
+
+// Empty reports whether the two policy files are structurally identical.
+func (d ACLDiff) Empty() bool {
+	return d.ACLs.Empty() && d.Grants.Empty() && d.SSH.Empty() && d.Tests.Empty() &&
+		len(d.Groups) == 0 && len(d.TagOwners) == 0 && len(d.Hosts) == 0 && len(d.Other) == 0
+}
+
+// String returns a human-readable summary of the changes, one per line, suitable for
+// posting as a review comment.
+func (d ACLDiff) String() string {
+	var sb strings.Builder
+	writeACLListDiff(&sb, "acls", d.ACLs)
+	writeACLListDiff(&sb, "grants", d.Grants)
+	writeACLListDiff(&sb, "ssh", d.SSH)
+	writeACLListDiff(&sb, "tests", d.Tests)
+	for _, g := range d.Groups {
+		writeMembershipDiff(&sb, "groups", g)
+	}
+	for _, t := range d.TagOwners {
+		writeMembershipDiff(&sb, "tagOwners", t)
+	}
+	for _, h := range d.Hosts {
+		switch {
+		case h.Old == "":
+			fmt.Fprintf(&sb, "+ hosts: %s = %s\n", h.Name, h.New)
+		case h.New == "":
+			fmt.Fprintf(&sb, "- hosts: %s = %s\n", h.Name, h.Old)
+		default:
+			fmt.Fprintf(&sb, "~ hosts: %s = %s (was %s)\n", h.Name, h.New, h.Old)
+		}
+	}
+	for _, name := range d.Other {
+		fmt.Fprintf(&sb, "~ %s changed\n", name)
+	}
+	return sb.String()
+}
+
+func writeACLListDiff[T any](sb *strings.Builder, section string, d ACLListDiff[T]) {
+	for _, r := range d.Removed {
+		fmt.Fprintf(sb, "- %s: %s\n", section, compactJSON(r))
+	}
+	for _, r := range d.Added {
+		fmt.Fprintf(sb, "+ %s: %s\n", section, compactJSON(r))
+	}
+	for _, m := range d.Modified {
+		fmt.Fprintf(sb, "~ %s: %s -> %s\n", section, compactJSON(m.Old), compactJSON(m.New))
+	}
+}
+
+func writeMembershipDiff(sb *strings.Builder, section string, d ACLMembershipDiff) {
+	for _, m := range d.Removed {
+		fmt.Fprintf(sb, "- %s: %s: %s\n", section, d.Name, m)
+	}
+	for _, m := range d.Added {
+		fmt.Fprintf(sb, "+ %s: %s: %s\n", section, d.Name, m)
+	}
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// aclSections returns the JSON encoding of each non-empty top-level section of acl.
+func aclSections(acl *ACL) map[string]json.RawMessage {
+	sections := make(map[string]json.RawMessage)
+	b, err := json.Marshal(acl)
+	if err != nil {
+		return sections
+	}
+	_ = json.Unmarshal(b, &sections)
+	return sections
+}
+
+func mergeKeys[V any](a, b map[string]V) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+func diffMembership(old, new map[string][]string) []ACLMembershipDiff {
+	var diffs []ACLMembershipDiff
+	for _, name := range slices.Sorted(maps.Keys(mergeKeys(old, new))) {
+		d := ACLMembershipDiff{Name: name}
+		for _, m := range new[name] {
+			if !slices.Contains(old[name], m) {
+				d.Added = append(d.Added, m)
+			}
+		}
+		for _, m := range old[name] {
+			if !slices.Contains(new[name], m) {
+				d.Removed = append(d.Removed, m)
+			}
+		}
+		if len(d.Added) > 0 || len(d.Removed) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// diffACLList computes the changes between two ordered lists using their longest common
+// subsequence. Entries removed and added at the same position are reported as modified.
+func diffACLList[T any](old, new []T) ACLListDiff[T] {
+	// lcs[i][j] is the length of the longest common subsequence of old[i:] and new[j:].
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if reflect.DeepEqual(old[i], new[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var d ACLListDiff[T]
+	var removed, added []T
+	flush := func() {
+		n := min(len(removed), len(added))
+		for k := range n {
+			d.Modified = append(d.Modified, ACLModification[T]{Old: removed[k], New: added[k]})
+		}
+		d.Removed = append(d.Removed, removed[n:]...)
+		d.Added = append(d.Added, added[n:]...)
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case i < len(old) && j < len(new) && reflect.DeepEqual(old[i], new[j]):
+			flush()
+			i++
+			j++
+		case j == len(new) || (i < len(old) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, old[i])
+			i++
+		default:
+			added = append(added, new[j])
+			j++
+		}
+	}
+	flush()
+	return d
+}
@@ -0,0 +1,90 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffACL(t *testing.T) {
+	t.Parallel()
+
+	old := &ACL{
+		ACLs: []ACLEntry{
+			{Action: "accept", Source: []string{"group:dev"}, Destination: []string{"tag:dev:*"}},
+			{Action: "accept", Source: []string{"group:ops"}, Destination: []string{"tag:prod:*"}},
+			{Action: "accept", Source: []string{"*"}, Destination: []string{"tag:web:443"}},
+		},
+		Groups: map[string][]string{
+			"group:dev": {"alice@example.com", "bob@example.com"},
+			"group:old": {"carl@example.com"},
+		},
+		TagOwners: map[string][]string{
+			"tag:dev": {"group:dev"},
+		},
+		Hosts: map[string]string{
+			"db": "100.64.0.1",
+		},
+		ETag: "old",
+	}
+	new := &ACL{
+		ACLs: []ACLEntry{
+			{Action: "accept", Source: []string{"group:dev"}, Destination: []string{"tag:dev:*"}},
+			{Action: "accept", Source: []string{"group:ops"}, Destination: []string{"tag:prod:22"}},
+			{Action: "accept", Source: []string{"*"}, Destination: []string{"tag:web:443"}},
+			{Action: "accept", Source: []string{"group:ops"}, Destination: []string{"tag:db:5432"}},
+		},
+		Groups: map[string][]string{
+			"group:dev": {"alice@example.com", "dave@example.com"},
+		},
+		TagOwners: map[string][]string{
+			"tag:dev": {"group:dev"},
+		},
+		Hosts: map[string]string{
+			"db":  "100.64.0.2",
+			"web": "100.64.0.3",
+		},
+		DisableIPv4: true,
+		ETag:        "new",
+	}
+
+	d := DiffACL(old, new)
+	assert.False(t, d.Empty())
+	assert.Equal(t, []ACLEntry{new.ACLs[3]}, d.ACLs.Added)
+	assert.Empty(t, d.ACLs.Removed)
+	assert.Equal(t, []ACLModification[ACLEntry]{{Old: old.ACLs[1], New: new.ACLs[1]}}, d.ACLs.Modified)
+	assert.Equal(t, []ACLMembershipDiff{
+		{Name: "group:dev", Added: []string{"dave@example.com"}, Removed: []string{"bob@example.com"}},
+		{Name: "group:old", Removed: []string{"carl@example.com"}},
+	}, d.Groups)
+	assert.Empty(t, d.TagOwners)
+	assert.Equal(t, []ACLHostDiff{
+		{Name: "db", Old: "100.64.0.1", New: "100.64.0.2"},
+		{Name: "web", New: "100.64.0.3"},
+	}, d.Hosts)
+	assert.Equal(t, []string{"disableIPv4"}, d.Other)
+
+	assert.Equal(t, `+ acls: {"action":"accept","src":["group:ops"],"dst":["tag:db:5432"]}
+~ acls: {"action":"accept","src":["group:ops"],"dst":["tag:prod:*"]} -> {"action":"accept","src":["group:ops"],"dst":["tag:prod:22"]}
+- groups: group:dev: bob@example.com
++ groups: group:dev: dave@example.com
+- groups: group:old: carl@example.com
+~ hosts: db = 100.64.0.2 (was 100.64.0.1)
++ hosts: web = 100.64.0.3
+~ disableIPv4 changed
+`, d.String())
+
+	assert.True(t, DiffACL(old, old).Empty())
+}
+
+func TestDiffACLList(t *testing.T) {
+	t.Parallel()
+
+	d := diffACLList([]string{"a", "b", "c"}, []string{"b", "c", "d"})
+	assert.Equal(t, []string{"a"}, d.Removed)
+	assert.Equal(t, []string{"d"}, d.Added)
+	assert.Empty(t, d.Modified)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// PolicyEditor edits a HuJSON policy file in place, preserving the comments and formatting of
+// every part of the document that is not modified. Use [NewPolicyEditor] to create one, or
+// [PolicyFileResource.Edit] to edit the tailnet's policy file directly.
+//
+// Sections are matched case-insensitively, so both "groups" and "Groups" are understood.
+// Sections that do not exist yet are created using their lowercase JSON name.
+type PolicyEditor struct {
+	value hujson.Value
+	etag  string
+}
+
+// NewPolicyEditor parses the HuJSON policy file in raw for editing.
+func NewPolicyEditor(raw RawACL) (*PolicyEditor, error) {
+	v, err := hujson.Parse([]byte(raw.HuJSON))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.Value.(*hujson.Object); !ok {
+		return nil, errors.New("policy file must be a JSON object")
+	}
+	return &PolicyEditor{value: v, etag: raw.ETag}, nil
+}
+
+// RawACL returns the edited policy file, along with the ETag of the policy file it was created from.
+func (e *PolicyEditor) RawACL() RawACL {
+	return RawACL{HuJSON: e.value.String(), ETag: e.etag}
+}
+
+// Format formats the whole policy file in the standard HuJSON style. Comments are preserved.
+func (e *PolicyEditor) Format() {
+	e.value.Format()
+}
+
+// Patch applies a JSON Patch (RFC 6902) to the policy file. The patch may itself be HuJSON,
+// in which case comments within inserted values are preserved.
+func (e *PolicyEditor) Patch(patch []byte) error {
+	return e.value.Patch(patch)
+}
+
+// AddGroupMember adds member to group, creating the group if necessary.
+// It does nothing if member already belongs to group.
+func (e *PolicyEditor) AddGroupMember(group, member string) error {
+	ptr, err := e.ensureSection("groups")
+	if err != nil {
+		return err
+	}
+	ptr += "/" + escapeJSONPointer(group)
+
+	var members []string
+	found, err := e.get(ptr, &members)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return e.patch("add", ptr, []string{member})
+	}
+	if slices.Contains(members, member) {
+		return nil
+	}
+	return e.patch("add", ptr+"/-", member)
+}
+
+// RemoveGroupMember removes member from group. It does nothing if member does not belong to group.
+func (e *PolicyEditor) RemoveGroupMember(group, member string) error {
+	ptr := e.sectionPointer("groups") + "/" + escapeJSONPointer(group)
+
+	var members []string
+	if _, err := e.get(ptr, &members); err != nil {
+		return err
+	}
+	i := slices.Index(members, member)
+	if i == -1 {
+		return nil
+	}
+	return e.patch("remove", fmt.Sprintf("%s/%d", ptr, i), nil)
+}
+
+// SetTagOwners sets the owners of tag, replacing any existing owners.
+func (e *PolicyEditor) SetTagOwners(tag string, owners []string) error {
+	ptr, err := e.ensureSection("tagOwners")
+	if err != nil {
+		return err
+	}
+	return e.patch("add", ptr+"/"+escapeJSONPointer(tag), owners)
+}
+
+// SetSection replaces the named top-level section with value, creating it if necessary.
+// The rest of the policy file, including comments, is left untouched.
+func (e *PolicyEditor) SetSection(name string, value any) error {
+	return e.patch("add", e.sectionPointer(name), value)
+}
+
+// AppendACLRule appends entry to the acls section.
+func (e *PolicyEditor) AppendACLRule(entry ACLEntry) error {
+	ptr, err := e.ensureSectionArray("acls")
+	if err != nil {
+		return err
+	}
+	return e.patch("add", ptr+"/-", entry)
+}
+
+// AppendGrant appends grant to the grants section.
+func (e *PolicyEditor) AppendGrant(grant Grant) error {
+	ptr, err := e.ensureSectionArray("grants")
+	if err != nil {
+		return err
+	}
+	return e.patch("add", ptr+"/-", grant)
+}
+
+// Edit performs an ETag-protected read-modify-write of the tailnet's HuJSON policy file, preserving
+// comments and formatting. It retrieves the policy file with [PolicyFileResource.Raw], calls edit
+// and sets the result with [PolicyFileResource.SetRawAndGet]. Like [PolicyFileResource.Update], the
+// whole cycle is retried if the policy file was modified concurrently. Returns the resulting policy file.
+func (pr *PolicyFileResource) Edit(ctx context.Context, edit func(e *PolicyEditor) error) (*RawACL, error) {
+	var err error
+	for range defaultPolicyUpdateAttempts {
+		var raw *RawACL
+		raw, err = pr.Raw(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var editor *PolicyEditor
+		editor, err = NewPolicyEditor(*raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := edit(editor); err != nil {
+			return nil, err
+		}
+
+		var out *RawACL
+		out, err = pr.SetRawAndGet(ctx, editor.RawACL())
+		if err == nil {
+			return out, nil
+		}
+		if !IsPreconditionFailed(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("policy file was modified concurrently, giving up after %d attempts: %w", defaultPolicyUpdateAttempts, err)
+}
+
+// sectionPointer returns the JSON pointer to the named top-level section,
+// matching existing section names case-insensitively.
+func (e *PolicyEditor) sectionPointer(name string) string {
+	obj := e.value.Value.(*hujson.Object)
+	for _, m := range obj.Members {
+		if lit, ok := m.Name.Value.(hujson.Literal); ok && strings.EqualFold(lit.String(), name) {
+			return "/" + escapeJSONPointer(lit.String())
+		}
+	}
+	return "/" + escapeJSONPointer(name)
+}
+
+// ensureSection returns the JSON pointer to the named top-level object section, creating it if necessary.
+func (e *PolicyEditor) ensureSection(name string) (string, error) {
+	ptr := e.sectionPointer(name)
+	if e.value.Find(ptr) == nil {
+		return ptr, e.patch("add", ptr, map[string]any{})
+	}
+	return ptr, nil
+}
+
+// ensureSectionArray returns the JSON pointer to the named top-level array section, creating it if necessary.
+func (e *PolicyEditor) ensureSectionArray(name string) (string, error) {
+	ptr := e.sectionPointer(name)
+	if e.value.Find(ptr) == nil {
+		return ptr, e.patch("add", ptr, []any{})
+	}
+	return ptr, nil
+}
+
+// get decodes the value at ptr into out, reporting whether it exists.
+func (e *PolicyEditor) get(ptr string, out any) (bool, error) {
+	v := e.value.Find(ptr)
+	if v == nil {
+		return false, nil
+	}
+	b, err := hujson.Standardize(v.Pack())
+	if err != nil {
+		return true, err
+	}
+	return true, json.Unmarshal(b, out)
+}
+
+func (e *PolicyEditor) patch(op, path string, value any) error {
+	operation := map[string]any{"op": op, "path": path}
+	if op != "remove" {
+		operation["value"] = value
+	}
+	b, err := json.Marshal([]any{operation})
+	if err != nil {
+		return err
+	}
+	return e.value.Patch(b)
+}
+
+// escapeJSONPointer escapes a single JSON pointer reference token per RFC 6901.
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tailscale/hujson"
+)
+
+func TestPolicyEditor(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewPolicyEditor(RawACL{HuJSON: string(huJSONACL), ETag: "myetag"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, e.AddGroupMember("group:dev", "dave@example.com"))
+	assert.NoError(t, e.AddGroupMember("group:dev", "dave@example.com"))
+	assert.NoError(t, e.AddGroupMember("group:new", "erin@example.com"))
+	assert.NoError(t, e.RemoveGroupMember("group:devops", "carl@example.com"))
+	assert.NoError(t, e.RemoveGroupMember("group:missing", "carl@example.com"))
+	assert.NoError(t, e.SetTagOwners("tag:new", []string{"group:new"}))
+	assert.NoError(t, e.AppendACLRule(ACLEntry{Action: "accept", Source: []string{"group:new"}, Destination: []string{"tag:new:*"}}))
+
+	raw := e.RawACL()
+	assert.Equal(t, "myetag", raw.ETag)
+
+	// Comments from the original document are preserved.
+	for _, line := range strings.Split(string(huJSONACL), "\n") {
+		if comment := strings.TrimSpace(line); strings.HasPrefix(comment, "//") {
+			assert.Contains(t, raw.HuJSON, comment)
+		}
+	}
+
+	b, err := hujson.Standardize([]byte(raw.HuJSON))
+	assert.NoError(t, err)
+	var acl ACL
+	assert.NoError(t, json.Unmarshal(b, &acl))
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "dave@example.com"}, acl.Groups["group:dev"])
+	assert.Equal(t, []string{"erin@example.com"}, acl.Groups["group:new"])
+	assert.Empty(t, acl.Groups["group:devops"])
+	assert.Equal(t, []string{"group:new"}, acl.TagOwners["tag:new"])
+	assert.Equal(t, ACLEntry{Action: "accept", Source: []string{"group:new"}, Destination: []string{"tag:new:*"}}, acl.ACLs[len(acl.ACLs)-1])
+}
+
+func TestPolicyEditor_CreatesSections(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewPolicyEditor(RawACL{HuJSON: "{\n\t// Empty policy.\n}\n"})
+	assert.NoError(t, err)
+	assert.NoError(t, e.AppendGrant(Grant{Source: []string{"*"}, Destination: []string{"*"}, IP: []string{"*"}}))
+	assert.NoError(t, e.AddGroupMember("group:a", "alice@example.com"))
+
+	b, err := hujson.Standardize([]byte(e.RawACL().HuJSON))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"grants":[{"src":["*"],"dst":["*"],"ip":["*"]}],"groups":{"group:a":["alice@example.com"]}}`, string(b))
+	assert.Contains(t, e.RawACL().HuJSON, "// Empty policy.")
+
+	_, err = NewPolicyEditor(RawACL{HuJSON: "[]"})
+	assert.Error(t, err)
+}
+
+func TestClient_EditACL(t *testing.T) {
+	t.Parallel()
+
+	var posted string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/acl", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			_, err := w.Write(huJSONACL)
+			assert.NoError(t, err)
+		case http.MethodPost:
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+			assert.Equal(t, "application/hujson", r.Header.Get("Content-Type"))
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			posted = string(b)
+			w.Header().Set("ETag", `"v2"`)
+			_, err = w.Write(b)
+			assert.NoError(t, err)
+		}
+	}))
+
+	raw, err := client.PolicyFile().Edit(context.Background(), func(e *PolicyEditor) error {
+		return e.AddGroupMember("group:dev", "dave@example.com")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"v2"`, raw.ETag)
+	assert.Equal(t, posted, raw.HuJSON)
+	assert.Contains(t, raw.HuJSON, "dave@example.com")
+}
+
+func TestClient_EditACLGivesUp(t *testing.T) {
+	t.Parallel()
+
+	var posts int
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", `"v1"`)
+			_, err := w.Write(huJSONACL)
+			assert.NoError(t, err)
+			return
+		}
+		posts++
+		w.WriteHeader(http.StatusPreconditionFailed)
+		assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "precondition failed"}))
+	}))
+
+	_, err := client.PolicyFile().Edit(context.Background(), func(e *PolicyEditor) error {
+		return e.AddGroupMember("group:dev", "dave@example.com")
+	})
+	assert.True(t, IsPreconditionFailed(err))
+	assert.EqualError(t, err, "policy file was modified concurrently, giving up after 5 attempts: precondition failed (412)")
+	assert.Equal(t, defaultPolicyUpdateAttempts, posts)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// errPolicyUnchanged is returned by mutate functions passed to [PolicyFileResource.Update]
+// to abort without writing when there is nothing to change.
+var errPolicyUnchanged = errors.New("policy file unchanged")
+
+// SyncGroups reconciles the groups section of the policy file with desired, a mapping of group names
+// (e.g. "group:eng") to their complete member lists, such as one exported from an identity provider.
+// Each group in desired is created or has its members replaced; groups not in desired are left
+// untouched. The write is ETag-protected and retried as in [PolicyFileResource.Update], and is
+// skipped entirely if nothing would change. Returns the changes made, sorted by group name.
+func (pr *PolicyFileResource) SyncGroups(ctx context.Context, desired map[string][]string) ([]ACLMembershipDiff, error) {
+	for name := range desired {
+		if !strings.HasPrefix(name, "group:") {
+			return nil, fmt.Errorf("invalid group name %q: must start with \"group:\"", name)
+		}
+	}
+
+	var changes []ACLMembershipDiff
+	_, err := pr.Update(ctx, func(acl *ACL) error {
+		changes = ReconcileGroups(acl, desired)
+		if len(changes) == 0 {
+			return errPolicyUnchanged
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPolicyUnchanged) {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ReconcileGroups sets the members of each group in acl to those given in desired, leaving groups
+// not in desired untouched, and returns the resulting changes sorted by group name. Groups whose
+// membership is unchanged, including empty groups that don't exist yet, are not modified.
+func ReconcileGroups(acl *ACL, desired map[string][]string) []ACLMembershipDiff {
+	current := make(map[string][]string, len(desired))
+	for name := range desired {
+		current[name] = acl.Groups[name]
+	}
+
+	changes := diffMembership(current, desired)
+	if len(changes) == 0 {
+		return nil
+	}
+	if acl.Groups == nil {
+		acl.Groups = make(map[string][]string)
+	}
+	for _, c := range changes {
+		acl.Groups[c.Name] = slices.Clone(desired[c.Name])
+	}
+	return changes
+}
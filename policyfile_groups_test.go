@@ -0,0 +1,77 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileGroups(t *testing.T) {
+	t.Parallel()
+
+	acl := &ACL{Groups: map[string][]string{
+		"group:eng":    {"alice@example.com", "bob@example.com"},
+		"group:sales":  {"carol@example.com"},
+		"group:manual": {"dave@example.com"},
+	}}
+
+	changes := ReconcileGroups(acl, map[string][]string{
+		"group:eng":   {"bob@example.com", "erin@example.com"},
+		"group:sales": {"carol@example.com"},
+		"group:new":   {"frank@example.com"},
+	})
+	assert.Equal(t, []ACLMembershipDiff{
+		{Name: "group:eng", Added: []string{"erin@example.com"}, Removed: []string{"alice@example.com"}},
+		{Name: "group:new", Added: []string{"frank@example.com"}},
+	}, changes)
+	assert.Equal(t, map[string][]string{
+		"group:eng":    {"bob@example.com", "erin@example.com"},
+		"group:sales":  {"carol@example.com"},
+		"group:manual": {"dave@example.com"},
+		"group:new":    {"frank@example.com"},
+	}, acl.Groups)
+
+	assert.Nil(t, ReconcileGroups(acl, map[string][]string{"group:sales": {"carol@example.com"}}))
+}
+
+func TestClient_SyncGroups(t *testing.T) {
+	t.Parallel()
+
+	var posts int
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/acl", r.URL.Path)
+		acl := ACL{Groups: map[string][]string{"group:eng": {"alice@example.com"}}}
+		if r.Method == http.MethodPost {
+			posts++
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&acl))
+		}
+		w.Header().Set("ETag", `"v1"`)
+		assert.NoError(t, json.NewEncoder(w).Encode(acl))
+	}))
+
+	changes, err := client.PolicyFile().SyncGroups(context.Background(), map[string][]string{
+		"group:eng": {"bob@example.com"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ACLMembershipDiff{
+		{Name: "group:eng", Added: []string{"bob@example.com"}, Removed: []string{"alice@example.com"}},
+	}, changes)
+	assert.Equal(t, 1, posts)
+
+	changes, err = client.PolicyFile().SyncGroups(context.Background(), map[string][]string{
+		"group:eng": {"alice@example.com"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, 1, posts)
+
+	_, err = client.PolicyFile().SyncGroups(context.Background(), map[string][]string{"eng": nil})
+	assert.EqualError(t, err, `invalid group name "eng": must start with "group:"`)
+}
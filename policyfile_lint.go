@@ -0,0 +1,383 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// ACLProblem describes a structural problem found in a policy file by [ACL.Lint].
+type ACLProblem struct {
+	// Path locates the problem within the policy file, e.g. "acls[2].dst[0]".
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+func (p ACLProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// ACLLintError is an error wrapping the problems found by [ACL.Lint].
+type ACLLintError struct {
+	Problems []ACLProblem
+}
+
+func (e *ACLLintError) Error() string {
+	msgs := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		msgs = append(msgs, p.String())
+	}
+	return "invalid policy file: " + strings.Join(msgs, "; ")
+}
+
+// LintHuJSON parses a HuJSON policy file and returns the result of [ACL.Lint].
+// An error is returned only if the document cannot be parsed.
+func LintHuJSON(b []byte) ([]ACLProblem, error) {
+	b, err := hujson.Standardize(append([]byte(nil), b...))
+	if err != nil {
+		return nil, err
+	}
+	var acl ACL
+	if err := json.Unmarshal(b, &acl); err != nil {
+		return nil, err
+	}
+	return acl.Lint(), nil
+}
+
+// Lint checks the policy file for structural problems without calling the API: unknown
+// actions, references to undefined groups, tags, hosts and IP sets, duplicate group members
+// and rules without sources or destinations. Problems are reported in policy file order, with
+// named entries such as groups in sorted order, so that the output is stable. It is intended for
+// fast pre-commit checks; [PolicyFileResource.Validate] remains the authoritative check.
+func (acl *ACL) Lint() []ACLProblem {
+	l := &aclLinter{acl: acl}
+
+	for _, name := range slices.Sorted(maps.Keys(acl.Groups)) {
+		members := acl.Groups[name]
+		path := fmt.Sprintf("groups[%q]", name)
+		if !strings.HasPrefix(name, "group:") {
+			l.add(path, "group names must start with \"group:\"")
+		}
+		seen := make(map[string]bool)
+		for _, m := range members {
+			if seen[m] {
+				l.add(path, fmt.Sprintf("duplicate member %q", m))
+			}
+			seen[m] = true
+			l.checkRef(path, m)
+		}
+	}
+
+	for _, tag := range slices.Sorted(maps.Keys(acl.TagOwners)) {
+		owners := acl.TagOwners[tag]
+		path := fmt.Sprintf("tagOwners[%q]", tag)
+		if !strings.HasPrefix(tag, "tag:") {
+			l.add(path, "tag names must start with \"tag:\"")
+		}
+		for _, o := range owners {
+			l.checkRef(path, o)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(acl.IPSets)) {
+		entries := acl.IPSets[name]
+		path := fmt.Sprintf("ipsets[%q]", name)
+		if !strings.HasPrefix(name, "ipset:") {
+			l.add(path, "ipset names must start with \"ipset:\"")
+		}
+		for j, raw := range entries {
+			e, err := ParseIPSetEntry(raw)
+			if err != nil {
+				l.add(fmt.Sprintf("%s[%d]", path, j), err.Error())
+				continue
+			}
+			l.checkRef(fmt.Sprintf("%s[%d]", path, j), e.Value)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(acl.Postures)) {
+		conditions := acl.Postures[name]
+		path := fmt.Sprintf("postures[%q]", name)
+		if !strings.HasPrefix(name, "posture:") {
+			l.add(path, "posture names must start with \"posture:\"")
+		}
+		for j, c := range conditions {
+			if err := c.Validate(); err != nil {
+				l.add(fmt.Sprintf("%s[%d]", path, j), err.Error())
+			}
+		}
+	}
+	for j, p := range acl.DefaultSourcePosture {
+		l.checkRef(fmt.Sprintf("defaultSrcPosture[%d]", j), p)
+	}
+
+	for i, e := range acl.ACLs {
+		path := fmt.Sprintf("acls[%d]", i)
+		if e.Action != ACLActionAccept {
+			l.add(path, fmt.Sprintf("unknown action %q", e.Action))
+		}
+		if e.Protocol != "" && !IsValidACLProtocol(e.Protocol) {
+			l.add(path, fmt.Sprintf("unknown protocol %q", e.Protocol))
+		}
+		src := append(append([]string(nil), e.Source...), e.Users...)
+		dst := append(append([]string(nil), e.Destination...), e.Ports...)
+		if len(src) == 0 {
+			l.add(path, "no src")
+		}
+		if len(dst) == 0 {
+			l.add(path, "no dst")
+		}
+		for j, s := range e.Source {
+			l.checkRef(fmt.Sprintf("%s.src[%d]", path, j), s)
+		}
+		for j, s := range e.Users {
+			l.checkRef(fmt.Sprintf("%s.users[%d]", path, j), s)
+		}
+		for j, d := range e.Destination {
+			l.checkRef(fmt.Sprintf("%s.dst[%d]", path, j), stripPorts(d))
+		}
+		for j, d := range e.Ports {
+			l.checkRef(fmt.Sprintf("%s.ports[%d]", path, j), stripPorts(d))
+		}
+		for j, p := range e.SourcePosture {
+			l.checkRef(fmt.Sprintf("%s.srcPosture[%d]", path, j), p)
+		}
+	}
+
+	for i, g := range acl.Grants {
+		path := fmt.Sprintf("grants[%d]", i)
+		if len(g.Source) == 0 {
+			l.add(path, "no src")
+		}
+		if len(g.Destination) == 0 {
+			l.add(path, "no dst")
+		}
+		if len(g.IP) == 0 && len(g.App) == 0 {
+			l.add(path, "grant must have ip or app")
+		}
+		for j, s := range g.Source {
+			l.checkRef(fmt.Sprintf("%s.src[%d]", path, j), s)
+		}
+		for j, d := range g.Destination {
+			l.checkRef(fmt.Sprintf("%s.dst[%d]", path, j), d)
+		}
+		for j, v := range g.Via {
+			l.checkRef(fmt.Sprintf("%s.via[%d]", path, j), v)
+		}
+		for j, p := range g.SrcPosture {
+			l.checkRef(fmt.Sprintf("%s.srcPosture[%d]", path, j), p)
+		}
+	}
+
+	for i, s := range acl.SSH {
+		path := fmt.Sprintf("ssh[%d]", i)
+		if !IsValidACLAction(s.Action) {
+			l.add(path, fmt.Sprintf("unknown action %q", s.Action))
+		}
+		if len(s.Source) == 0 {
+			l.add(path, "no src")
+		}
+		if len(s.Destination) == 0 {
+			l.add(path, "no dst")
+		}
+		for j, src := range s.Source {
+			l.checkRef(fmt.Sprintf("%s.src[%d]", path, j), src)
+		}
+		for j, d := range s.Destination {
+			l.checkRef(fmt.Sprintf("%s.dst[%d]", path, j), d)
+		}
+		for j, r := range s.Recorder {
+			if err := SSHRecorder(r).Validate(); err != nil {
+				l.add(fmt.Sprintf("%s.recorder[%d]", path, j), err.Error())
+				continue
+			}
+			if strings.HasPrefix(r, "tag:") {
+				l.checkRef(fmt.Sprintf("%s.recorder[%d]", path, j), r)
+			}
+		}
+		if s.EnforceRecorder && len(s.Recorder) == 0 {
+			l.add(path, "enforceRecorder requires a recorder")
+		}
+		for j, env := range s.AcceptEnv {
+			if !isEnvPattern(env) {
+				l.add(fmt.Sprintf("%s.acceptEnv[%d]", path, j), fmt.Sprintf("invalid environment variable pattern %q", env))
+			}
+		}
+	}
+
+	for i, test := range acl.Tests {
+		path := fmt.Sprintf("tests[%d]", i)
+		if test.Proto != "" && !IsValidACLProtocol(test.Proto) {
+			l.add(path, fmt.Sprintf("unknown protocol %q", test.Proto))
+		}
+		for j, p := range test.SrcPosture {
+			l.checkRef(fmt.Sprintf("%s.srcPosture[%d]", path, j), p)
+		}
+	}
+
+	if acl.AutoApprovers != nil {
+		for _, route := range slices.Sorted(maps.Keys(acl.AutoApprovers.Routes)) {
+			approvers := acl.AutoApprovers.Routes[route]
+			path := fmt.Sprintf("autoApprovers.routes[%q]", route)
+			if _, err := netip.ParsePrefix(route); err != nil {
+				l.add(path, fmt.Sprintf("invalid route %q", route))
+			}
+			for j, a := range approvers {
+				l.checkApprover(fmt.Sprintf("%s[%d]", path, j), a)
+			}
+		}
+		for j, a := range acl.AutoApprovers.ExitNode {
+			l.checkApprover(fmt.Sprintf("autoApprovers.exitNode[%d]", j), a)
+		}
+		for _, svc := range slices.Sorted(maps.Keys(acl.AutoApprovers.Services)) {
+			approvers := acl.AutoApprovers.Services[svc]
+			path := fmt.Sprintf("autoApprovers.services[%q]", svc)
+			if !strings.HasPrefix(svc, "svc:") {
+				l.add(path, "service names must start with \"svc:\"")
+			}
+			for j, a := range approvers {
+				l.checkApprover(fmt.Sprintf("%s[%d]", path, j), a)
+			}
+		}
+	}
+
+	for i, n := range acl.NodeAttrs {
+		for j, t := range n.Target {
+			l.checkRef(fmt.Sprintf("nodeAttrs[%d].target[%d]", i, j), t)
+		}
+		for j, app := range n.App[AppConnectorsAttr] {
+			path := fmt.Sprintf("nodeAttrs[%d].app[%q][%d]", i, AppConnectorsAttr, j)
+			if app == nil {
+				continue
+			}
+			for _, err := range app.problems() {
+				l.add(path, err.Error())
+			}
+			for k, c := range app.Connectors {
+				if strings.HasPrefix(c, "tag:") {
+					l.checkRef(fmt.Sprintf("%s.connectors[%d]", path, k), c)
+				}
+			}
+		}
+	}
+
+	return l.problems
+}
+
+type aclLinter struct {
+	acl      *ACL
+	problems []ACLProblem
+}
+
+func (l *aclLinter) add(path, msg string) {
+	l.problems = append(l.problems, ACLProblem{Path: path, Message: msg})
+}
+
+// checkRef reports a problem if ref refers to a group, tag, host, IP set or posture that is not
+// defined in the policy file.
+func (l *aclLinter) checkRef(path, ref string) {
+	switch {
+	case ref == "" || ref == "*":
+	case strings.HasPrefix(ref, "group:"):
+		if _, ok := l.acl.Groups[ref]; !ok {
+			l.add(path, fmt.Sprintf("undefined group %q", ref))
+		}
+	case strings.HasPrefix(ref, "tag:"):
+		if _, ok := l.acl.TagOwners[ref]; !ok {
+			l.add(path, fmt.Sprintf("undefined tag %q", ref))
+		}
+	case strings.HasPrefix(ref, "ipset:"):
+		if _, ok := l.acl.IPSets[ref]; !ok {
+			l.add(path, fmt.Sprintf("undefined ipset %q", ref))
+		}
+	case strings.HasPrefix(ref, "posture:"):
+		if _, ok := l.acl.Postures[ref]; !ok {
+			l.add(path, fmt.Sprintf("undefined posture %q", ref))
+		}
+	case strings.HasPrefix(ref, "autogroup:"):
+		if !Autogroup(ref).IsValid() {
+			l.add(path, fmt.Sprintf("unknown autogroup %q", ref))
+		}
+	case strings.Contains(ref, ":"), strings.Contains(ref, "@"):
+		// IPv6 addresses and users.
+	default:
+		if _, err := netip.ParseAddr(ref); err == nil {
+			return
+		}
+		if _, err := netip.ParsePrefix(ref); err == nil {
+			return
+		}
+		if strings.Contains(ref, "-") && isIPRange(ref) {
+			return
+		}
+		if _, ok := l.acl.Hosts[ref]; !ok {
+			l.add(path, fmt.Sprintf("undefined host %q", ref))
+		}
+	}
+}
+
+// checkApprover reports a problem if approver is not a defined tag or group, an autogroup or a user.
+func (l *aclLinter) checkApprover(path, approver string) {
+	switch {
+	case strings.HasPrefix(approver, "tag:"), strings.HasPrefix(approver, "group:"), strings.HasPrefix(approver, "autogroup:"):
+		l.checkRef(path, approver)
+	case strings.Contains(approver, "@"):
+	default:
+		l.add(path, fmt.Sprintf("invalid approver %q: must be a tag, group, autogroup or user", approver))
+	}
+}
+
+// isEnvPattern reports whether s is a valid environment variable name, optionally containing
+// * and ? wildcards.
+func isEnvPattern(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && r != '*' && r != '?' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isIPRange reports whether s is a range of IP addresses like "192.0.2.1-192.0.2.10".
+func isIPRange(s string) bool {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return false
+	}
+	_, fromErr := netip.ParseAddr(from)
+	_, toErr := netip.ParseAddr(to)
+	return fromErr == nil && toErr == nil
+}
+
+// stripPorts removes the trailing port specification from an ACL destination
+// such as "tag:prod:80,443", "[fd7a:115c:a1e0::1]:22" or "autogroup:self:*".
+func stripPorts(dst string) string {
+	host, _ := splitPorts(dst)
+	return host
+}
+
+// splitPorts splits an ACL destination into its host and port specification.
+func splitPorts(dst string) (host, ports string) {
+	if strings.HasPrefix(dst, "[") {
+		if end := strings.Index(dst, "]"); end != -1 {
+			return dst[1:end], strings.TrimPrefix(dst[end+1:], ":")
+		}
+	}
+	i := strings.LastIndex(dst, ":")
+	if i == -1 {
+		return dst, ""
+	}
+	return dst[:i], dst[i+1:]
+}
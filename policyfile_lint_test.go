@@ -0,0 +1,116 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACL_Lint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid policy", func(t *testing.T) {
+		problems, err := LintHuJSON([]byte(`{
+			// Developers.
+			"groups": {"group:dev": ["alice@example.com"]},
+			"tagOwners": {"tag:dev": ["group:dev"]},
+			"hosts": {"db": "100.64.0.1"},
+			"acls": [
+				{"action": "accept", "src": ["group:dev"], "dst": ["tag:dev:*", "db:5432", "autogroup:self:*"]},
+			],
+			"ssh": [
+				{"action": "check", "src": ["autogroup:member"], "dst": ["autogroup:self"], "users": ["autogroup:nonroot"]},
+			],
+		}`))
+		assert.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		acl := ACL{
+			Groups: map[string][]string{
+				"group:dev": {"alice@example.com", "alice@example.com"},
+			},
+			TagOwners: map[string][]string{
+				"tag:prod": {"group:ops"},
+			},
+			Hosts: map[string]string{
+				"db": "100.64.0.1",
+			},
+			ACLs: []ACLEntry{
+				{Action: "accept", Source: []string{"group:dev"}, Destination: []string{"db:5432", "web:80", "tag:prod:*", "[fd7a:115c:a1e0::1]:22", "192.0.2.0/24:*"}},
+				{Action: "deny", Source: []string{"*"}, Destination: []string{"*:*"}},
+				{Action: "accept", Source: []string{"tag:unknown"}},
+				{Action: ACLActionAccept, Protocol: "tcpp", Source: []string{"autogroup:members"}, Destination: []string{"*:*"}},
+			},
+			SSH: []ACLSSH{
+				{Action: "check", Source: []string{"group:dev"}, Destination: []string{"tag:prod"}, Users: []string{"root"}},
+				{
+					Action: "accept", Source: []string{"group:dev"}, Destination: []string{"tag:prod"}, Users: []string{"root"},
+					Recorder: []string{"tag:prod", "100.64.0.1:80", "tag:recorder", "recorder.example.com"}, AcceptEnv: []string{"GIT_*", "BAD-NAME"},
+				},
+				{Action: "accept", Source: []string{"group:dev"}, Destination: []string{"tag:prod"}, Users: []string{"root"}, EnforceRecorder: true},
+			},
+			Grants: []Grant{
+				{Source: []string{"ipset:missing"}, Destination: []string{"tag:prod"}},
+			},
+			AutoApprovers: &ACLAutoApprovers{
+				Routes: map[string][]string{
+					"10.0.0.0/8": {"tag:prod", "alice@example.com"},
+					"10.0.0.0":   {"group:ops"},
+				},
+				ExitNode: []string{"autogroup:member"},
+				Services: map[string][]string{
+					"svc:web": {"tag:prod"},
+					"web":     {"db"},
+				},
+			},
+			IPSets: map[string][]string{
+				"ipset:prod": {"add 192.0.2.0/24", "remove db", "drop 192.0.2.1"},
+				"prod":       {"192.0.2.0-192.0.2.9"},
+			},
+			NodeAttrs: []NodeAttrGrant{
+				NewAppConnectorNodeAttr([]string{"tag:prod"}, &NodeAttrGrantApp{Connectors: []string{"server"}}),
+			},
+		}
+
+		assert.Equal(t, []ACLProblem{
+			{Path: `groups["group:dev"]`, Message: `duplicate member "alice@example.com"`},
+			{Path: `tagOwners["tag:prod"]`, Message: `undefined group "group:ops"`},
+			{Path: `ipsets["ipset:prod"][2]`, Message: `invalid ipset entry "drop 192.0.2.1"`},
+			{Path: `ipsets["prod"]`, Message: `ipset names must start with "ipset:"`},
+			{Path: "acls[0].dst[1]", Message: `undefined host "web"`},
+			{Path: "acls[1]", Message: `unknown action "deny"`},
+			{Path: "acls[2]", Message: "no dst"},
+			{Path: "acls[2].src[0]", Message: `undefined tag "tag:unknown"`},
+			{Path: "acls[3]", Message: `unknown protocol "tcpp"`},
+			{Path: "acls[3].src[0]", Message: `unknown autogroup "autogroup:members"`},
+			{Path: "grants[0]", Message: "grant must have ip or app"},
+			{Path: "grants[0].src[0]", Message: `undefined ipset "ipset:missing"`},
+			{Path: "ssh[1].recorder[2]", Message: `undefined tag "tag:recorder"`},
+			{Path: "ssh[1].recorder[3]", Message: `invalid recorder "recorder.example.com": must be a tag or an IP address and port`},
+			{Path: "ssh[1].acceptEnv[1]", Message: `invalid environment variable pattern "BAD-NAME"`},
+			{Path: "ssh[2]", Message: "enforceRecorder requires a recorder"},
+			{Path: `autoApprovers.routes["10.0.0.0"]`, Message: `invalid route "10.0.0.0"`},
+			{Path: `autoApprovers.routes["10.0.0.0"][0]`, Message: `undefined group "group:ops"`},
+			{Path: `autoApprovers.services["web"]`, Message: `service names must start with "svc:"`},
+			{Path: `autoApprovers.services["web"][0]`, Message: `invalid approver "db": must be a tag, group, autogroup or user`},
+			{Path: `nodeAttrs[0].app["tailscale.com/app-connectors"][0]`, Message: "app connector has no name"},
+			{Path: `nodeAttrs[0].app["tailscale.com/app-connectors"][0]`, Message: `invalid connector "server": must be a tag`},
+		}, acl.Lint())
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		_, err := LintHuJSON([]byte(`{"acls": [`))
+		assert.Error(t, err)
+	})
+}
+
+func TestACLProblem_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "acls[1]: no dst", ACLProblem{Path: "acls[1]", Message: "no dst"}.String())
+}
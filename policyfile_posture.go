@@ -0,0 +1,254 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	PostureEqual          PostureOperator = "=="
+	PostureNotEqual       PostureOperator = "!="
+	PostureLess           PostureOperator = "<"
+	PostureLessOrEqual    PostureOperator = "<="
+	PostureGreater        PostureOperator = ">"
+	PostureGreaterOrEqual PostureOperator = ">="
+	PostureIn             PostureOperator = "IN"
+	PostureNotIn          PostureOperator = "NOT IN"
+	PostureIsSet          PostureOperator = "IS SET"
+	PostureNotSet         PostureOperator = "NOT SET"
+)
+
+// PostureOperator is the operator of a [PostureCondition].
+type PostureOperator string
+
+// PostureCondition is a single condition of a posture in the postures section of a policy file,
+// such as "node:os IN ['macos', 'windows']" or "node:tsVersion >= '1.60'". Conditions can be
+// built with [PostureAttr] and inspected with [ParsePostureCondition].
+// More details: https://tailscale.com/kb/1288/device-posture
+type PostureCondition string
+
+// Validate returns an error if c is not a syntactically valid posture condition.
+func (c PostureCondition) Validate() error {
+	_, err := ParsePostureCondition(string(c))
+	return err
+}
+
+// PostureExpr is the parsed form of a [PostureCondition]. Each value is either a string, for values
+// that are quoted in the condition, or a float64, for unquoted numbers.
+type PostureExpr struct {
+	Attribute string
+	Operator  PostureOperator
+	Values    []any
+}
+
+// ParsePostureCondition parses a posture condition like "node:os IN ['macos', 'windows']".
+func ParsePostureCondition(s string) (*PostureExpr, error) {
+	attr, rest, _ := strings.Cut(strings.TrimSpace(s), " ")
+	if ns, name, ok := strings.Cut(attr, ":"); !ok || ns == "" || name == "" {
+		return nil, fmt.Errorf("invalid posture condition %q: attribute must be of the form namespace:name", s)
+	}
+	rest = strings.TrimSpace(rest)
+
+	expr := &PostureExpr{Attribute: attr}
+	switch upper := strings.ToUpper(rest); {
+	case upper == string(PostureIsSet), upper == string(PostureNotSet):
+		expr.Operator = PostureOperator(upper)
+		return expr, nil
+	case strings.HasPrefix(upper, string(PostureNotIn)+" "), strings.HasPrefix(upper, string(PostureIn)+" "):
+		expr.Operator = PostureIn
+		if strings.HasPrefix(upper, string(PostureNotIn)) {
+			expr.Operator = PostureNotIn
+		}
+		list := strings.TrimSpace(rest[len(expr.Operator):])
+		if !strings.HasPrefix(list, "[") || !strings.HasSuffix(list, "]") {
+			return nil, fmt.Errorf("invalid posture condition %q: %s requires a list of values", s, expr.Operator)
+		}
+		items, err := splitPostureList(list[1 : len(list)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid posture condition %q: %w", s, err)
+		}
+		for _, v := range items {
+			value, err := parsePostureValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid posture condition %q: %w", s, err)
+			}
+			expr.Values = append(expr.Values, value)
+		}
+		return expr, nil
+	}
+
+	for _, op := range []PostureOperator{PostureEqual, PostureNotEqual, PostureLessOrEqual, PostureGreaterOrEqual, PostureLess, PostureGreater} {
+		if v, ok := strings.CutPrefix(rest, string(op)); ok {
+			value, err := parsePostureValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid posture condition %q: %w", s, err)
+			}
+			expr.Operator = op
+			expr.Values = []any{value}
+			return expr, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid posture condition %q: unknown operator", s)
+}
+
+// splitPostureList splits the contents of a posture list on the commas that separate its values,
+// ignoring commas inside quoted strings.
+func splitPostureList(s string) ([]string, error) {
+	var (
+		items []string
+		start int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in %q", strings.TrimSpace(s[start:]))
+	}
+	return append(items, s[start:]), nil
+}
+
+func parsePostureValue(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: strings must be quoted", s)
+	}
+	return f, nil
+}
+
+// Condition returns e formatted as a [PostureCondition].
+func (e *PostureExpr) Condition() PostureCondition {
+	values := make([]string, 0, len(e.Values))
+	for _, v := range e.Values {
+		switch v := v.(type) {
+		case string:
+			if strings.Contains(v, "'") {
+				values = append(values, `"`+v+`"`)
+			} else {
+				values = append(values, "'"+v+"'")
+			}
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	switch e.Operator {
+	case PostureIsSet, PostureNotSet:
+		return PostureCondition(e.Attribute + " " + string(e.Operator))
+	case PostureIn, PostureNotIn:
+		return PostureCondition(fmt.Sprintf("%s %s [%s]", e.Attribute, e.Operator, strings.Join(values, ", ")))
+	default:
+		return PostureCondition(fmt.Sprintf("%s %s %s", e.Attribute, e.Operator, strings.Join(values, "")))
+	}
+}
+
+// PostureAttribute builds [PostureCondition] values for a device posture attribute.
+//
+//	tailscale.PostureAttr("node:os").In("macos", "windows")
+//	tailscale.PostureAttr("node:tsVersion").GreaterOrEqual("1.60")
+//	tailscale.PostureAttr("falcon:ztaScore").Greater(70)
+type PostureAttribute string
+
+// PostureAttr returns a [PostureAttribute] for the named attribute, e.g. "node:os".
+func PostureAttr(name string) PostureAttribute {
+	return PostureAttribute(name)
+}
+
+func (a PostureAttribute) compare(op PostureOperator, value any) PostureCondition {
+	switch v := value.(type) {
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	}
+	return (&PostureExpr{Attribute: string(a), Operator: op, Values: []any{value}}).Condition()
+}
+
+func (a PostureAttribute) list(op PostureOperator, values []string) PostureCondition {
+	expr := &PostureExpr{Attribute: string(a), Operator: op}
+	for _, v := range values {
+		expr.Values = append(expr.Values, v)
+	}
+	return expr.Condition()
+}
+
+// Equal returns a condition matching devices whose attribute equals value,
+// which may be a string or a number.
+func (a PostureAttribute) Equal(value any) PostureCondition {
+	return a.compare(PostureEqual, value)
+}
+
+// NotEqual returns a condition matching devices whose attribute does not equal value.
+func (a PostureAttribute) NotEqual(value any) PostureCondition {
+	return a.compare(PostureNotEqual, value)
+}
+
+// Less returns a condition matching devices whose attribute is less than value.
+func (a PostureAttribute) Less(value any) PostureCondition {
+	return a.compare(PostureLess, value)
+}
+
+// LessOrEqual returns a condition matching devices whose attribute is at most value.
+func (a PostureAttribute) LessOrEqual(value any) PostureCondition {
+	return a.compare(PostureLessOrEqual, value)
+}
+
+// Greater returns a condition matching devices whose attribute is greater than value.
+func (a PostureAttribute) Greater(value any) PostureCondition {
+	return a.compare(PostureGreater, value)
+}
+
+// GreaterOrEqual returns a condition matching devices whose attribute is at least value.
+// Versions such as node:tsVersion are compared as versions when value is a string.
+func (a PostureAttribute) GreaterOrEqual(value any) PostureCondition {
+	return a.compare(PostureGreaterOrEqual, value)
+}
+
+// In returns a condition matching devices whose attribute is one of values.
+func (a PostureAttribute) In(values ...string) PostureCondition {
+	return a.list(PostureIn, values)
+}
+
+// NotIn returns a condition matching devices whose attribute is none of values.
+func (a PostureAttribute) NotIn(values ...string) PostureCondition {
+	return a.list(PostureNotIn, values)
+}
+
+// IsSet returns a condition matching devices that have the attribute.
+func (a PostureAttribute) IsSet() PostureCondition {
+	return PostureCondition(string(a) + " " + string(PostureIsSet))
+}
+
+// NotSet returns a condition matching devices that don't have the attribute.
+func (a PostureAttribute) NotSet() PostureCondition {
+	return PostureCondition(string(a) + " " + string(PostureNotSet))
+}
+
+// Posture defines the named posture (e.g. "posture:latestMac") as the given conditions,
+// all of which must be satisfied.
+func (b *ACLBuilder) Posture(name string, conditions ...PostureCondition) *ACLBuilder {
+	if b.acl.Postures == nil {
+		b.acl.Postures = make(map[string][]PostureCondition)
+	}
+	b.acl.Postures[name] = append(b.acl.Postures[name], conditions...)
+	return b
+}
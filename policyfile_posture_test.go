@@ -0,0 +1,127 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostureAttribute(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, PostureCondition("node:os IN ['macos', 'windows']"), PostureAttr("node:os").In("macos", "windows"))
+	assert.Equal(t, PostureCondition("node:os NOT IN ['linux']"), PostureAttr("node:os").NotIn("linux"))
+	assert.Equal(t, PostureCondition("node:tsVersion >= '1.60'"), PostureAttr("node:tsVersion").GreaterOrEqual("1.60"))
+	assert.Equal(t, PostureCondition("falcon:ztaScore > 70"), PostureAttr("falcon:ztaScore").Greater(70))
+	assert.Equal(t, PostureCondition("node:tsReleaseTrack == 'stable'"), PostureAttr("node:tsReleaseTrack").Equal("stable"))
+	assert.Equal(t, PostureCondition("custom:score <= 2.5"), PostureAttr("custom:score").LessOrEqual(2.5))
+	assert.Equal(t, PostureCondition("intune:complianceState IS SET"), PostureAttr("intune:complianceState").IsSet())
+	assert.Equal(t, PostureCondition("intune:complianceState NOT SET"), PostureAttr("intune:complianceState").NotSet())
+}
+
+func TestParsePostureCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		condition string
+		want      *PostureExpr
+		wantErr   string
+	}{
+		{
+			condition: "node:os IN ['macos', \"windows\"]",
+			want:      &PostureExpr{Attribute: "node:os", Operator: PostureIn, Values: []any{"macos", "windows"}},
+		},
+		{
+			condition: "custom:team IN ['ops, infra', \"it's\", 'sre']",
+			want:      &PostureExpr{Attribute: "custom:team", Operator: PostureIn, Values: []any{"ops, infra", "it's", "sre"}},
+		},
+		{
+			condition: "custom:team IN ['ops, infra]",
+			wantErr:   `invalid posture condition "custom:team IN ['ops, infra]": unterminated string in "'ops, infra"`,
+		},
+		{
+			condition: "node:os not in ['linux']",
+			want:      &PostureExpr{Attribute: "node:os", Operator: PostureNotIn, Values: []any{"linux"}},
+		},
+		{
+			condition: "node:tsVersion >= '1.60'",
+			want:      &PostureExpr{Attribute: "node:tsVersion", Operator: PostureGreaterOrEqual, Values: []any{"1.60"}},
+		},
+		{
+			condition: "falcon:ztaScore>70",
+			wantErr:   `invalid posture condition "falcon:ztaScore>70": unknown operator`,
+		},
+		{
+			condition: "falcon:ztaScore > 70",
+			want:      &PostureExpr{Attribute: "falcon:ztaScore", Operator: PostureGreater, Values: []any{70.0}},
+		},
+		{
+			condition: "node:tsAutoUpdate IS SET",
+			want:      &PostureExpr{Attribute: "node:tsAutoUpdate", Operator: PostureIsSet},
+		},
+		{
+			condition: "os IN ['macos']",
+			wantErr:   `invalid posture condition "os IN ['macos']": attribute must be of the form namespace:name`,
+		},
+		{
+			condition: "node:os == macos",
+			wantErr:   `invalid posture condition "node:os == macos": invalid value "macos": strings must be quoted`,
+		},
+		{
+			condition: "node:os IN 'macos'",
+			wantErr:   `invalid posture condition "node:os IN 'macos'": IN requires a list of values`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			got, err := ParsePostureCondition(tt.condition)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	expr, err := ParsePostureCondition("node:os in ['macos','windows']")
+	assert.NoError(t, err)
+	assert.Equal(t, PostureCondition("node:os IN ['macos', 'windows']"), expr.Condition())
+
+	expr, err = ParsePostureCondition(string(PostureAttr("custom:team").In("ops, infra", "it's")))
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"ops, infra", "it's"}, expr.Values)
+}
+
+func TestACL_Postures(t *testing.T) {
+	t.Parallel()
+
+	acl, err := NewACL().
+		Posture("posture:latestMac",
+			PostureAttr("node:os").In("macos"),
+			PostureAttr("node:tsVersion").GreaterOrEqual("1.60"),
+		).
+		Grant(Grant{Source: []string{"autogroup:member"}, Destination: []string{"*"}, IP: []string{"*"}, SrcPosture: []string{"posture:latestMac"}}).
+		Build()
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(acl)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"postures": {"posture:latestMac": ["node:os IN ['macos']", "node:tsVersion >= '1.60'"]},
+		"grants": [{"src": ["autogroup:member"], "dst": ["*"], "ip": ["*"], "srcPosture": ["posture:latestMac"]}]
+	}`, string(b))
+
+	acl.Postures["latest"] = []PostureCondition{"node:os = 'macos'"}
+	acl.DefaultSourcePosture = []string{"posture:missing"}
+	assert.ElementsMatch(t, []ACLProblem{
+		{Path: `postures["latest"]`, Message: `posture names must start with "posture:"`},
+		{Path: `postures["latest"][0]`, Message: `invalid posture condition "node:os = 'macos'": unknown operator`},
+		{Path: "defaultSrcPosture[0]", Message: `undefined posture "posture:missing"`},
+	}, acl.Lint())
+}
@@ -0,0 +1,111 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// policyFileSchemaEnums lists the allowed values of string fields, keyed by struct and field name,
+// that are rendered as enums in the JSON Schema.
+var policyFileSchemaEnums = map[string][]string{
+	"ACLEntry.Action": {ACLActionAccept},
+	"ACLSSH.Action":   {ACLActionAccept, ACLActionCheck},
+}
+
+// PolicyFileJSONSchema returns a JSON Schema (draft 2020-12) describing the policy file structure
+// understood by [ACL]. It is generated from the Go types in this package, and a copy is checked in
+// as policyfile.schema.json for use by editors and validators that don't use Go.
+//
+// The schema permits additional properties, so that policy files using sections this client does
+// not yet know about still validate.
+func PolicyFileJSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	root := g.structSchema(reflect.TypeFor[ACL]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "Tailscale policy file"
+	root["$defs"] = g.defs
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// schema returns the JSON Schema for t, adding any named struct types to the $defs section.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// json.RawMessage and other byte slices may hold any value.
+			return map[string]any{}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // Reserve the name to handle recursive types.
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the JSON Schema for the struct type t, based on its json struct tags.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if values, ok := policyFileSchemaEnums[t.Name()+"."+f.Name]; ok {
+			properties[name] = map[string]any{"type": "string", "enum": values}
+		} else {
+			properties[name] = g.schema(f.Type)
+		}
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var updateSchema = flag.Bool("update-schema", false, "regenerate policyfile.schema.json")
+
+func TestPolicyFileJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	got, err := PolicyFileJSONSchema()
+	assert.NoError(t, err)
+
+	if *updateSchema {
+		assert.NoError(t, os.WriteFile("policyfile.schema.json", got, 0o644))
+	}
+	want, err := os.ReadFile("policyfile.schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "policyfile.schema.json is out of date, run: go test -run TestPolicyFileJSONSchema -update-schema")
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Defs       map[string]struct {
+			Properties map[string]map[string]any `json:"properties"`
+			Required   []string                  `json:"required"`
+		} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(got, &schema))
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/ACLEntry"}}, schema.Properties["acls"])
+	assert.NotContains(t, schema.Properties, "ETag")
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"accept"}}, schema.Defs["ACLEntry"].Properties["action"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"accept", "check"}}, schema.Defs["ACLSSH"].Properties["action"])
+	assert.Equal(t, map[string]any{"type": "string"}, schema.Defs["ACLSSH"].Properties["checkPeriod"])
+	assert.Equal(t, []string{"regionID", "regionCode", "regionName", "nodes"}, schema.Defs["ACLDERPRegion"].Required)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+)
+
+// GetGroups returns the groups section of the tailnet's policy file.
+func (pr *PolicyFileResource) GetGroups(ctx context.Context) (map[string][]string, error) {
+	acl, err := pr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return acl.Groups, nil
+}
+
+// SetGroups replaces the groups section of the tailnet's policy file. The rest of the policy
+// file, including comments, is preserved, and the write is ETag-protected as in [PolicyFileResource.Edit].
+func (pr *PolicyFileResource) SetGroups(ctx context.Context, groups map[string][]string) error {
+	return pr.setSection(ctx, "groups", emptyIfNil(groups))
+}
+
+// GetHosts returns the hosts section of the tailnet's policy file.
+func (pr *PolicyFileResource) GetHosts(ctx context.Context) (map[string]string, error) {
+	acl, err := pr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return acl.Hosts, nil
+}
+
+// SetHosts replaces the hosts section of the tailnet's policy file, as in [PolicyFileResource.SetGroups].
+func (pr *PolicyFileResource) SetHosts(ctx context.Context, hosts map[string]string) error {
+	return pr.setSection(ctx, "hosts", emptyIfNil(hosts))
+}
+
+// GetTagOwners returns the tagOwners section of the tailnet's policy file.
+func (pr *PolicyFileResource) GetTagOwners(ctx context.Context) (map[string][]string, error) {
+	acl, err := pr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return acl.TagOwners, nil
+}
+
+// SetTagOwners replaces the tagOwners section of the tailnet's policy file, as in [PolicyFileResource.SetGroups].
+func (pr *PolicyFileResource) SetTagOwners(ctx context.Context, tagOwners map[string][]string) error {
+	return pr.setSection(ctx, "tagOwners", emptyIfNil(tagOwners))
+}
+
+func (pr *PolicyFileResource) setSection(ctx context.Context, name string, value any) error {
+	_, err := pr.Edit(ctx, func(e *PolicyEditor) error {
+		return e.SetSection(name, value)
+	})
+	return err
+}
+
+// emptyIfNil returns an empty map if m is nil, so that the section is written as {} rather than null.
+func emptyIfNil[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return map[string]V{}
+	}
+	return m
+}
@@ -0,0 +1,66 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_PolicyFileSections(t *testing.T) {
+	t.Parallel()
+
+	current := `{
+	// Engineering.
+	"groups": {"group:eng": ["alice@example.com"]},
+	"hosts": {"db": "100.64.0.1"},
+}`
+	var posted string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/acl", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			_, err := io.WriteString(w, current)
+			assert.NoError(t, err)
+		case http.MethodPost:
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			posted = string(b)
+			_, err = w.Write(b)
+			assert.NoError(t, err)
+		}
+	}))
+	ctx := context.Background()
+
+	groups, err := client.PolicyFile().GetGroups(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"group:eng": {"alice@example.com"}}, groups)
+
+	hosts, err := client.PolicyFile().GetHosts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"db": "100.64.0.1"}, hosts)
+
+	tagOwners, err := client.PolicyFile().GetTagOwners(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, tagOwners)
+
+	assert.NoError(t, client.PolicyFile().SetGroups(ctx, map[string][]string{"group:eng": {"bob@example.com"}}))
+	assert.Equal(t, `{
+	// Engineering.
+	"groups": {"group:eng":["bob@example.com"]},
+	"hosts": {"db": "100.64.0.1"},
+}`, posted)
+
+	assert.NoError(t, client.PolicyFile().SetTagOwners(ctx, nil))
+	assert.Contains(t, posted, `"tagOwners":{}`)
+
+	assert.NoError(t, client.PolicyFile().SetHosts(ctx, map[string]string{"web": "100.64.0.2"}))
+	assert.Contains(t, posted, `"hosts": {"web":"100.64.0.2"}`)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PolicySnapshot is a point-in-time copy of a tailnet's raw policy file, suitable for
+// saving with [PolicySnapshot.WriteFile] and restoring with [PolicyFileResource.Restore].
+type PolicySnapshot struct {
+	// Taken is the time at which the snapshot was taken.
+	Taken time.Time `json:"taken"`
+	// ETag is the ETag of the policy file at the time of the snapshot.
+	ETag string `json:"etag"`
+	// HuJSON is the raw policy file, including comments and formatting.
+	HuJSON string `json:"hujson"`
+}
+
+// Snapshot captures the current raw policy file of the tailnet.
+func (pr *PolicyFileResource) Snapshot(ctx context.Context) (*PolicySnapshot, error) {
+	raw, err := pr.Raw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicySnapshot{
+		Taken:  time.Now().UTC(),
+		ETag:   raw.ETag,
+		HuJSON: raw.HuJSON,
+	}, nil
+}
+
+// Restore replaces the tailnet's policy file with the one captured in snapshot and returns the
+// resulting policy file. The snapshot is validated before anything is changed, and the write is
+// made conditional on the ETag of the policy file read immediately beforehand, so that a
+// concurrent change is not silently overwritten; in that case the returned error satisfies
+// [IsPreconditionFailed]. If the policy file already matches the snapshot, it is left untouched.
+func (pr *PolicyFileResource) Restore(ctx context.Context, snapshot *PolicySnapshot) (*RawACL, error) {
+	if err := pr.Validate(ctx, snapshot.HuJSON); err != nil {
+		return nil, err
+	}
+
+	current, err := pr.Raw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current.HuJSON == snapshot.HuJSON {
+		return current, nil
+	}
+	return pr.SetRawAndGet(ctx, RawACL{HuJSON: snapshot.HuJSON, ETag: current.ETag})
+}
+
+// WriteFile saves the snapshot as JSON to the named file, which is created with
+// permissions that make it readable only by the current user.
+func (s *PolicySnapshot) WriteFile(name string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(b, '\n'), 0o600)
+}
+
+// ReadPolicySnapshot reads a snapshot previously saved with [PolicySnapshot.WriteFile].
+func ReadPolicySnapshot(name string) (*PolicySnapshot, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var s PolicySnapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("reading policy snapshot %s: %w", name, err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SnapshotAndRestoreACL(t *testing.T) {
+	t.Parallel()
+
+	current := `{"groups": {"group:dev": ["alice@example.com"]}}`
+	var validated, posted string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/tailnet/example.com/acl":
+			w.Header().Set("ETag", `"v1"`)
+			_, err := io.WriteString(w, current)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/tailnet/example.com/acl/validate":
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			validated = string(b)
+			_, err = io.WriteString(w, "{}")
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/tailnet/example.com/acl":
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Match"))
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			posted = string(b)
+			w.Header().Set("ETag", `"v2"`)
+			_, err = w.Write(b)
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+
+	snapshot, err := client.PolicyFile().Snapshot(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `"v1"`, snapshot.ETag)
+	assert.Equal(t, current, snapshot.HuJSON)
+	assert.False(t, snapshot.Taken.IsZero())
+
+	name := filepath.Join(t.TempDir(), "policy.json")
+	assert.NoError(t, snapshot.WriteFile(name))
+	restored, err := ReadPolicySnapshot(name)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.Taken.Unix(), restored.Taken.Unix())
+	assert.Equal(t, snapshot.HuJSON, restored.HuJSON)
+
+	// Restoring an unchanged policy file does not write anything.
+	raw, err := client.PolicyFile().Restore(context.Background(), restored)
+	assert.NoError(t, err)
+	assert.Equal(t, `"v1"`, raw.ETag)
+	assert.Empty(t, posted)
+
+	current = `{"groups": {}}`
+	raw, err = client.PolicyFile().Restore(context.Background(), restored)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.HuJSON, validated)
+	assert.Equal(t, snapshot.HuJSON, posted)
+	assert.Equal(t, `"v2"`, raw.ETag)
+}
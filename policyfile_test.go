@@ -7,7 +7,10 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -630,3 +633,416 @@ func TestSSHCheckPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestACL_GrantsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var acl ACL
+	assert.NoError(t, json.Unmarshal(jsonACL, &acl))
+	assert.Len(t, acl.Grants, 3)
+
+	b, err := json.Marshal(acl)
+	assert.NoError(t, err)
+
+	var roundTripped ACL
+	assert.NoError(t, json.Unmarshal(b, &roundTripped))
+	assert.Equal(t, acl.Grants, roundTripped.Grants)
+}
+
+func TestGrant_ViaAndSrcPosture(t *testing.T) {
+	t.Parallel()
+
+	grant := Grant{
+		Source:      []string{"group:eng"},
+		Destination: []string{"192.0.2.0/24"},
+		IP:          []string{"*"},
+		SrcPosture:  []string{"posture:latestMac"},
+		Via:         []string{"tag:office-router"},
+	}
+
+	b, err := json.Marshal(grant)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"src":["group:eng"],"dst":["192.0.2.0/24"],"ip":["*"],"srcPosture":["posture:latestMac"],"via":["tag:office-router"]}`, string(b))
+}
+
+func TestGrant_AppCapabilities(t *testing.T) {
+	t.Parallel()
+
+	type golinkCap struct {
+		Admin bool `json:"admin"`
+	}
+
+	var g Grant
+	assert.NoError(t, g.SetAppCapabilities(GrantAppCapabilities{
+		"tailscale.com/cap/golink": {json.RawMessage(`{"admin":true}`)},
+	}))
+	assert.Equal(t, map[string][]map[string]any{
+		"tailscale.com/cap/golink": {{"admin": true}},
+	}, g.App)
+
+	caps, err := g.AppCapabilities()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"admin":true}`, string(caps["tailscale.com/cap/golink"][0]))
+
+	decoded, err := DecodeAppCapability[golinkCap](caps, "tailscale.com/cap/golink")
+	assert.NoError(t, err)
+	assert.Equal(t, []golinkCap{{Admin: true}}, decoded)
+
+	missing, err := DecodeAppCapability[golinkCap](caps, "example.com/cap/missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	assert.NoError(t, g.SetAppCapabilities(GrantAppCapabilities{
+		"example.com/cap/quota": {json.RawMessage(`{"limit": 9007199254740993}`)},
+	}))
+	caps, err = g.AppCapabilities()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"limit":9007199254740993}`, string(caps["example.com/cap/quota"][0]))
+
+	assert.Error(t, g.SetAppCapabilities(GrantAppCapabilities{
+		"example.com/cap/bad": {json.RawMessage(`["not", "an", "object"]`)},
+	}))
+}
+
+func TestClient_SetRawACL(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	raw := RawACL{HuJSON: string(huJSONACL), ETag: "myetag"}
+	assert.NoError(t, client.PolicyFile().SetRaw(context.Background(), raw))
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/acl", server.Path)
+	assert.Equal(t, `"myetag"`, server.Header.Get("If-Match"))
+	assert.Equal(t, "application/hujson", server.Header.Get("Content-Type"))
+	assert.EqualValues(t, huJSONACL, server.Body.Bytes())
+
+	assert.NoError(t, client.PolicyFile().SetRaw(context.Background(), RawACL{HuJSON: "{}"}))
+	assert.Empty(t, server.Header.Get("If-Match"))
+}
+
+func TestClient_SetRawAndGetACL(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = huJSONACL
+	server.ResponseHeader.Set("ETag", "newetag")
+
+	out, err := client.PolicyFile().SetRawAndGet(context.Background(), RawACL{HuJSON: string(huJSONACL), ETag: `"oldetag"`})
+	assert.NoError(t, err)
+	assert.Equal(t, `"oldetag"`, server.Header.Get("If-Match"))
+	assert.Equal(t, "application/hujson", server.Header.Get("Accept"))
+	assert.Equal(t, &RawACL{HuJSON: string(huJSONACL), ETag: "newetag"}, out)
+}
+
+func TestClient_RunACLTests(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+
+	t.Run("passing tests", func(t *testing.T) {
+		server.ResponseCode = http.StatusOK
+		server.ResponseBody = map[string]any{}
+
+		failures, err := client.PolicyFile().RunTests(context.Background(), string(huJSONACL))
+		assert.NoError(t, err)
+		assert.Empty(t, failures)
+		assert.Equal(t, http.MethodPost, server.Method)
+		assert.Equal(t, "/api/v2/tailnet/example.com/acl/validate", server.Path)
+		assert.Equal(t, "application/hujson", server.Header.Get("Content-Type"))
+	})
+
+	t.Run("failing tests", func(t *testing.T) {
+		server.ResponseCode = http.StatusOK
+		server.ResponseBody = APIError{
+			Message: "test(s) failed",
+			Data: []APIErrorData{
+				{
+					User: "user1@example.com",
+					Errors: []string{
+						`address "user2@example.com:400": want: Accept, got: Drop`,
+						"something unexpected",
+					},
+				},
+			},
+		}
+
+		failures, err := client.PolicyFile().RunTests(context.Background(), ACL{})
+		assert.NoError(t, err)
+		assert.Equal(t, []ACLTestFailure{
+			{
+				Source:  "user1@example.com",
+				Address: "user2@example.com:400",
+				Want:    "Accept",
+				Got:     "Drop",
+				Message: `address "user2@example.com:400": want: Accept, got: Drop`,
+			},
+			{
+				Source:  "user1@example.com",
+				Message: "something unexpected",
+			},
+		}, failures)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		server.ResponseCode = http.StatusBadRequest
+		server.ResponseBody = APIError{Message: "line 3: unexpected token"}
+
+		failures, err := client.PolicyFile().RunTests(context.Background(), ACL{})
+		assert.Error(t, err)
+		assert.Nil(t, failures)
+	})
+
+	t.Run("other errors with data", func(t *testing.T) {
+		server.ResponseCode = http.StatusBadRequest
+		server.ResponseBody = APIError{
+			Message: "invalid grant",
+			Data:    []APIErrorData{{Errors: []string{"grants[0]: unknown field"}}},
+		}
+
+		failures, err := client.PolicyFile().RunTests(context.Background(), ACL{})
+		assert.Nil(t, failures)
+		assert.Equal(t, APIError{
+			Message: "invalid grant",
+			Data:    []APIErrorData{{Errors: []string{"grants[0]: unknown field"}}},
+			Status:  http.StatusBadRequest,
+		}, err)
+	})
+}
+
+func TestClient_UpdateACL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries on precondition failed", func(t *testing.T) {
+		var gets, sets int
+		client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/tailnet/example.com/acl", r.URL.Path)
+			switch r.Method {
+			case http.MethodGet:
+				gets++
+				w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, gets))
+				assert.NoError(t, json.NewEncoder(w).Encode(ACL{Groups: map[string][]string{"group:a": {"alice@example.com"}}}))
+			case http.MethodPost:
+				sets++
+				if r.Header.Get("If-Match") == `"v1"` {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "precondition failed"}))
+					return
+				}
+				w.Header().Set("ETag", `"v3"`)
+				_, err := io.Copy(w, r.Body)
+				assert.NoError(t, err)
+			}
+		}))
+
+		acl, err := client.PolicyFile().Update(context.Background(), func(acl *ACL) error {
+			acl.Groups["group:a"] = append(acl.Groups["group:a"], "bob@example.com")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, gets)
+		assert.Equal(t, 2, sets)
+		assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, acl.Groups["group:a"])
+		assert.Equal(t, `"v3"`, acl.ETag)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var sets int
+		client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				assert.NoError(t, json.NewEncoder(w).Encode(ACL{}))
+				return
+			}
+			sets++
+			w.WriteHeader(http.StatusPreconditionFailed)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "precondition failed"}))
+		}))
+
+		_, err := client.PolicyFile().UpdateWithRetry(context.Background(), 3, func(acl *ACL) error { return nil })
+		assert.True(t, IsPreconditionFailed(err))
+		assert.Equal(t, 3, sets)
+	})
+
+	t.Run("mutate error", func(t *testing.T) {
+		client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.NoError(t, json.NewEncoder(w).Encode(ACL{}))
+		}))
+
+		_, err := client.PolicyFile().Update(context.Background(), func(acl *ACL) error { return io.ErrUnexpectedEOF })
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+func TestACLConstants_IsValid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsValidACLAction(ACLActionAccept))
+	assert.True(t, IsValidACLAction(ACLActionCheck))
+	assert.False(t, IsValidACLAction("deny"))
+
+	assert.True(t, IsValidACLProtocol(ACLProtocolTCP))
+	assert.True(t, IsValidACLProtocol(ACLProtocolIPv6ICMP))
+	for _, proto := range []string{"ipv4", "ip-in-ip", "egp", "igp"} {
+		assert.True(t, IsValidACLProtocol(proto), proto)
+	}
+	assert.True(t, IsValidACLProtocol("17"))
+	assert.False(t, IsValidACLProtocol("256"))
+	assert.False(t, IsValidACLProtocol("tcpp"))
+
+	// The constants can be assigned to the string fields and to string variables.
+	var action string = ACLActionAccept
+	entry := ACLEntry{Action: action, Protocol: ACLProtocolUDP}
+	assert.Equal(t, "udp", entry.Protocol)
+
+	assert.True(t, AutogroupMember.IsValid())
+	assert.True(t, AutogroupInternet.IsValid())
+	assert.False(t, Autogroup("autogroup:members").IsValid())
+	assert.Equal(t, "autogroup:self", AutogroupSelf.String())
+}
+
+func TestACL_MarshalHuJSON(t *testing.T) {
+	t.Parallel()
+
+	acl := &ACL{
+		ACLs: []ACLEntry{
+			{Action: ACLActionAccept, Source: []string{"group:b"}, Destination: []string{"tag:x:*"}},
+		},
+		Groups: map[string][]string{
+			"group:b": {"bob@example.com"},
+			"group:a": {"alice@example.com"},
+		},
+		TagOwners:   map[string][]string{"tag:x": {"group:a"}},
+		DisableIPv4: true,
+	}
+
+	b, err := acl.MarshalHuJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+	"groups": {
+		"group:a": [
+			"alice@example.com"
+		],
+		"group:b": [
+			"bob@example.com"
+		]
+	},
+	"tagOwners": {
+		"tag:x": [
+			"group:a"
+		]
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src": [
+				"group:b"
+			],
+			"dst": [
+				"tag:x:*"
+			]
+		}
+	],
+	"disableIPv4": true
+}
+`, string(b))
+
+	again, err := acl.MarshalHuJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, b, again)
+
+	var decoded ACL
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, acl, &decoded)
+}
+
+func TestACL_IPSetEntries(t *testing.T) {
+	t.Parallel()
+
+	acl := &ACL{}
+	acl.SetIPSet("ipset:prod",
+		IPSetEntry{Operation: IPSetAdd, Value: "192.0.2.0/24"},
+		IPSetEntry{Operation: IPSetRemove, Value: "192.0.2.33"},
+	)
+	assert.Equal(t, map[string][]string{"ipset:prod": {"add 192.0.2.0/24", "remove 192.0.2.33"}}, acl.IPSets)
+
+	acl.IPSets["ipset:dev"] = []string{"198.51.100.1", "remove ipset:prod"}
+	entries, err := acl.IPSetEntries("ipset:dev")
+	assert.NoError(t, err)
+	assert.Equal(t, []IPSetEntry{
+		{Operation: IPSetAdd, Value: "198.51.100.1"},
+		{Operation: IPSetRemove, Value: "ipset:prod"},
+	}, entries)
+
+	entries, err = acl.IPSetEntries("ipset:missing")
+	assert.NoError(t, err)
+	assert.Nil(t, entries)
+
+	_, err = ParseIPSetEntry("subtract 192.0.2.1")
+	assert.EqualError(t, err, `invalid ipset entry "subtract 192.0.2.1"`)
+}
+
+func TestSSHRecorder_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, SSHRecorder("tag:recorder").Validate())
+	assert.NoError(t, SSHRecorder("100.64.0.1:80").Validate())
+	assert.NoError(t, SSHRecorder("[fd7a:115c:a1e0::1]:443").Validate())
+	assert.EqualError(t, SSHRecorder("tag:").Validate(), `invalid recorder "tag:": empty tag name`)
+	assert.EqualError(t, SSHRecorder("100.64.0.1").Validate(), `invalid recorder "100.64.0.1": must be a tag or an IP address and port`)
+}
+
+func TestACLAutoApprovers_Routes(t *testing.T) {
+	t.Parallel()
+
+	a := &ACLAutoApprovers{}
+	a.SetRouteApprovers(netip.MustParsePrefix("10.1.2.3/16"), "tag:router")
+	a.SetRouteApprovers(netip.MustParsePrefix("10.0.0.0/8"), "group:netops", "tag:router")
+	a.SetRouteApprovers(netip.MustParsePrefix("192.0.2.0/24"), "alice@example.com")
+	assert.Equal(t, map[string][]string{
+		"10.1.0.0/16":  {"tag:router"},
+		"10.0.0.0/8":   {"group:netops", "tag:router"},
+		"192.0.2.0/24": {"alice@example.com"},
+	}, a.Routes)
+
+	assert.Equal(t, []string{"group:netops", "tag:router"}, a.RouteApprovers(netip.MustParsePrefix("10.1.2.0/24")))
+	assert.Equal(t, []string{"group:netops", "tag:router"}, a.RouteApprovers(netip.MustParsePrefix("10.2.0.0/16")))
+	assert.Equal(t, []string{"alice@example.com"}, a.RouteApprovers(netip.MustParsePrefix("192.0.2.128/25")))
+	assert.Nil(t, a.RouteApprovers(netip.MustParsePrefix("0.0.0.0/0")))
+
+	routes, err := a.ParsedRoutes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, routes[netip.MustParsePrefix("192.0.2.0/24")])
+
+	a.Routes["not a route"] = nil
+	_, err = a.ParsedRoutes()
+	assert.ErrorContains(t, err, `invalid route "not a route"`)
+}
+
+func TestACLTest_ExtendedFields(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{"src":"alice@example.com","proto":"udp","srcPosture":["posture:latestMac"],"accept":["tag:dns:53"],"deny":["tag:prod:53"]}`
+
+	var test ACLTest
+	assert.NoError(t, json.Unmarshal([]byte(raw), &test))
+	assert.Equal(t, ACLTest{
+		Source:     "alice@example.com",
+		Proto:      ACLProtocolUDP,
+		SrcPosture: []string{"posture:latestMac"},
+		Accept:     []string{"tag:dns:53"},
+		Deny:       []string{"tag:prod:53"},
+	}, test)
+
+	b, err := json.Marshal(test)
+	assert.NoError(t, err)
+	assert.JSONEq(t, raw, string(b))
+
+	acl := &ACL{Tests: []ACLTest{{Source: "alice@example.com", Proto: "bogus", SrcPosture: []string{"posture:missing"}}}}
+	assert.ElementsMatch(t, []ACLProblem{
+		{Path: "tests[0]", Message: `unknown protocol "bogus"`},
+		{Path: "tests[0].srcPosture[0]", Message: `undefined posture "posture:missing"`},
+	}, acl.Lint())
+}
@@ -0,0 +1,106 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMinBackoff = 250 * time.Millisecond
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how the client retries requests that fail with a 429 or 5xx response,
+// or because of a network error. See [Client.RetryPolicy].
+type RetryPolicy struct {
+	// MaxRetries is the number of times to retry a failed request. Zero disables retries.
+	MaxRetries int
+	// MinBackoff is how long to wait before the first retry. The wait doubles for each further
+	// retry. Defaults to 250 milliseconds.
+	MinBackoff time.Duration
+	// MaxBackoff is the longest to wait between retries, including when the server asks to wait
+	// longer with a Retry-After header. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// backoff returns how long to wait before the given retry, counting from zero, of a request
+// that failed with err.
+func (p RetryPolicy) backoff(retry int, err error) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultRetryMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	var rae retryAfterError
+	if errors.As(err, &rae) {
+		return min(rae.after, maxBackoff)
+	}
+	d := minBackoff
+	for range retry {
+		if d >= maxBackoff {
+			break
+		}
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// wait waits before the given retry of a request that failed with err, returning early with
+// the context's error if ctx is done.
+func (p RetryPolicy) wait(ctx context.Context, retry int, err error) error {
+	t := time.NewTimer(p.backoff(retry, err))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryAfterError wraps an [APIError] from a response with a Retry-After header.
+type retryAfterError struct {
+	APIError
+	after time.Duration
+}
+
+func (e retryAfterError) Unwrap() error {
+	return e.APIError
+}
+
+// withRetryAfter wraps apiErr in a [retryAfterError] if header has a valid Retry-After value.
+func withRetryAfter(apiErr APIError, header http.Header) error {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return apiErr
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return retryAfterError{apiErr, time.Duration(seconds) * time.Second}
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return retryAfterError{apiErr, max(time.Until(t), 0)}
+	}
+	return apiErr
+}
+
+// isRetryableError reports whether a request that failed with err may succeed if retried: the
+// connection failed, or the server returned a 429 or 5xx error.
+func isRetryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == http.StatusTooManyRequests || apiErr.Status >= http.StatusInternalServerError
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	var p RetryPolicy
+	assert.Equal(t, 250*time.Millisecond, p.backoff(0, nil))
+	assert.Equal(t, time.Second, p.backoff(2, nil))
+	assert.Equal(t, 30*time.Second, p.backoff(100, nil))
+
+	p = RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, 4*time.Second, p.backoff(2, nil))
+	assert.Equal(t, 5*time.Second, p.backoff(3, nil))
+
+	header := http.Header{"Retry-After": {"3"}}
+	assert.Equal(t, 3*time.Second, p.backoff(0, withRetryAfter(APIError{Status: http.StatusTooManyRequests}, header)))
+	header.Set("Retry-After", "60")
+	assert.Equal(t, 5*time.Second, p.backoff(0, withRetryAfter(APIError{Status: http.StatusTooManyRequests}, header)))
+	header.Set("Retry-After", "soon")
+	assert.Equal(t, APIError{Status: http.StatusTooManyRequests}, withRetryAfter(APIError{Status: http.StatusTooManyRequests}, header))
+}
+
+func TestClient_GetNetworkFlowLogs_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var requests int
+	var starts []string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		starts = append(starts, r.URL.Query().Get("start"))
+		switch requests {
+		case 1:
+			b, err := json.Marshal(NetworkFlowLog{Logged: now.Add(-time.Minute), NodeID: "node1"})
+			assert.NoError(t, err)
+			_, err = w.Write(append(append([]byte(`{"logs":[`), b...), ','))
+			assert.NoError(t, err)
+			// Drop the connection before the response is complete.
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "rate limited"}))
+		default:
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"logs": []NetworkFlowLog{
+					{Logged: now.Add(-time.Minute), NodeID: "node1"},
+					{Logged: now, NodeID: "node2"},
+				},
+			}))
+		}
+	}))
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+
+	var nodeIDs []string
+	err := client.Logging().GetNetworkFlowLogs(context.Background(), NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}, func(log NetworkFlowLog) error {
+		nodeIDs = append(nodeIDs, log.NodeID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node1", "node2"}, nodeIDs)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, now.Add(-time.Minute).Format(time.RFC3339), starts[2])
+
+	// Other client errors are not retried.
+	requests = 0
+	client = NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+	err = client.Logging().GetNetworkFlowLogs(context.Background(), NetworkFlowLogsRequest{Start: now.Add(-time.Hour), End: now}, func(NetworkFlowLog) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -5,7 +5,14 @@ package tailscale
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
 )
 
 // TailnetSettingsResource provides access to https://tailscale.com/api#tag/tailnetsettings.
@@ -30,6 +37,31 @@ type TailnetSettings struct {
 	RegionalRoutingOn           bool `json:"regionalRoutingOn"`
 	PostureIdentityCollectionOn bool `json:"postureIdentityCollectionOn"`
 	HTTPSEnabled                bool `json:"httpsEnabled"`
+
+	// Unknown holds the settings returned by the API that this client doesn't know about, keyed by
+	// their JSON name. [TailnetSettingsResource.Apply] passes them on untouched, so that newer
+	// settings aren't reset by older versions of this client.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON encodes the settings along with any Unknown settings.
+func (s TailnetSettings) MarshalJSON() ([]byte, error) {
+	type alias TailnetSettings
+	return marshalWithUnknownFields(alias(s), s.Unknown)
+}
+
+// UnmarshalJSON decodes the settings, keeping any that this client doesn't know about in Unknown.
+func (s *TailnetSettings) UnmarshalJSON(b []byte) error {
+	type alias TailnetSettings
+	if err := json.Unmarshal(b, (*alias)(s)); err != nil {
+		return err
+	}
+	unknown, err := unknownFields(b, reflect.TypeFor[alias]())
+	if err != nil {
+		return err
+	}
+	s.Unknown = unknown
+	return nil
 }
 
 // UpdateTailnetSettingsRequest is a request to update the settings of a tailnet.
@@ -49,6 +81,85 @@ type UpdateTailnetSettingsRequest struct {
 	RegionalRoutingOn           *bool `json:"regionalRoutingOn,omitempty"`
 	PostureIdentityCollectionOn *bool `json:"postureIdentityCollectionOn,omitempty"`
 	HTTPSEnabled                *bool `json:"httpsEnabled,omitempty"`
+
+	// Unknown holds settings that this client doesn't know about, keyed by their JSON name, to be
+	// sent as-is. See [TailnetSettings].Unknown.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON encodes the request along with any Unknown settings.
+func (r UpdateTailnetSettingsRequest) MarshalJSON() ([]byte, error) {
+	type alias UpdateTailnetSettingsRequest
+	return marshalWithUnknownFields(alias(r), r.Unknown)
+}
+
+// marshalWithUnknownFields encodes v, a struct, adding the unknown fields that v has no field for.
+func marshalWithUnknownFields(v any, unknown map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return b, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range unknown {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unknownFields returns the fields of the JSON object b that the struct type t has no field for,
+// or nil if there are none.
+func unknownFields(b []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+const (
+	// MinDevicesKeyDuration is the shortest device key expiry that a tailnet can be configured with.
+	MinDevicesKeyDuration = 24 * time.Hour
+	// MaxDevicesKeyDuration is the longest device key expiry that a tailnet can be configured with.
+	MaxDevicesKeyDuration = 180 * 24 * time.Hour
+)
+
+// DevicesKeyDuration returns DevicesKeyDurationDays as a [time.Duration].
+func (s *TailnetSettings) DevicesKeyDuration() time.Duration {
+	return time.Duration(s.DevicesKeyDurationDays) * 24 * time.Hour
+}
+
+// SetDevicesKeyDuration sets DevicesKeyDurationDays from d, which must be a whole number of days
+// between [MinDevicesKeyDuration] and [MaxDevicesKeyDuration].
+func (r *UpdateTailnetSettingsRequest) SetDevicesKeyDuration(d time.Duration) error {
+	if err := ValidateDevicesKeyDuration(d); err != nil {
+		return err
+	}
+	r.DevicesKeyDurationDays = PointerTo(int(d / (24 * time.Hour)))
+	return nil
+}
+
+// ValidateDevicesKeyDuration checks that d is a whole number of days between
+// [MinDevicesKeyDuration] and [MaxDevicesKeyDuration].
+func ValidateDevicesKeyDuration(d time.Duration) error {
+	if d < MinDevicesKeyDuration || d > MaxDevicesKeyDuration {
+		return fmt.Errorf("device key duration %s must be between 1 and 180 days", d)
+	}
+	if d%(24*time.Hour) != 0 {
+		return fmt.Errorf("device key duration %s must be a whole number of days", d)
+	}
+	return nil
 }
 
 // RoleAllowedToJoinExternalTailnets constrains which users are allowed to join external tailnets
@@ -72,9 +183,38 @@ func (tsr *TailnetSettingsResource) Get(ctx context.Context) (*TailnetSettings,
 	return body[TailnetSettings](tsr, req)
 }
 
-// Update updates the tailnet settings.
+// Validate checks the settings set in the request before they are sent: ACLsExternalLink must be
+// an absolute URL (or empty, to clear it), DevicesKeyDurationDays must be between 1 and 180 and
+// UsersRoleAllowedToJoinExternalTailnets must be a known role. All problems found are returned.
+func (r *UpdateTailnetSettingsRequest) Validate() error {
+	var errs []error
+	if r.ACLsExternalLink != nil && *r.ACLsExternalLink != "" {
+		if u, err := url.Parse(*r.ACLsExternalLink); err != nil || !u.IsAbs() || u.Host == "" {
+			errs = append(errs, fmt.Errorf("ACLs external link %q is not an absolute URL", *r.ACLsExternalLink))
+		}
+	}
+	if r.DevicesKeyDurationDays != nil {
+		if days := *r.DevicesKeyDurationDays; days < 1 || days > int(MaxDevicesKeyDuration/(24*time.Hour)) {
+			errs = append(errs, fmt.Errorf("device key duration of %d days must be between 1 and 180 days", days))
+		}
+	}
+	if r.UsersRoleAllowedToJoinExternalTailnets != nil {
+		switch role := *r.UsersRoleAllowedToJoinExternalTailnets; role {
+		case RoleAllowedToJoinExternalTailnetsNone, RoleAllowedToJoinExternalTailnetsAdmin, RoleAllowedToJoinExternalTailnetsMember:
+		default:
+			errs = append(errs, fmt.Errorf("unknown role allowed to join external tailnets %q, must be one of none, admin, member", role))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Update updates the tailnet settings. The request is checked with
+// [UpdateTailnetSettingsRequest.Validate] first, and rejected without making a request if invalid.
 // See https://tailscale.com/api#tag/tailnetsettings/PATCH/tailnet/{tailnet}/settings.
 func (tsr *TailnetSettingsResource) Update(ctx context.Context, request UpdateTailnetSettingsRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
 	req, err := tsr.buildRequest(ctx, http.MethodPatch, tsr.buildTailnetURL("settings"), requestBody(request))
 	if err != nil {
 		return err
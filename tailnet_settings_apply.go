@@ -0,0 +1,107 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Apply converges the tailnet's settings on desired: it fetches the current settings, computes
+// the minimal patch with [DiffTailnetSettings] and only sends it if something differs, so it is
+// safe to call repeatedly. Returns the patch that was applied, or nil if nothing changed.
+//
+// Settings that this client doesn't know about are sent back untouched with the patch, unless
+// desired sets them in [TailnetSettings].Unknown.
+func (tsr *TailnetSettingsResource) Apply(ctx context.Context, desired TailnetSettings) (*UpdateTailnetSettingsRequest, error) {
+	current, err := tsr.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := DiffTailnetSettings(current, &desired)
+	if patch == nil {
+		return nil, nil
+	}
+	if err := tsr.Update(ctx, *patch); err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// DiffTailnetSettings returns an [UpdateTailnetSettingsRequest] that only sets the settings that
+// differ between current and desired, or nil if there are none. Unknown settings differ if desired
+// sets them to a different value than current; the patch carries the Unknown settings of current,
+// updated with those of desired.
+func DiffTailnetSettings(current, desired *TailnetSettings) *UpdateTailnetSettingsRequest {
+	patch, changed := diffTailnetSettings(current, desired)
+	if len(changed) == 0 {
+		return nil
+	}
+	return patch
+}
+
+// diffTailnetSettings returns the patch from current to desired, along with the JSON names of
+// the settings that it changes.
+func diffTailnetSettings(current, desired *TailnetSettings) (*UpdateTailnetSettingsRequest, []string) {
+	var patch UpdateTailnetSettingsRequest
+	var changed []string
+	diff := func(name string, dst **bool, cur, want bool) {
+		if cur != want {
+			*dst = PointerTo(want)
+			changed = append(changed, name)
+		}
+	}
+
+	diff("aclsExternallyManagedOn", &patch.ACLsExternallyManagedOn, current.ACLsExternallyManagedOn, desired.ACLsExternallyManagedOn)
+	if current.ACLsExternalLink != desired.ACLsExternalLink {
+		patch.ACLsExternalLink = PointerTo(desired.ACLsExternalLink)
+		changed = append(changed, "aclsExternalLink")
+	}
+	diff("devicesApprovalOn", &patch.DevicesApprovalOn, current.DevicesApprovalOn, desired.DevicesApprovalOn)
+	diff("devicesAutoUpdatesOn", &patch.DevicesAutoUpdatesOn, current.DevicesAutoUpdatesOn, desired.DevicesAutoUpdatesOn)
+	if current.DevicesKeyDurationDays != desired.DevicesKeyDurationDays {
+		patch.DevicesKeyDurationDays = PointerTo(desired.DevicesKeyDurationDays)
+		changed = append(changed, "devicesKeyDurationDays")
+	}
+	diff("usersApprovalOn", &patch.UsersApprovalOn, current.UsersApprovalOn, desired.UsersApprovalOn)
+	if current.UsersRoleAllowedToJoinExternalTailnets != desired.UsersRoleAllowedToJoinExternalTailnets {
+		patch.UsersRoleAllowedToJoinExternalTailnets = PointerTo(desired.UsersRoleAllowedToJoinExternalTailnets)
+		changed = append(changed, "usersRoleAllowedToJoinExternalTailnets")
+	}
+	diff("networkFlowLoggingOn", &patch.NetworkFlowLoggingOn, current.NetworkFlowLoggingOn, desired.NetworkFlowLoggingOn)
+	diff("regionalRoutingOn", &patch.RegionalRoutingOn, current.RegionalRoutingOn, desired.RegionalRoutingOn)
+	diff("postureIdentityCollectionOn", &patch.PostureIdentityCollectionOn, current.PostureIdentityCollectionOn, desired.PostureIdentityCollectionOn)
+	diff("httpsEnabled", &patch.HTTPSEnabled, current.HTTPSEnabled, desired.HTTPSEnabled)
+
+	var changedUnknown []string
+	for name, want := range desired.Unknown {
+		if cur, ok := current.Unknown[name]; !ok || !jsonEqual(cur, want) {
+			changedUnknown = append(changedUnknown, name)
+		}
+	}
+	slices.Sort(changedUnknown)
+	changed = append(changed, changedUnknown...)
+	if len(current.Unknown) > 0 || len(desired.Unknown) > 0 {
+		patch.Unknown = maps.Clone(current.Unknown)
+		if patch.Unknown == nil {
+			patch.Unknown = make(map[string]json.RawMessage, len(desired.Unknown))
+		}
+		maps.Copy(patch.Unknown, desired.Unknown)
+	}
+
+	return &patch, changed
+}
+
+// jsonEqual reports whether a and b are the same JSON, ignoring insignificant whitespace.
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
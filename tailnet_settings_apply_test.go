@@ -0,0 +1,104 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTailnetSettings(t *testing.T) {
+	t.Parallel()
+
+	current := TailnetSettings{
+		DevicesApprovalOn:                      true,
+		DevicesKeyDurationDays:                 180,
+		UsersRoleAllowedToJoinExternalTailnets: RoleAllowedToJoinExternalTailnetsAdmin,
+		HTTPSEnabled:                           true,
+	}
+	assert.Nil(t, DiffTailnetSettings(&current, &current))
+
+	desired := current
+	desired.DevicesApprovalOn = false
+	desired.DevicesKeyDurationDays = 90
+	desired.ACLsExternalLink = "https://github.com/example/acls"
+	assert.Equal(t, &UpdateTailnetSettingsRequest{
+		ACLsExternalLink:       PointerTo("https://github.com/example/acls"),
+		DevicesApprovalOn:      PointerTo(false),
+		DevicesKeyDurationDays: PointerTo(90),
+	}, DiffTailnetSettings(&current, &desired))
+}
+
+func TestClient_TailnetSettings_Apply(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	current := TailnetSettings{DevicesApprovalOn: true, DevicesKeyDurationDays: 180}
+	var patches []string
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/settings", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			var patch json.RawMessage
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			patches = append(patches, string(patch))
+			assert.NoError(t, json.Unmarshal(patch, &current))
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(current))
+	}))
+	ctx := context.Background()
+
+	desired := TailnetSettings{DevicesApprovalOn: true, DevicesKeyDurationDays: 90, HTTPSEnabled: true}
+	patch, err := client.TailnetSettings().Apply(ctx, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, &UpdateTailnetSettingsRequest{
+		DevicesKeyDurationDays: PointerTo(90),
+		HTTPSEnabled:           PointerTo(true),
+	}, patch)
+	if assert.Len(t, patches, 1) {
+		var sent UpdateTailnetSettingsRequest
+		assert.NoError(t, json.Unmarshal([]byte(patches[0]), &sent))
+		assert.Equal(t, patch, &sent)
+	}
+
+	// Applying the same settings again is a no-op.
+	patch, err = client.TailnetSettings().Apply(ctx, desired)
+	assert.NoError(t, err)
+	assert.Nil(t, patch)
+	assert.Len(t, patches, 1)
+}
+
+func TestClient_TailnetSettings_ApplyUnknown(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = []byte(`{"devicesApprovalOn":false,"futureSettingOn":true,"futureLimit":5}`)
+	ctx := context.Background()
+
+	patch, err := client.TailnetSettings().Apply(ctx, TailnetSettings{DevicesApprovalOn: true})
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, server.Method)
+	assert.JSONEq(t, `{"aclsExternallyManagedOn":null,"aclsExternalLink":null,"devicesApprovalOn":true,"futureSettingOn":true,"futureLimit":5}`, server.Body.String())
+	assert.Equal(t, PointerTo(true), patch.DevicesApprovalOn)
+
+	// Unknown settings only cause a patch when desired changes them.
+	server.Method = ""
+	patch, err = client.TailnetSettings().Apply(ctx, TailnetSettings{Unknown: map[string]json.RawMessage{"futureLimit": json.RawMessage(` 5 `)}})
+	assert.NoError(t, err)
+	assert.Nil(t, patch)
+	assert.Equal(t, http.MethodGet, server.Method)
+
+	patch, err = client.TailnetSettings().Apply(ctx, TailnetSettings{Unknown: map[string]json.RawMessage{"futureLimit": json.RawMessage(`10`)}})
+	assert.NoError(t, err)
+	assert.NotNil(t, patch)
+	assert.JSONEq(t, `{"aclsExternallyManagedOn":null,"aclsExternalLink":null,"futureSettingOn":true,"futureLimit":10}`, server.Body.String())
+}
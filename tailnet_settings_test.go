@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,3 +70,90 @@ func TestClient_TailnetSettings_Update(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, updateRequest, receivedRequest)
 }
+
+func TestTailnetSettings_DevicesKeyDuration(t *testing.T) {
+	t.Parallel()
+
+	settings := TailnetSettings{DevicesKeyDurationDays: 90}
+	assert.Equal(t, 90*24*time.Hour, settings.DevicesKeyDuration())
+
+	var req UpdateTailnetSettingsRequest
+	assert.NoError(t, req.SetDevicesKeyDuration(30*24*time.Hour))
+	assert.Equal(t, PointerTo(30), req.DevicesKeyDurationDays)
+	assert.NoError(t, req.SetDevicesKeyDuration(MinDevicesKeyDuration))
+	assert.Equal(t, PointerTo(1), req.DevicesKeyDurationDays)
+	assert.NoError(t, req.SetDevicesKeyDuration(MaxDevicesKeyDuration))
+	assert.Equal(t, PointerTo(180), req.DevicesKeyDurationDays)
+
+	assert.EqualError(t, req.SetDevicesKeyDuration(12*time.Hour), "device key duration 12h0m0s must be between 1 and 180 days")
+	assert.EqualError(t, req.SetDevicesKeyDuration(181*24*time.Hour), "device key duration 4344h0m0s must be between 1 and 180 days")
+	assert.EqualError(t, req.SetDevicesKeyDuration(36*time.Hour), "device key duration 36h0m0s must be a whole number of days")
+	assert.Equal(t, PointerTo(180), req.DevicesKeyDurationDays, "invalid durations must not change the request")
+}
+
+func TestTailnetSettings_UnknownFields(t *testing.T) {
+	t.Parallel()
+
+	var settings TailnetSettings
+	assert.NoError(t, json.Unmarshal([]byte(`{"devicesApprovalOn":true,"futureSettingOn":true,"futureLimit":{"max": 5}}`), &settings))
+	assert.True(t, settings.DevicesApprovalOn)
+	assert.Equal(t, map[string]json.RawMessage{
+		"futureSettingOn": json.RawMessage(`true`),
+		"futureLimit":     json.RawMessage(`{"max": 5}`),
+	}, settings.Unknown)
+
+	b, err := json.Marshal(settings)
+	assert.NoError(t, err)
+	var roundTripped TailnetSettings
+	assert.NoError(t, json.Unmarshal(b, &roundTripped))
+	assert.Equal(t, settings.DevicesApprovalOn, roundTripped.DevicesApprovalOn)
+	assert.JSONEq(t, `{"max":5}`, string(roundTripped.Unknown["futureLimit"]))
+
+	settings = TailnetSettings{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"devicesApprovalOn":true}`), &settings))
+	assert.Nil(t, settings.Unknown)
+
+	b, err = json.Marshal(UpdateTailnetSettingsRequest{
+		DevicesApprovalOn: PointerTo(false),
+		Unknown: map[string]json.RawMessage{
+			"futureSettingOn":   json.RawMessage(`true`),
+			"devicesApprovalOn": json.RawMessage(`true`),
+		},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"aclsExternallyManagedOn":null,"aclsExternalLink":null,"devicesApprovalOn":false,"futureSettingOn":true}`, string(b))
+}
+
+func TestUpdateTailnetSettingsRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&UpdateTailnetSettingsRequest{}).Validate())
+	assert.NoError(t, (&UpdateTailnetSettingsRequest{
+		ACLsExternalLink:                       PointerTo("https://github.com/example/acls"),
+		DevicesKeyDurationDays:                 PointerTo(180),
+		UsersRoleAllowedToJoinExternalTailnets: PointerTo(RoleAllowedToJoinExternalTailnetsNone),
+	}).Validate())
+	assert.NoError(t, (&UpdateTailnetSettingsRequest{ACLsExternalLink: PointerTo("")}).Validate())
+
+	err := (&UpdateTailnetSettingsRequest{
+		ACLsExternalLink:                       PointerTo("github.com/example/acls"),
+		DevicesKeyDurationDays:                 PointerTo(0),
+		UsersRoleAllowedToJoinExternalTailnets: PointerTo(RoleAllowedToJoinExternalTailnets("owner")),
+	}).Validate()
+	assert.EqualError(t, err, `ACLs external link "github.com/example/acls" is not an absolute URL
+device key duration of 0 days must be between 1 and 180 days
+unknown role allowed to join external tailnets "owner", must be one of none, admin, member`)
+
+	assert.Error(t, (&UpdateTailnetSettingsRequest{DevicesKeyDurationDays: PointerTo(181)}).Validate())
+}
+
+func TestClient_TailnetSettings_UpdateInvalid(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.TailnetSettings().Update(context.Background(), UpdateTailnetSettingsRequest{DevicesKeyDurationDays: PointerTo(365)})
+	assert.EqualError(t, err, "device key duration of 365 days must be between 1 and 180 days")
+	assert.Empty(t, server.Method)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TailnetSettingsChange describes how a tailnet's settings changed between two checks by a
+// [TailnetSettingsWatcher].
+type TailnetSettingsChange struct {
+	// Old is the settings before the change.
+	Old *TailnetSettings
+	// New is the settings after the change.
+	New *TailnetSettings
+	// Changed lists the JSON names of the settings that changed, such as "devicesApprovalOn".
+	Changed []string
+}
+
+// Empty reports whether nothing changed.
+func (c *TailnetSettingsChange) Empty() bool {
+	return len(c.Changed) == 0
+}
+
+// TailnetSettingsWatcher polls a tailnet's settings and reports changes, such as device approval
+// being disabled in the admin console. Use [TailnetSettingsResource.NewWatcher] to create one.
+type TailnetSettingsWatcher struct {
+	settings *TailnetSettingsResource
+
+	mu       sync.Mutex // protects baseline
+	baseline *TailnetSettings
+}
+
+// NewWatcher returns a [TailnetSettingsWatcher] for the tailnet's settings. Unless a baseline is
+// set with [TailnetSettingsWatcher.SetBaseline], the settings seen by the first check become the
+// baseline.
+func (tsr *TailnetSettingsResource) NewWatcher() *TailnetSettingsWatcher {
+	return &TailnetSettingsWatcher{settings: tsr}
+}
+
+// SetBaseline sets the settings that the next check is compared against, such as the desired
+// settings of a configuration management tool.
+func (w *TailnetSettingsWatcher) SetBaseline(settings TailnetSettings) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.baseline = &settings
+}
+
+// Check fetches the current settings and compares them to the baseline, which is then updated to
+// the current settings. If there is no baseline yet, Check establishes it and reports no change.
+func (w *TailnetSettingsWatcher) Check(ctx context.Context) (*TailnetSettingsChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current, err := w.settings.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	old := w.baseline
+	w.baseline = current
+	if old == nil {
+		return &TailnetSettingsChange{Old: current, New: current}, nil
+	}
+	_, changed := diffTailnetSettings(old, current)
+	return &TailnetSettingsChange{Old: old, New: current, Changed: changed}, nil
+}
+
+// Watch calls [TailnetSettingsWatcher.Check] every interval until ctx is done, calling handle for
+// every change found. It returns when ctx is done, or with the first error from Check or handle.
+// The interval must be positive.
+func (w *TailnetSettingsWatcher) Watch(ctx context.Context, interval time.Duration, handle func(ctx context.Context, change *TailnetSettingsChange) error) error {
+	return poll(ctx, interval, func(ctx context.Context) error {
+		change, err := w.Check(ctx)
+		if err != nil {
+			return err
+		}
+		if !change.Empty() {
+			if err := handle(ctx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Revert restores the settings from before change and makes them the baseline again. Only the
+// settings that changed are updated.
+func (w *TailnetSettingsWatcher) Revert(ctx context.Context, change *TailnetSettingsChange) error {
+	if patch := DiffTailnetSettings(change.New, change.Old); patch != nil {
+		if err := w.settings.Update(ctx, *patch); err != nil {
+			return err
+		}
+	}
+	w.SetBaseline(*change.Old)
+	return nil
+}
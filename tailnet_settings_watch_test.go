@@ -0,0 +1,89 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailnetSettingsWatcher(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	current := TailnetSettings{DevicesApprovalOn: true, UsersApprovalOn: true, DevicesKeyDurationDays: 180}
+	var patches []UpdateTailnetSettingsRequest
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/settings", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			var patch UpdateTailnetSettingsRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			patches = append(patches, patch)
+			if patch.DevicesApprovalOn != nil {
+				current.DevicesApprovalOn = *patch.DevicesApprovalOn
+			}
+			if patch.DevicesKeyDurationDays != nil {
+				current.DevicesKeyDurationDays = *patch.DevicesKeyDurationDays
+			}
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(current))
+	}))
+	ctx := context.Background()
+	watcher := client.TailnetSettings().NewWatcher()
+
+	change, err := watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.True(t, change.Empty())
+
+	mu.Lock()
+	current.DevicesApprovalOn = false
+	current.DevicesKeyDurationDays = 30
+	mu.Unlock()
+
+	change, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TailnetSettingsChange{
+		Old:     &TailnetSettings{DevicesApprovalOn: true, UsersApprovalOn: true, DevicesKeyDurationDays: 180},
+		New:     &TailnetSettings{UsersApprovalOn: true, DevicesKeyDurationDays: 30},
+		Changed: []string{"devicesApprovalOn", "devicesKeyDurationDays"},
+	}, change)
+
+	assert.NoError(t, watcher.Revert(ctx, change))
+	assert.Equal(t, []UpdateTailnetSettingsRequest{{DevicesApprovalOn: PointerTo(true), DevicesKeyDurationDays: PointerTo(180)}}, patches)
+	assert.True(t, current.DevicesApprovalOn)
+
+	change, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.True(t, change.Empty())
+
+	watcher.SetBaseline(TailnetSettings{DevicesApprovalOn: true, DevicesKeyDurationDays: 180})
+	errStop := errors.New("stop")
+	var changes []*TailnetSettingsChange
+	err = watcher.Watch(ctx, time.Millisecond, func(_ context.Context, change *TailnetSettingsChange) error {
+		changes = append(changes, change)
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, []string{"usersApprovalOn"}, changes[0].Changed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = watcher.Watch(ctx, time.Millisecond, func(context.Context, *TailnetSettingsChange) error {
+		t.Error("unexpected change")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -11,8 +11,12 @@ import (
 	"maps"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +35,13 @@ type TestServer struct {
 	ResponseCode   int
 	ResponseBody   interface{}
 	ResponseHeader http.Header
+
+	// WebhookEndpoints maps webhook endpoint IDs to the URLs that test events requested with
+	// Webhooks().Test are delivered to, signed with WebhookSecret, as the API would.
+	WebhookEndpoints map[string]string
+	WebhookSecret    string
+
+	deliveries sync.WaitGroup // tracks asynchronous webhook deliveries
 }
 
 func NewTestHarness(t *testing.T) (*Client, *TestServer) {
@@ -59,6 +70,8 @@ func NewTestHarness(t *testing.T) (*Client, *TestServer) {
 	t.Cleanup(func() {
 		assert.NoError(t, svr.Close())
 	})
+	// Wait for webhook deliveries first, so that they don't report to a finished test.
+	t.Cleanup(testServer.deliveries.Wait)
 
 	baseURL := fmt.Sprintf("http://localhost:%v", listener.Addr().(*net.TCPAddr).Port)
 	testServer.BaseURL, err = url.Parse(baseURL)
@@ -82,6 +95,20 @@ func (t *TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_, err := io.Copy(t.Body, r.Body)
 	assert.NoError(t.t, err)
 
+	if id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/webhooks/"), "/test"); ok && r.Method == http.MethodPost {
+		if endpoint, ok := t.WebhookEndpoints[id]; ok {
+			// Deliver the test event asynchronously, once the response has been sent.
+			t.deliveries.Add(1)
+			go func() {
+				defer t.deliveries.Done()
+				event, err := SampleWebhookEvent(WebhookTestEvent)
+				assert.NoError(t.t, err)
+				_, err = t.DeliverWebhook(endpoint, event)
+				assert.NoError(t.t, err)
+			}()
+		}
+	}
+
 	maps.Copy(w.Header(), t.ResponseHeader)
 	w.WriteHeader(t.ResponseCode)
 	if t.ResponseBody != nil {
@@ -94,3 +121,41 @@ func (t *TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// DeliverWebhook delivers events to endpointURL, signed with WebhookSecret, and returns the
+// status code of the response.
+func (t *TestServer) DeliverWebhook(endpointURL string, events ...WebhookEvent) (int, error) {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(body, t.WebhookSecret, time.Now()))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// NewTestClient returns a Client that sends its requests to a test server backed by
+// handler, for tests that need to serve more than one endpoint.
+func NewTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	svr := httptest.NewServer(handler)
+	t.Cleanup(svr.Close)
+
+	baseURL, err := url.Parse(svr.URL)
+	assert.NoError(t, err)
+	return &Client{
+		BaseURL: baseURL,
+		APIKey:  "not a real key",
+		Tailnet: "example.com",
+	}
+}
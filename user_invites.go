@@ -0,0 +1,109 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// UserInvitesResource provides access to https://tailscale.com/api#tag/userinvites.
+type UserInvitesResource struct {
+	*Client
+}
+
+// UserInvite is an invitation for a user to join the tailnet.
+type UserInvite struct {
+	ID        string   `json:"id"`
+	Role      UserRole `json:"role"`
+	TailnetID string   `json:"tailnetId"`
+	// InviterID is the ID of the user that created the invite.
+	InviterID string `json:"inviterId"`
+	// Email is the address that the invite was sent to, if any.
+	Email string `json:"email,omitempty"`
+	// LastEmailSentAt is when the invite was last emailed to Email.
+	LastEmailSentAt time.Time `json:"lastEmailSentAt,omitzero"`
+	// InviteURL is the URL with which the invite can be accepted.
+	InviteURL string `json:"inviteUrl"`
+	// Expires is when the invite can no longer be accepted, if it expires.
+	Expires time.Time `json:"expires,omitzero"`
+}
+
+// IsExpired reports whether the invite had expired at time t.
+func (ui *UserInvite) IsExpired(t time.Time) bool {
+	return !ui.Expires.IsZero() && !t.Before(ui.Expires)
+}
+
+// CreateUserInviteRequest describes an invite to create with [UserInvitesResource.Create].
+type CreateUserInviteRequest struct {
+	// Role is the role the invited user is given. Defaults to [UserRoleMember].
+	Role UserRole `json:"role,omitempty"`
+	// Email, if set, is an address that the invite is emailed to. Without it, the returned
+	// [UserInvite.InviteURL] must be passed to the user some other way.
+	Email string `json:"email,omitempty"`
+}
+
+// Create creates an invite for each of the given requests, and returns the created invites
+// with the URLs with which they can be accepted.
+func (uir *UserInvitesResource) Create(ctx context.Context, requests []CreateUserInviteRequest) ([]UserInvite, error) {
+	req, err := uir.buildRequest(ctx, http.MethodPost, uir.buildTailnetURL("user-invites"), requestBody(requests))
+	if err != nil {
+		return nil, err
+	}
+
+	var invites []UserInvite
+	if err := uir.do(req, &invites); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// List lists every outstanding [UserInvite] of the tailnet.
+func (uir *UserInvitesResource) List(ctx context.Context) ([]UserInvite, error) {
+	req, err := uir.buildRequest(ctx, http.MethodGet, uir.buildTailnetURL("user-invites"))
+	if err != nil {
+		return nil, err
+	}
+
+	var invites []UserInvite
+	if err := uir.do(req, &invites); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// Get retrieves the [UserInvite] identified by the given inviteID.
+func (uir *UserInvitesResource) Get(ctx context.Context, inviteID string) (*UserInvite, error) {
+	req, err := uir.buildRequest(ctx, http.MethodGet, uir.buildURL("user-invites", inviteID))
+	if err != nil {
+		return nil, err
+	}
+
+	return body[UserInvite](uir, req)
+}
+
+// Delete deletes the [UserInvite] identified by the given inviteID, so that it can no longer
+// be accepted.
+func (uir *UserInvitesResource) Delete(ctx context.Context, inviteID string) error {
+	req, err := uir.buildRequest(ctx, http.MethodDelete, uir.buildURL("user-invites", inviteID))
+	if err != nil {
+		return err
+	}
+
+	return uir.do(req, nil)
+}
+
+// Resend emails the [UserInvite] identified by the given inviteID again, such as when the
+// invited user lost the original email. Only invites created with an email address can be resent.
+func (uir *UserInvitesResource) Resend(ctx context.Context, inviteID string) error {
+	req, err := uir.buildRequest(ctx, http.MethodPost, uir.buildURL("user-invites", inviteID, "resend"))
+	if err != nil {
+		return err
+	}
+
+	return uir.do(req, nil)
+}
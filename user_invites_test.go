@@ -0,0 +1,133 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_UserInvites_Create(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	requests := []CreateUserInviteRequest{
+		{Role: UserRoleAdmin, Email: "jane@example.com"},
+		{},
+	}
+	expectedInvites := []UserInvite{
+		{
+			ID:              "12345",
+			Role:            UserRoleAdmin,
+			TailnetID:       "1",
+			InviterID:       "6789",
+			Email:           "jane@example.com",
+			LastEmailSentAt: time.Date(2022, 2, 10, 11, 50, 23, 0, time.UTC),
+			InviteURL:       "https://login.tailscale.com/uinv/abc",
+		},
+		{
+			ID:        "12346",
+			Role:      UserRoleMember,
+			TailnetID: "1",
+			InviterID: "6789",
+			InviteURL: "https://login.tailscale.com/uinv/def",
+		},
+	}
+	server.ResponseBody = expectedInvites
+
+	invites, err := client.UserInvites().Create(context.Background(), requests)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/user-invites", server.Path)
+	assert.Equal(t, expectedInvites, invites)
+
+	var actualRequests []CreateUserInviteRequest
+	assert.NoError(t, json.Unmarshal(server.Body.Bytes(), &actualRequests))
+	assert.Equal(t, requests, actualRequests)
+	assert.JSONEq(t, `[{"role":"admin","email":"jane@example.com"},{}]`, server.Body.String())
+}
+
+func TestClient_UserInvites_List(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	expectedInvites := []UserInvite{
+		{
+			ID:              "12345",
+			Role:            UserRoleAuditor,
+			TailnetID:       "1",
+			InviterID:       "6789",
+			Email:           "jane@example.com",
+			LastEmailSentAt: time.Date(2022, 2, 10, 11, 50, 23, 0, time.UTC),
+			InviteURL:       "https://login.tailscale.com/uinv/abc",
+			Expires:         time.Date(2022, 3, 10, 11, 50, 23, 0, time.UTC),
+		},
+	}
+	server.ResponseBody = expectedInvites
+
+	invites, err := client.UserInvites().List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/user-invites", server.Path)
+	assert.Equal(t, expectedInvites, invites)
+
+	assert.False(t, invites[0].IsExpired(time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, invites[0].IsExpired(time.Date(2022, 3, 10, 11, 50, 23, 0, time.UTC)))
+	assert.False(t, (&UserInvite{}).IsExpired(time.Now()))
+}
+
+func TestClient_UserInvites_Get(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	expectedInvite := &UserInvite{
+		ID:        "12345",
+		Role:      UserRoleMember,
+		TailnetID: "1",
+		InviterID: "6789",
+		InviteURL: "https://login.tailscale.com/uinv/abc",
+	}
+	server.ResponseBody = expectedInvite
+
+	invite, err := client.UserInvites().Get(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/user-invites/12345", server.Path)
+	assert.Equal(t, expectedInvite, invite)
+}
+
+func TestClient_UserInvites_Delete(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.UserInvites().Delete(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, server.Method)
+	assert.Equal(t, "/api/v2/user-invites/12345", server.Path)
+}
+
+func TestClient_UserInvites_Resend(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.UserInvites().Resend(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/user-invites/12345/resend", server.Path)
+}
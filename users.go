@@ -5,7 +5,11 @@ package tailscale
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -63,8 +67,75 @@ type User struct {
 }
 
 // List lists every [User] of the tailnet. If userType and/or role are provided,
-// the list of users will be filtered by those.
-func (ur *UsersResource) List(ctx context.Context, userType *UserType, role *UserRole) ([]User, error) {
+// the list of users will be filtered by those. The users can be further filtered with options
+// such as [WithUserStatus]; the API doesn't support those filters, so they are applied client-side.
+func (ur *UsersResource) List(ctx context.Context, userType *UserType, role *UserRole, opts ...ListUsersOptions) ([]User, error) {
+	o := newListUsersOptions(opts)
+	req, err := ur.buildListRequest(ctx, userType, role)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make(map[string][]User)
+	if err = ur.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	users := resp["users"]
+	if !o.filtered() {
+		return users, nil
+	}
+	return slices.DeleteFunc(users, func(u User) bool { return !o.matches(&u) }), nil
+}
+
+// ListUsersOptions filters the users returned by [UsersResource.List] and [UsersResource.Iter].
+type ListUsersOptions func(*listUsersOptions)
+
+func newListUsersOptions(opts []ListUsersOptions) *listUsersOptions {
+	var o listUsersOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+type listUsersOptions struct {
+	statuses  []UserStatus
+	connected *bool
+}
+
+// WithUserStatus only lists users with one of the given statuses, such as
+// [UserStatusNeedsApproval]. It may be given more than once.
+func WithUserStatus(statuses ...UserStatus) ListUsersOptions {
+	return func(o *listUsersOptions) {
+		o.statuses = append(o.statuses, statuses...)
+	}
+}
+
+// WithUserConnected only lists users that are, or are not, currently connected to the tailnet.
+func WithUserConnected(connected bool) ListUsersOptions {
+	return func(o *listUsersOptions) {
+		o.connected = &connected
+	}
+}
+
+// filtered reports whether any option filters the listed users.
+func (o *listUsersOptions) filtered() bool {
+	return len(o.statuses) > 0 || o.connected != nil
+}
+
+func (o *listUsersOptions) matches(u *User) bool {
+	if len(o.statuses) > 0 && !slices.Contains(o.statuses, u.Status) {
+		return false
+	}
+	if o.connected != nil && u.CurrentlyConnected != *o.connected {
+		return false
+	}
+	return true
+}
+
+// buildListRequest builds the request made by [UsersResource.List] and [UsersResource.Iter].
+func (ur *UsersResource) buildListRequest(ctx context.Context, userType *UserType, role *UserRole) (*http.Request, error) {
 	u := ur.buildTailnetURL("users")
 	q := u.Query()
 	if userType != nil {
@@ -75,25 +146,136 @@ func (ur *UsersResource) List(ctx context.Context, userType *UserType, role *Use
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := ur.buildRequest(ctx, http.MethodGet, u)
+	return ur.buildRequest(ctx, http.MethodGet, u)
+}
+
+// Get retrieves the [User] identified by the given id.
+func (ur *UsersResource) Get(ctx context.Context, id string) (*User, error) {
+	req, err := ur.buildRequest(ctx, http.MethodGet, ur.buildURL("users", id))
 	if err != nil {
 		return nil, err
 	}
 
-	resp := make(map[string][]User)
-	if err = ur.do(req, &resp); err != nil {
-		return nil, err
+	return body[User](ur, req)
+}
+
+// ErrUserNotFound is returned by [UsersResource.GetByLoginName] when no user has the login name.
+var ErrUserNotFound = errors.New("user not found")
+
+// AmbiguousUserError is returned by [UsersResource.GetByLoginName] when more than one user has the
+// login name, such as a member and a user shared into the tailnet.
+type AmbiguousUserError struct {
+	LoginName string
+	// Users are the users with the login name.
+	Users []User
+}
+
+func (e *AmbiguousUserError) Error() string {
+	return fmt.Sprintf("%d users have login name %q", len(e.Users), e.LoginName)
+}
+
+// GetByLoginName retrieves the [User] with the given login name, such as an email address. The
+// API can't look users up by login name, so this lists the tailnet's users and picks the one whose
+// login name matches exactly, ignoring case. If no user matches, an error wrapping
+// [ErrUserNotFound] is returned; if several do, an [*AmbiguousUserError] is returned.
+func (ur *UsersResource) GetByLoginName(ctx context.Context, loginName string) (*User, error) {
+	var matches []User
+	for u, err := range ur.Iter(ctx, nil, nil) {
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(u.LoginName, loginName) {
+			matches = append(matches, u)
+		}
 	}
 
-	return resp["users"], nil
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrUserNotFound, loginName)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, &AmbiguousUserError{LoginName: loginName, Users: matches}
+	}
 }
 
-// Get retrieves the [User] identified by the given id.
-func (ur *UsersResource) Get(ctx context.Context, id string) (*User, error) {
-	req, err := ur.buildRequest(ctx, http.MethodGet, ur.buildURL("users", id))
+// Approve approves the pending [User] identified by the given id, allowing them to access the tailnet.
+// See https://tailscale.com/api#tag/users/POST/users/{userId}/approve.
+func (ur *UsersResource) Approve(ctx context.Context, id string) error {
+	req, err := ur.buildRequest(ctx, http.MethodPost, ur.buildURL("users", id, "approve"))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return body[User](ur, req)
+	return ur.do(req, nil)
+}
+
+// Suspend suspends the [User] identified by the given id, preventing them from accessing the tailnet
+// until they are restored with [UsersResource.Restore].
+func (ur *UsersResource) Suspend(ctx context.Context, id string) error {
+	req, err := ur.buildRequest(ctx, http.MethodPost, ur.buildURL("users", id, "suspend"))
+	if err != nil {
+		return err
+	}
+
+	return ur.do(req, nil)
+}
+
+// Restore restores the suspended [User] identified by the given id.
+func (ur *UsersResource) Restore(ctx context.Context, id string) error {
+	req, err := ur.buildRequest(ctx, http.MethodPost, ur.buildURL("users", id, "restore"))
+	if err != nil {
+		return err
+	}
+
+	return ur.do(req, nil)
+}
+
+// UpdateRole changes the role of the [User] identified by the given id.
+func (ur *UsersResource) UpdateRole(ctx context.Context, id string, role UserRole) error {
+	req, err := ur.buildRequest(ctx, http.MethodPost, ur.buildURL("users", id, "role"), requestBody(map[string]UserRole{
+		"role": role,
+	}))
+	if err != nil {
+		return err
+	}
+
+	return ur.do(req, nil)
+}
+
+// UserDeletionBlockedError is returned by [UsersResource.Delete] when the API refuses to delete a
+// user with a 409 Conflict, for example because they are the tailnet's last owner. It wraps the
+// [APIError] returned by the API.
+type UserDeletionBlockedError struct {
+	// UserID is the ID of the user that could not be deleted.
+	UserID string
+	// Reason is the API's explanation of why the user could not be deleted.
+	Reason string
+
+	err APIError
+}
+
+func (e *UserDeletionBlockedError) Error() string {
+	return fmt.Sprintf("cannot delete user %s: %s", e.UserID, e.Reason)
+}
+
+func (e *UserDeletionBlockedError) Unwrap() error {
+	return e.err
+}
+
+// Delete deletes the [User] identified by the given id, along with their devices. If the API
+// refuses to delete the user because of a conflict, for example because they are the tailnet's
+// last owner, a [*UserDeletionBlockedError] is returned. Other API errors are returned unchanged.
+func (ur *UsersResource) Delete(ctx context.Context, id string) error {
+	req, err := ur.buildRequest(ctx, http.MethodPost, ur.buildURL("users", id, "delete"))
+	if err != nil {
+		return err
+	}
+
+	err = ur.do(req, nil)
+	var apiErr APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+		return &UserDeletionBlockedError{UserID: id, Reason: apiErr.Message, err: apiErr}
+	}
+	return err
 }
@@ -0,0 +1,88 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AllUserTypes returns every [UserType] known to this client.
+func AllUserTypes() []UserType {
+	return []UserType{UserTypeMember, UserTypeShared}
+}
+
+// IsValid reports whether t is a [UserType] known to this client.
+func (t UserType) IsValid() bool {
+	return slices.Contains(AllUserTypes(), t)
+}
+
+// ParseUserType parses s as a [UserType], ignoring case and surrounding whitespace, so that
+// configuration can be checked before it is passed to the API.
+func ParseUserType(s string) (UserType, error) {
+	return parseEnum("user type", s, AllUserTypes())
+}
+
+// AllUserRoles returns every [UserRole] known to this client.
+func AllUserRoles() []UserRole {
+	return []UserRole{
+		UserRoleOwner,
+		UserRoleMember,
+		UserRoleAdmin,
+		UserRoleITAdmin,
+		UserRoleNetworkAdmin,
+		UserRoleBillingAdmin,
+		UserRoleAuditor,
+	}
+}
+
+// IsValid reports whether r is a [UserRole] known to this client.
+func (r UserRole) IsValid() bool {
+	return slices.Contains(AllUserRoles(), r)
+}
+
+// ParseUserRole parses s as a [UserRole], ignoring case and surrounding whitespace, so that
+// configuration can be checked before it is passed to the API.
+func ParseUserRole(s string) (UserRole, error) {
+	return parseEnum("user role", s, AllUserRoles())
+}
+
+// AllUserStatuses returns every [UserStatus] known to this client.
+func AllUserStatuses() []UserStatus {
+	return []UserStatus{
+		UserStatusActive,
+		UserStatusIdle,
+		UserStatusSuspended,
+		UserStatusNeedsApproval,
+		UserStatusOverBillingLimit,
+	}
+}
+
+// IsValid reports whether s is a [UserStatus] known to this client.
+func (s UserStatus) IsValid() bool {
+	return slices.Contains(AllUserStatuses(), s)
+}
+
+// ParseUserStatus parses s as a [UserStatus], ignoring case and surrounding whitespace, so that
+// configuration can be checked before it is passed to the API.
+func ParseUserStatus(s string) (UserStatus, error) {
+	return parseEnum("user status", s, AllUserStatuses())
+}
+
+// parseEnum returns the value in values that matches s, ignoring case and surrounding whitespace.
+// name describes the values in the returned error.
+func parseEnum[T ~string](name, s string, values []T) (T, error) {
+	s = strings.TrimSpace(s)
+	for _, v := range values {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	valid := make([]string, len(values))
+	for i, v := range values {
+		valid[i] = string(v)
+	}
+	return "", fmt.Errorf("unknown %s %q, must be one of %s", name, s, strings.Join(valid, ", "))
+}
@@ -0,0 +1,46 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserEnums(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range AllUserRoles() {
+		assert.True(t, r.IsValid(), r)
+	}
+	assert.False(t, UserRole("superuser").IsValid())
+	assert.False(t, UserRole("").IsValid())
+	assert.True(t, UserTypeShared.IsValid())
+	assert.False(t, UserType("guest").IsValid())
+	assert.True(t, UserStatusOverBillingLimit.IsValid())
+	assert.False(t, UserStatus("deleted").IsValid())
+	assert.True(t, ContactSecurity.IsValid())
+	assert.False(t, ContactType("billing").IsValid())
+
+	role, err := ParseUserRole(" IT-Admin\n")
+	assert.NoError(t, err)
+	assert.Equal(t, UserRoleITAdmin, role)
+	_, err = ParseUserRole("superuser")
+	assert.EqualError(t, err, `unknown user role "superuser", must be one of owner, member, admin, it-admin, network-admin, billing-admin, auditor`)
+
+	userType, err := ParseUserType("member")
+	assert.NoError(t, err)
+	assert.Equal(t, UserTypeMember, userType)
+
+	status, err := ParseUserStatus("Needs-Approval")
+	assert.NoError(t, err)
+	assert.Equal(t, UserStatusNeedsApproval, status)
+	_, err = ParseUserStatus("")
+	assert.Error(t, err)
+
+	contactType, err := ParseContactType("SUPPORT")
+	assert.NoError(t, err)
+	assert.Equal(t, ContactSupport, contactType)
+}
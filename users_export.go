@@ -0,0 +1,97 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// UserExportFormat is a format that users can be exported in by a [UserExporter].
+type UserExportFormat string
+
+const (
+	// UserExportFormatNDJSON writes each [User] as a line of JSON, as returned by the API.
+	UserExportFormatNDJSON UserExportFormat = "ndjson"
+	// UserExportFormatCSV writes each [User] as a CSV record, after a header record.
+	UserExportFormatCSV UserExportFormat = "csv"
+)
+
+// userCSVHeader is the header record written by [UserExportFormatCSV].
+var userCSVHeader = []string{"id", "loginName", "displayName", "type", "role", "status", "created", "lastSeen", "deviceCount", "currentlyConnected"}
+
+func userCSVRecord(u *User) []string {
+	var lastSeen string
+	if !u.LastSeen.IsZero() {
+		lastSeen = u.LastSeen.Format(time.RFC3339)
+	}
+	return []string{
+		u.ID,
+		u.LoginName,
+		u.DisplayName,
+		string(u.Type),
+		string(u.Role),
+		string(u.Status),
+		u.Created.Format(time.RFC3339),
+		lastSeen,
+		strconv.Itoa(u.DeviceCount),
+		strconv.FormatBool(u.CurrentlyConnected),
+	}
+}
+
+// UserExporter writes users to an [io.Writer] in a [UserExportFormat]. Output is buffered, so
+// call Flush once all users have been written. It is not safe for concurrent use.
+type UserExporter struct {
+	out *recordWriter
+}
+
+// NewUserExporter returns a [UserExporter] that writes to w in the given format.
+func NewUserExporter(w io.Writer, format UserExportFormat) (*UserExporter, error) {
+	switch format {
+	case UserExportFormatNDJSON:
+		return &UserExporter{out: newNDJSONWriter(w)}, nil
+	case UserExportFormatCSV:
+		return &UserExporter{out: newCSVWriter(w, userCSVHeader)}, nil
+	default:
+		return nil, fmt.Errorf("unknown user export format %q", format)
+	}
+}
+
+// Write writes user.
+func (e *UserExporter) Write(user User) error {
+	if e.out.csv != nil {
+		return e.out.writeCSV(userCSVRecord(&user))
+	}
+	return e.out.writeJSON(user)
+}
+
+// Flush writes any buffered output to the underlying writer. For [UserExportFormatCSV], the
+// header record is written even if no users were.
+func (e *UserExporter) Flush() error {
+	return e.out.flush()
+}
+
+// Export streams the tailnet's users to w in the given format, for compliance and license
+// reporting. Users are fetched with [UsersResource.Iter], and can be filtered with the same
+// options. See [UserExporter].
+func (ur *UsersResource) Export(ctx context.Context, w io.Writer, format UserExportFormat, opts ...ListUsersOptions) error {
+	e, err := NewUserExporter(w, format)
+	if err != nil {
+		return err
+	}
+	for user, err := range ur.Iter(ctx, nil, nil, opts...) {
+		if err == nil {
+			err = e.Write(user)
+		}
+		if err != nil {
+			// Write out what was exported before the failure.
+			return errors.Join(err, e.Flush())
+		}
+	}
+	return e.Flush()
+}
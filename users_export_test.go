@@ -0,0 +1,94 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testExportUsers() []User {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []User{
+		{
+			ID:                 "1",
+			DisplayName:        "Jane Doe",
+			LoginName:          "jane@example.com",
+			Created:            created,
+			Type:               UserTypeMember,
+			Role:               UserRoleAdmin,
+			Status:             UserStatusActive,
+			DeviceCount:        3,
+			LastSeen:           created.Add(time.Hour),
+			CurrentlyConnected: true,
+		},
+		{
+			ID:          "2",
+			DisplayName: "Doe, John",
+			LoginName:   "john@example.com",
+			Created:     created,
+			Type:        UserTypeShared,
+			Role:        UserRoleMember,
+			Status:      UserStatusNeedsApproval,
+		},
+	}
+}
+
+func TestUserExporter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[UserExportFormat]string{
+		UserExportFormatNDJSON: `{"id":"1","displayName":"Jane Doe","loginName":"jane@example.com","profilePicUrl":"","tailnetId":"","created":"2025-01-02T03:04:05Z","type":"member","role":"admin","status":"active","deviceCount":3,"lastSeen":"2025-01-02T04:04:05Z","currentlyConnected":true}
+{"id":"2","displayName":"Doe, John","loginName":"john@example.com","profilePicUrl":"","tailnetId":"","created":"2025-01-02T03:04:05Z","type":"shared","role":"member","status":"needs-approval","deviceCount":0,"lastSeen":"0001-01-01T00:00:00Z","currentlyConnected":false}
+`,
+		UserExportFormatCSV: `id,loginName,displayName,type,role,status,created,lastSeen,deviceCount,currentlyConnected
+1,jane@example.com,Jane Doe,member,admin,active,2025-01-02T03:04:05Z,2025-01-02T04:04:05Z,3,true
+2,john@example.com,"Doe, John",shared,member,needs-approval,2025-01-02T03:04:05Z,,0,false
+`,
+	}
+	for format, want := range tests {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			e, err := NewUserExporter(&buf, format)
+			assert.NoError(t, err)
+			for _, u := range testExportUsers() {
+				assert.NoError(t, e.Write(u))
+			}
+			assert.NoError(t, e.Flush())
+			assert.Equal(t, want, buf.String())
+		})
+	}
+
+	_, err := NewUserExporter(&bytes.Buffer{}, "xml")
+	assert.EqualError(t, err, `unknown user export format "xml"`)
+}
+
+func TestClient_Users_Export(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string][]User{"users": testExportUsers()}
+
+	var buf bytes.Buffer
+	err := client.Users().Export(context.Background(), &buf, UserExportFormatCSV, WithUserStatus(UserStatusNeedsApproval))
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v2/tailnet/example.com/users", server.Path)
+	assert.Equal(t, `id,loginName,displayName,type,role,status,created,lastSeen,deviceCount,currentlyConnected
+2,john@example.com,"Doe, John",shared,member,needs-approval,2025-01-02T03:04:05Z,,0,false
+`, buf.String())
+
+	server.ResponseCode = http.StatusForbidden
+	server.ResponseBody = APIError{Message: "forbidden"}
+	buf.Reset()
+	err = client.Users().Export(context.Background(), &buf, UserExportFormatCSV)
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "id,loginName,displayName,type,role,status,created,lastSeen,deviceCount,currentlyConnected\n", buf.String())
+}
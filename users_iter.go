@@ -0,0 +1,40 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"iter"
+)
+
+// Iter returns an iterator over the users of the tailnet, accepting the same arguments as
+// [UsersResource.List]. The API returns every user in a single response, so rather than fetching
+// pages, users are decoded from the response one at a time as they are read, allowing tailnets
+// with tens of thousands of users to be processed without holding the whole list in memory.
+//
+// The request is made when iteration starts. If it fails, or a user cannot be decoded, the error
+// is yielded with a zero [User] and iteration stops. Stopping iteration early closes the response.
+func (ur *UsersResource) Iter(ctx context.Context, userType *UserType, role *UserRole, opts ...ListUsersOptions) iter.Seq2[User, error] {
+	return func(yield func(User, error) bool) {
+		o := newListUsersOptions(opts)
+		req, err := ur.buildListRequest(ctx, userType, role)
+		if err != nil {
+			yield(User{}, err)
+			return
+		}
+
+		err = streamArray(ur.Client, req, "users", func(u User) error {
+			if o.filtered() && !o.matches(&u) {
+				return nil
+			}
+			if !yield(u, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil {
+			yield(User{}, err)
+		}
+	}
+}
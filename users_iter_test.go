@@ -0,0 +1,64 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Users_Iter(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string]any{
+		"users": []User{
+			{ID: "1", LoginName: "a@example.com"},
+			{ID: "2", LoginName: "b@example.com"},
+			{ID: "3", LoginName: "c@example.com"},
+		},
+	}
+
+	var ids []string
+	for u, err := range client.Users().Iter(context.Background(), PointerTo(UserTypeMember), PointerTo(UserRoleAdmin)) {
+		assert.NoError(t, err)
+		ids = append(ids, u.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/users", server.Path)
+	assert.Equal(t, url.Values{"type": {"member"}, "role": {"admin"}}, server.Query)
+
+	// Stopping early must not yield further users or errors.
+	ids = nil
+	for u, err := range client.Users().Iter(context.Background(), nil, nil) {
+		assert.NoError(t, err)
+		ids = append(ids, u.ID)
+		break
+	}
+	assert.Equal(t, []string{"1"}, ids)
+	assert.Empty(t, server.Query)
+}
+
+func TestClient_Users_IterError(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusForbidden
+	server.ResponseBody = APIError{Message: "forbidden"}
+
+	var errs []error
+	for _, err := range client.Users().Iter(context.Background(), nil, nil) {
+		errs = append(errs, err)
+	}
+	assert.Len(t, errs, 1)
+	var apiErr APIError
+	assert.ErrorAs(t, errs[0], &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+}
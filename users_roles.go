@@ -0,0 +1,160 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// defaultUpdateRoleConcurrency is the number of roles updated at once by [UsersResource.UpdateRoles]
+// when [UpdateRolesOptions.Concurrency] is not set.
+const defaultUpdateRoleConcurrency = 4
+
+// UpdateRolesOptions controls how [UsersResource.UpdateRoles] updates roles.
+type UpdateRolesOptions struct {
+	// Concurrency is the maximum number of role updates in flight at once. Defaults to 4.
+	Concurrency int
+}
+
+// UpdateRolesError is returned by [UsersResource.UpdateRoles] when the roles of some users could
+// not be updated.
+type UpdateRolesError struct {
+	// Errors holds the error for each user ID whose role was not updated.
+	Errors map[string]error
+}
+
+func (e *UpdateRolesError) Error() string {
+	ids := slices.Sorted(maps.Keys(e.Errors))
+	problems := make([]string, len(ids))
+	for i, id := range ids {
+		problems[i] = fmt.Sprintf("%s: %v", id, e.Errors[id])
+	}
+	return fmt.Sprintf("failed to update the roles of %d users: %s", len(ids), strings.Join(problems, "; "))
+}
+
+func (e *UpdateRolesError) Unwrap() []error {
+	return slices.Collect(maps.Values(e.Errors))
+}
+
+// UpdateRoles sets the role of each user in roles, which maps user IDs to their new role, using
+// [UsersResource.UpdateRole]. A failure to update one user does not stop the others from being
+// updated; if any fail, an [*UpdateRolesError] reporting the error for each of them is returned.
+func (ur *UsersResource) UpdateRoles(ctx context.Context, roles map[string]UserRole, opts UpdateRolesOptions) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultUpdateRoleConcurrency
+	}
+
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for id, role := range roles {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := ur.UpdateRole(ctx, id, role); err != nil {
+				mu.Lock()
+				errs[id] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &UpdateRolesError{Errors: errs}
+	}
+	return nil
+}
+
+// UserRoleChange is a change to a user's role in a [UserRolePlan].
+type UserRoleChange struct {
+	UserID    string
+	LoginName string
+	From      UserRole
+	To        UserRole
+}
+
+// UserRolePlan is the set of role changes needed to bring the tailnet's users in line with a
+// desired role for each user. See [UsersResource.PlanRoles].
+type UserRolePlan struct {
+	// Changes are the users whose role differs from the desired one, sorted by login name.
+	Changes []UserRoleChange
+	// Missing are the login names with a desired role but no user in the tailnet, sorted.
+	Missing []string
+}
+
+// Empty reports whether no roles need to change.
+func (p *UserRolePlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// PlanRoles compares the roles of the tailnet's users against desired, a mapping of login names
+// to roles such as one derived from identity provider groups, and returns the changes needed to
+// reconcile them. Login names are matched case-insensitively, and users not in desired are left
+// untouched. Use [UsersResource.ApplyRolePlan] to make the changes.
+func (ur *UsersResource) PlanRoles(ctx context.Context, desired map[string]UserRole) (*UserRolePlan, error) {
+	for loginName, role := range desired {
+		if !role.IsValid() {
+			return nil, fmt.Errorf("unknown role %q for user %s", role, loginName)
+		}
+	}
+	users, err := ur.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return PlanUserRoles(users, desired), nil
+}
+
+// PlanUserRoles is like [UsersResource.PlanRoles], but compares an already fetched list of users.
+func PlanUserRoles(users []User, desired map[string]UserRole) *UserRolePlan {
+	wanted := make(map[string]UserRole, len(desired))
+	for loginName, role := range desired {
+		wanted[strings.ToLower(loginName)] = role
+	}
+
+	plan := &UserRolePlan{}
+	found := make(map[string]bool, len(users))
+	for _, u := range users {
+		loginName := strings.ToLower(u.LoginName)
+		role, ok := wanted[loginName]
+		if !ok {
+			continue
+		}
+		found[loginName] = true
+		if u.Role != role {
+			plan.Changes = append(plan.Changes, UserRoleChange{UserID: u.ID, LoginName: u.LoginName, From: u.Role, To: role})
+		}
+	}
+	for loginName := range desired {
+		if !found[strings.ToLower(loginName)] {
+			plan.Missing = append(plan.Missing, loginName)
+		}
+	}
+
+	slices.SortFunc(plan.Changes, func(a, b UserRoleChange) int {
+		return strings.Compare(a.LoginName, b.LoginName)
+	})
+	slices.Sort(plan.Missing)
+	return plan
+}
+
+// ApplyRolePlan makes the changes in plan with [UsersResource.UpdateRoles].
+func (ur *UsersResource) ApplyRolePlan(ctx context.Context, plan *UserRolePlan, opts UpdateRolesOptions) error {
+	roles := make(map[string]UserRole, len(plan.Changes))
+	for _, c := range plan.Changes {
+		roles[c.UserID] = c.To
+	}
+	return ur.UpdateRoles(ctx, roles, opts)
+}
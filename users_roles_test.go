@@ -0,0 +1,153 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Users_UpdateRoles(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight atomic.Int32
+	var mu sync.Mutex
+	updated := make(map[string]UserRole)
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/users/"), "/role")
+		assert.True(t, ok)
+		if id == "owner" {
+			w.WriteHeader(http.StatusBadRequest)
+			assert.NoError(t, json.NewEncoder(w).Encode(APIError{Message: "cannot demote the last owner"}))
+			return
+		}
+		var req struct {
+			Role UserRole `json:"role"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		updated[id] = req.Role
+		mu.Unlock()
+	}))
+
+	roles := map[string]UserRole{
+		"1":     UserRoleAdmin,
+		"2":     UserRoleMember,
+		"3":     UserRoleAuditor,
+		"4":     UserRoleITAdmin,
+		"5":     UserRoleNetworkAdmin,
+		"owner": UserRoleMember,
+	}
+	err := client.Users().UpdateRoles(context.Background(), roles, UpdateRolesOptions{Concurrency: 2})
+
+	var rolesErr *UpdateRolesError
+	if assert.ErrorAs(t, err, &rolesErr) {
+		assert.Len(t, rolesErr.Errors, 1)
+		var apiErr APIError
+		assert.True(t, errors.As(rolesErr.Errors["owner"], &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+	}
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Contains(t, err.Error(), "owner: cannot demote the last owner (400)")
+
+	delete(roles, "owner")
+	assert.Equal(t, roles, updated)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+
+	updated = make(map[string]UserRole)
+	assert.NoError(t, client.Users().UpdateRoles(context.Background(), roles, UpdateRolesOptions{}))
+	assert.Equal(t, roles, updated)
+}
+
+func TestPlanUserRoles(t *testing.T) {
+	t.Parallel()
+
+	users := []User{
+		{ID: "1", LoginName: "owner@example.com", Role: UserRoleOwner},
+		{ID: "2", LoginName: "Jane@example.com", Role: UserRoleMember},
+		{ID: "3", LoginName: "john@example.com", Role: UserRoleAdmin},
+		{ID: "4", LoginName: "audit@example.com", Role: UserRoleAuditor},
+	}
+	plan := PlanUserRoles(users, map[string]UserRole{
+		"jane@example.com":  UserRoleAdmin,
+		"john@example.com":  UserRoleMember,
+		"audit@example.com": UserRoleAuditor,
+		"new@example.com":   UserRoleMember,
+	})
+	assert.Equal(t, &UserRolePlan{
+		Changes: []UserRoleChange{
+			{UserID: "2", LoginName: "Jane@example.com", From: UserRoleMember, To: UserRoleAdmin},
+			{UserID: "3", LoginName: "john@example.com", From: UserRoleAdmin, To: UserRoleMember},
+		},
+		Missing: []string{"new@example.com"},
+	}, plan)
+	assert.False(t, plan.Empty())
+
+	assert.True(t, PlanUserRoles(users, map[string]UserRole{"audit@example.com": UserRoleAuditor}).Empty())
+}
+
+func TestClient_Users_PlanAndApplyRoles(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	users := []User{
+		{ID: "1", LoginName: "jane@example.com", Role: UserRoleMember},
+		{ID: "2", LoginName: "john@example.com", Role: UserRoleAdmin},
+	}
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/api/v2/tailnet/example.com/users", r.URL.Path)
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]User{"users": users}))
+			return
+		}
+		id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v2/users/"), "/role")
+		assert.True(t, ok)
+		var req struct {
+			Role UserRole `json:"role"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		for i := range users {
+			if users[i].ID == id {
+				users[i].Role = req.Role
+			}
+		}
+	}))
+	ctx := context.Background()
+	desired := map[string]UserRole{"jane@example.com": UserRoleAdmin, "john@example.com": UserRoleAdmin}
+
+	_, err := client.Users().PlanRoles(ctx, map[string]UserRole{"jane@example.com": "superuser"})
+	assert.EqualError(t, err, `unknown role "superuser" for user jane@example.com`)
+
+	plan, err := client.Users().PlanRoles(ctx, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []UserRoleChange{{UserID: "1", LoginName: "jane@example.com", From: UserRoleMember, To: UserRoleAdmin}}, plan.Changes)
+
+	assert.NoError(t, client.Users().ApplyRolePlan(ctx, plan, UpdateRolesOptions{}))
+	plan, err = client.Users().PlanRoles(ctx, desired)
+	assert.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
@@ -5,6 +5,7 @@ package tailscale
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"testing"
@@ -64,6 +65,45 @@ func TestClient_Users_List(t *testing.T) {
 	assert.Equal(t, expectedUsers["users"], actualUsers)
 }
 
+func TestClient_Users_ListFiltered(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string][]User{
+		"users": {
+			{ID: "1", Status: UserStatusActive, CurrentlyConnected: true},
+			{ID: "2", Status: UserStatusNeedsApproval},
+			{ID: "3", Status: UserStatusSuspended},
+			{ID: "4", Status: UserStatusNeedsApproval, CurrentlyConnected: true},
+		},
+	}
+
+	ids := func(users []User) []string {
+		var ids []string
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		return ids
+	}
+
+	users, err := client.Users().List(context.Background(), nil, nil, WithUserStatus(UserStatusNeedsApproval, UserStatusSuspended))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "4"}, ids(users))
+	assert.Empty(t, server.Query)
+
+	users, err = client.Users().List(context.Background(), nil, nil, WithUserStatus(UserStatusNeedsApproval), WithUserConnected(false))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids(users))
+
+	var iterated []string
+	for u, err := range client.Users().Iter(context.Background(), nil, nil, WithUserConnected(true)) {
+		assert.NoError(t, err)
+		iterated = append(iterated, u.ID)
+	}
+	assert.Equal(t, []string{"1", "4"}, iterated)
+}
+
 func TestClient_Users_Get(t *testing.T) {
 	t.Parallel()
 
@@ -92,3 +132,126 @@ func TestClient_Users_Get(t *testing.T) {
 	assert.Equal(t, "/api/v2/users/12345", server.Path)
 	assert.Equal(t, expectedUser, actualUser)
 }
+
+func TestClient_Users_Approve(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Users().Approve(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/users/12345/approve", server.Path)
+}
+
+func TestClient_Users_Suspend(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Users().Suspend(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/users/12345/suspend", server.Path)
+}
+
+func TestClient_Users_Restore(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Users().Restore(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/users/12345/restore", server.Path)
+}
+
+func TestClient_Users_Delete(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Users().Delete(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/users/12345/delete", server.Path)
+}
+
+func TestClient_Users_DeleteBlocked(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusConflict
+	server.ResponseBody = APIError{Message: "cannot delete the last owner of a tailnet"}
+
+	err := client.Users().Delete(context.Background(), "12345")
+	var blocked *UserDeletionBlockedError
+	if assert.True(t, errors.As(err, &blocked)) {
+		assert.Equal(t, "12345", blocked.UserID)
+		assert.Equal(t, "cannot delete the last owner of a tailnet", blocked.Reason)
+	}
+	var apiErr APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.Status)
+
+	server.ResponseCode = http.StatusNotFound
+	server.ResponseBody = APIError{Message: "user not found"}
+	err = client.Users().Delete(context.Background(), "12345")
+	assert.False(t, errors.As(err, &blocked))
+	assert.True(t, IsNotFound(err))
+
+	server.ResponseCode = http.StatusBadRequest
+	server.ResponseBody = APIError{Message: "invalid user ID"}
+	err = client.Users().Delete(context.Background(), "12345")
+	assert.False(t, errors.As(err, &blocked))
+	assert.Equal(t, APIError{Message: "invalid user ID", Status: http.StatusBadRequest}, err)
+}
+
+func TestClient_Users_UpdateRole(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+
+	err := client.Users().UpdateRole(context.Background(), "12345", UserRoleAuditor)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, server.Method)
+	assert.Equal(t, "/api/v2/users/12345/role", server.Path)
+	assert.JSONEq(t, `{"role":"auditor"}`, server.Body.String())
+}
+
+func TestClient_Users_GetByLoginName(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	server.ResponseBody = map[string][]User{
+		"users": {
+			{ID: "1", LoginName: "jane@example.com", Type: UserTypeMember},
+			{ID: "2", LoginName: "jane@example.com.au", Type: UserTypeMember},
+			{ID: "3", LoginName: "john@example.com", Type: UserTypeMember},
+			{ID: "4", LoginName: "John@example.com", Type: UserTypeShared},
+		},
+	}
+	ctx := context.Background()
+
+	user, err := client.Users().GetByLoginName(ctx, "Jane@Example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+	assert.Equal(t, http.MethodGet, server.Method)
+	assert.Equal(t, "/api/v2/tailnet/example.com/users", server.Path)
+
+	_, err = client.Users().GetByLoginName(ctx, "jane")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	_, err = client.Users().GetByLoginName(ctx, "john@example.com")
+	var ambiguous *AmbiguousUserError
+	if assert.ErrorAs(t, err, &ambiguous) {
+		assert.Equal(t, "john@example.com", ambiguous.LoginName)
+		assert.Len(t, ambiguous.Users, 2)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// UserEventType is the kind of change reported by a [UserWatcher].
+type UserEventType string
+
+const (
+	// UserEventAdded is reported for users that joined the tailnet.
+	UserEventAdded UserEventType = "added"
+	// UserEventRemoved is reported for users that were deleted from the tailnet.
+	UserEventRemoved UserEventType = "removed"
+	// UserEventStatusChanged is reported for users whose [UserStatus] changed, such as to
+	// [UserStatusNeedsApproval] or [UserStatusSuspended].
+	UserEventStatusChanged UserEventType = "statusChanged"
+	// UserEventRoleChanged is reported for users whose [UserRole] changed.
+	UserEventRoleChanged UserEventType = "roleChanged"
+)
+
+// UserEvent describes a change to a user found by a [UserWatcher].
+type UserEvent struct {
+	Type UserEventType
+	// Old is the user before the change, or nil if the user was added.
+	Old *User
+	// New is the user after the change, or nil if the user was removed.
+	New *User
+}
+
+// UserWatcher polls a tailnet's users and reports users that were added or removed, or whose
+// status or role changed. Use [UsersResource.NewWatcher] to create one.
+type UserWatcher struct {
+	users *UsersResource
+
+	mu       sync.Mutex // protects baseline
+	baseline map[string]User
+}
+
+// NewWatcher returns a [UserWatcher] for the tailnet's users. The users seen by the first check
+// become the baseline that later checks are compared against.
+func (ur *UsersResource) NewWatcher() *UserWatcher {
+	return &UserWatcher{users: ur}
+}
+
+// Check fetches the current users and compares them to the baseline, which is then updated to
+// the current users. Events are ordered by user ID; a user whose status and role both changed
+// has an event for each. If there is no baseline yet, Check establishes it and reports no events.
+func (w *UserWatcher) Check(ctx context.Context) ([]UserEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	users, err := w.users.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]User, len(users))
+	for _, u := range users {
+		current[u.ID] = u
+	}
+	old := w.baseline
+	w.baseline = current
+	if old == nil {
+		return nil, nil
+	}
+	return diffUsers(old, current), nil
+}
+
+// Watch calls [UserWatcher.Check] every interval until ctx is done, calling handle for every
+// event found. It returns when ctx is done, or with the first error from Check or handle.
+// The interval must be positive.
+func (w *UserWatcher) Watch(ctx context.Context, interval time.Duration, handle func(ctx context.Context, event UserEvent) error) error {
+	return poll(ctx, interval, func(ctx context.Context) error {
+		events, err := w.Check(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := handle(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func diffUsers(old, current map[string]User) []UserEvent {
+	var events []UserEvent
+	for id, n := range current {
+		o, ok := old[id]
+		switch {
+		case !ok:
+			events = append(events, UserEvent{Type: UserEventAdded, New: &n})
+		default:
+			if o.Status != n.Status {
+				events = append(events, UserEvent{Type: UserEventStatusChanged, Old: &o, New: &n})
+			}
+			if o.Role != n.Role {
+				events = append(events, UserEvent{Type: UserEventRoleChanged, Old: &o, New: &n})
+			}
+		}
+	}
+	for id, o := range old {
+		if _, ok := current[id]; !ok {
+			events = append(events, UserEvent{Type: UserEventRemoved, Old: &o})
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b UserEvent) int {
+		return cmp.Compare(a.userID(), b.userID())
+	})
+	return events
+}
+
+func (e *UserEvent) userID() string {
+	if e.New != nil {
+		return e.New.ID
+	}
+	return e.Old.ID
+}
@@ -0,0 +1,102 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserWatcher(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	current := []User{
+		{ID: "1", Role: UserRoleOwner, Status: UserStatusActive},
+		{ID: "2", Role: UserRoleMember, Status: UserStatusActive},
+		{ID: "3", Role: UserRoleMember, Status: UserStatusIdle},
+	}
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tailnet/example.com/users", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string][]User{"users": current}))
+	}))
+	ctx := context.Background()
+	watcher := client.Users().NewWatcher()
+
+	events, err := watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	mu.Lock()
+	current = []User{
+		{ID: "1", Role: UserRoleOwner, Status: UserStatusActive},
+		{ID: "2", Role: UserRoleAdmin, Status: UserStatusSuspended},
+		{ID: "4", Role: UserRoleMember, Status: UserStatusNeedsApproval},
+	}
+	mu.Unlock()
+
+	events, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []UserEvent{
+		{
+			Type: UserEventStatusChanged,
+			Old:  &User{ID: "2", Role: UserRoleMember, Status: UserStatusActive},
+			New:  &User{ID: "2", Role: UserRoleAdmin, Status: UserStatusSuspended},
+		},
+		{
+			Type: UserEventRoleChanged,
+			Old:  &User{ID: "2", Role: UserRoleMember, Status: UserStatusActive},
+			New:  &User{ID: "2", Role: UserRoleAdmin, Status: UserStatusSuspended},
+		},
+		{
+			Type: UserEventRemoved,
+			Old:  &User{ID: "3", Role: UserRoleMember, Status: UserStatusIdle},
+		},
+		{
+			Type: UserEventAdded,
+			New:  &User{ID: "4", Role: UserRoleMember, Status: UserStatusNeedsApproval},
+		},
+	}, events)
+
+	events, err = watcher.Check(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestUserWatcher_Watch(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	role := UserRoleMember
+	client := NewTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		users := []User{{ID: "1", Role: role, Status: UserStatusActive}}
+		// Elevate the user once the baseline has been fetched.
+		role = UserRoleAdmin
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string][]User{"users": users}))
+	}))
+
+	errStop := errors.New("stop")
+	var got []UserEvent
+	err := client.Users().NewWatcher().Watch(context.Background(), time.Millisecond, func(ctx context.Context, event UserEvent) error {
+		got = append(got, event)
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, UserEventRoleChanged, got[0].Type)
+		assert.Equal(t, UserRoleMember, got[0].Old.Role)
+		assert.Equal(t, UserRoleAdmin, got[0].New.Role)
+	}
+}
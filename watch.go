@@ -0,0 +1,32 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// poll calls check immediately and then every interval until ctx is done. It returns when ctx
+// is done, or with the first error from check. The interval must be positive.
+func poll(ctx context.Context, interval time.Duration, check func(ctx context.Context) error) error {
+	if interval <= 0 {
+		return errors.New("watch interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := check(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
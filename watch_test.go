@@ -0,0 +1,48 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err := poll(ctx, interval, func(context.Context) error {
+			t.Error("unexpected check")
+			return nil
+		})
+		assert.EqualError(t, err, "watch interval must be positive")
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := poll(ctx, time.Millisecond, func(context.Context) error {
+		calls++
+		if calls == 3 {
+			return errStop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 3, calls)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	err = poll(ctx, time.Hour, func(context.Context) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
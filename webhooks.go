@@ -5,7 +5,10 @@ package tailscale
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"time"
 )
 
@@ -20,6 +23,10 @@ const (
 	WebhookMattermostProviderType WebhookProviderType = "mattermost"
 	WebhookGoogleChatProviderType WebhookProviderType = "googlechat"
 	WebhookDiscordProviderType    WebhookProviderType = "discord"
+
+	// WebhookGenericProviderType sends the events as JSON to any endpoint.
+	// It is the same as WebhookEmptyProviderType.
+	WebhookGenericProviderType = WebhookEmptyProviderType
 )
 
 const (
@@ -33,6 +40,8 @@ const (
 	WebhookNodeKeyExpiringInOneDay   WebhookSubscriptionType = "nodeKeyExpiringInOneDay"
 	WebhookNodeKeyExpired            WebhookSubscriptionType = "nodeKeyExpired"
 	WebhookNodeDeleted               WebhookSubscriptionType = "nodeDeleted"
+	WebhookNodeNeedsSignature        WebhookSubscriptionType = "nodeNeedsSignature"
+	WebhookNodeSigned                WebhookSubscriptionType = "nodeSigned"
 	WebhookPolicyUpdate              WebhookSubscriptionType = "policyUpdate"
 	WebhookUserCreated               WebhookSubscriptionType = "userCreated"
 	WebhookUserNeedsApproval         WebhookSubscriptionType = "userNeedsApproval"
@@ -55,9 +64,83 @@ const (
 // WebhookProviderType defines the provider type for a Webhook destination.
 type WebhookProviderType string
 
+// Validate returns an error if t is not a known provider type.
+func (t WebhookProviderType) Validate() error {
+	switch t {
+	case WebhookGenericProviderType, WebhookSlackProviderType, WebhookMattermostProviderType,
+		WebhookGoogleChatProviderType, WebhookDiscordProviderType:
+		return nil
+	}
+	return fmt.Errorf("unknown webhook provider type %q", t)
+}
+
 // WebhookSubscriptionType defines events in tailscale to subscribe a Webhook to.
 type WebhookSubscriptionType string
 
+// webhookCategories lists the event types of each webhook subscription category.
+var webhookCategories = map[WebhookSubscriptionType][]WebhookSubscriptionType{
+	WebhookCategoryTailnetManagement: {
+		WebhookNodeCreated,
+		WebhookNodeNeedsApproval,
+		WebhookNodeApproved,
+		WebhookNodeKeyExpiringInOneDay,
+		WebhookNodeKeyExpired,
+		WebhookNodeDeleted,
+		WebhookNodeNeedsSignature,
+		WebhookNodeSigned,
+		WebhookPolicyUpdate,
+		WebhookUserCreated,
+		WebhookUserNeedsApproval,
+		WebhookUserSuspended,
+		WebhookUserRestored,
+		WebhookUserDeleted,
+		WebhookUserApproved,
+		WebhookUserRoleUpdated,
+	},
+	WebhookCategoryDeviceMisconfigurations: {
+		WebhookSubnetIPForwardingNotEnabled,
+		WebhookExitNodeIPForwardingNotEnabled,
+	},
+}
+
+// AllWebhookSubscriptions returns every webhook event type known to this client, excluding the
+// categories. Subscribe to the categories instead to also receive events added in the future.
+func AllWebhookSubscriptions() []WebhookSubscriptionType {
+	var all []WebhookSubscriptionType
+	for _, category := range []WebhookSubscriptionType{WebhookCategoryTailnetManagement, WebhookCategoryDeviceMisconfigurations} {
+		all = append(all, webhookCategories[category]...)
+	}
+	return all
+}
+
+// Category returns the category that the event type belongs to, or "" if it is unknown or is
+// itself a category.
+func (t WebhookSubscriptionType) Category() WebhookSubscriptionType {
+	for category, events := range webhookCategories {
+		if slices.Contains(events, t) {
+			return category
+		}
+	}
+	return ""
+}
+
+// Validate returns an error if t is not a known event type or category.
+func (t WebhookSubscriptionType) Validate() error {
+	if _, ok := webhookCategories[t]; ok || t.Category() != "" {
+		return nil
+	}
+	return fmt.Errorf("unknown webhook subscription type %q", t)
+}
+
+// validateWebhookSubscriptions returns an error for each of subscriptions that is invalid.
+func validateWebhookSubscriptions(subscriptions []WebhookSubscriptionType) error {
+	var errs []error
+	for _, sub := range subscriptions {
+		errs = append(errs, sub.Validate())
+	}
+	return errors.Join(errs...)
+}
+
 // Webhook type defines a webhook endpoint within a tailnet.
 type Webhook struct {
 	EndpointID       string                    `json:"endpointId"`
@@ -78,9 +161,24 @@ type CreateWebhookRequest struct {
 	Subscriptions []WebhookSubscriptionType `json:"subscriptions"`
 }
 
+// Validate returns an error if the request's endpoint URL is not an http or https URL, or its
+// provider type or any of its subscription types are unknown.
+func (r *CreateWebhookRequest) Validate() error {
+	var errs []error
+	if !isHTTPURL(r.EndpointURL) {
+		errs = append(errs, fmt.Errorf("webhook endpoint URL %q is not an http or https URL", r.EndpointURL))
+	}
+	errs = append(errs, r.ProviderType.Validate(), validateWebhookSubscriptions(r.Subscriptions))
+	return errors.Join(errs...)
+}
+
 // Create creates a new [Webhook] with the specifications provided in the [CreateWebhookRequest].
-// Returns the created [Webhook] if successful.
+// Returns the created [Webhook] if successful. Invalid requests are rejected without making a
+// request, see [CreateWebhookRequest.Validate].
 func (wr *WebhooksResource) Create(ctx context.Context, request CreateWebhookRequest) (*Webhook, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
 	req, err := wr.buildRequest(ctx, http.MethodPost, wr.buildTailnetURL("webhooks"), requestBody(request))
 	if err != nil {
 		return nil, err
@@ -114,11 +212,37 @@ func (wr *WebhooksResource) Get(ctx context.Context, endpointID string) (*Webhoo
 	return body[Webhook](wr, req)
 }
 
+// UpdateWebhookRequest is a request to update a webhook. Nil values indicate that the existing
+// setting should be left unchanged.
+type UpdateWebhookRequest struct {
+	EndpointURL   *string                   `json:"endpointUrl,omitempty"`
+	ProviderType  *WebhookProviderType      `json:"providerType,omitempty"`
+	Subscriptions []WebhookSubscriptionType `json:"subscriptions,omitzero"`
+}
+
 // Update updates an existing webhook's subscriptions. Returns the updated [Webhook] on success.
+// Unknown subscription types are rejected without making a request.
 func (wr *WebhooksResource) Update(ctx context.Context, endpointID string, subscriptions []WebhookSubscriptionType) (*Webhook, error) {
-	req, err := wr.buildRequest(ctx, http.MethodPatch, wr.buildURL("webhooks", endpointID), requestBody(map[string][]WebhookSubscriptionType{
-		"subscriptions": subscriptions,
-	}))
+	return wr.Modify(ctx, endpointID, UpdateWebhookRequest{Subscriptions: subscriptions})
+}
+
+// Modify updates an existing webhook's endpoint URL, provider type and subscriptions, as set in
+// request. Changing the endpoint this way keeps the webhook's secret, unlike deleting and
+// recreating it. Returns the updated [Webhook] on success. Unknown provider and subscription types
+// are rejected without making a request.
+func (wr *WebhooksResource) Modify(ctx context.Context, endpointID string, request UpdateWebhookRequest) (*Webhook, error) {
+	var errs []error
+	if request.EndpointURL != nil && !isHTTPURL(*request.EndpointURL) {
+		errs = append(errs, fmt.Errorf("webhook endpoint URL %q is not an http or https URL", *request.EndpointURL))
+	}
+	if request.ProviderType != nil {
+		errs = append(errs, request.ProviderType.Validate())
+	}
+	errs = append(errs, validateWebhookSubscriptions(request.Subscriptions))
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	req, err := wr.buildRequest(ctx, http.MethodPatch, wr.buildURL("webhooks", endpointID), requestBody(request))
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +272,36 @@ func (wr *WebhooksResource) Test(ctx context.Context, endpointID string) error {
 	return wr.do(req, nil)
 }
 
+// defaultWebhookCheckTimeout is how long [WebhooksResource.CheckDelivery] waits by default.
+const defaultWebhookCheckTimeout = time.Minute
+
+// CheckDelivery checks the whole notification path of a webhook end-to-end: it sends a test event
+// with [WebhooksResource.Test] and waits for receiver, which must be serving the webhook's endpoint,
+// to receive and verify it. It returns the delivered test event, or an error if none arrives within
+// timeout, which defaults to 1 minute if not positive.
+func (wr *WebhooksResource) CheckDelivery(ctx context.Context, endpointID string, receiver *WebhookReceiver, timeout time.Duration) (*WebhookEvent, error) {
+	if timeout <= 0 {
+		timeout = defaultWebhookCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delivered, stop := receiver.waitForTest()
+	defer stop()
+	if err := wr.Test(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	select {
+	case event := <-delivered:
+		return &event, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("test event for webhook %s was not delivered within %s", endpointID, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
 // RotateSecret rotates the secret associated with a webhook.
 // A new secret will be generated and set on the returned [Webhook].
 func (wr *WebhooksResource) RotateSecret(ctx context.Context, endpointID string) (*Webhook, error) {
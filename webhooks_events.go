@@ -0,0 +1,133 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WebhookTestEvent is the type of the event sent by [WebhooksResource.Test].
+const WebhookTestEvent WebhookSubscriptionType = "test"
+
+// WebhookEvent is a single event within a webhook delivery. Deliveries may batch several events.
+type WebhookEvent struct {
+	Timestamp time.Time               `json:"timestamp"`
+	Version   int                     `json:"version"`
+	Type      WebhookSubscriptionType `json:"type"`
+	Tailnet   string                  `json:"tailnet"`
+	Message   string                  `json:"message"`
+	// Data holds the event type specific details of the event. Use NodeData, UserData or
+	// PolicyData to decode it according to Type.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// WebhookNodeEventData is the data of events about a node, such as [WebhookNodeCreated],
+// [WebhookNodeKeyExpired] or [WebhookSubnetIPForwardingNotEnabled].
+type WebhookNodeEventData struct {
+	NodeID     string `json:"nodeID"`
+	DeviceName string `json:"deviceName"`
+	ManagedBy  string `json:"managedBy"`       // the user or tags that own the node
+	Actor      string `json:"actor,omitempty"` // who caused the event, if anyone
+	URL        string `json:"url"`             // the node's page in the admin console
+	// Expiration is when the node's key expires, for key expiry events.
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// WebhookUserEventData is the data of events about a user, such as [WebhookUserCreated] or
+// [WebhookUserRoleUpdated].
+type WebhookUserEventData struct {
+	User  string `json:"user"`
+	Actor string `json:"actor,omitempty"` // who caused the event, if anyone
+	URL   string `json:"url"`             // the user's page in the admin console
+	// OldRoles and NewRoles are set for [WebhookUserRoleUpdated] events.
+	OldRoles []string `json:"oldRoles,omitempty"`
+	NewRoles []string `json:"newRoles,omitempty"`
+}
+
+// WebhookPolicyUpdateEventData is the data of [WebhookPolicyUpdate] events.
+type WebhookPolicyUpdateEventData struct {
+	Actor     string `json:"actor"`
+	URL       string `json:"url"`
+	OldPolicy string `json:"oldPolicy"`
+	NewPolicy string `json:"newPolicy"`
+}
+
+// ParseWebhookEvents parses the body of a webhook delivery, which is a JSON array of events.
+// A single JSON event object is also accepted. The body is not verified; see
+// [VerifyWebhookSignature] or use a [WebhookReceiver].
+func ParseWebhookEvents(payload []byte) ([]WebhookEvent, error) {
+	payload = bytes.TrimSpace(payload)
+	if len(payload) > 0 && payload[0] == '{' {
+		var event WebhookEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook event: %w", err)
+		}
+		return []WebhookEvent{event}, nil
+	}
+
+	var events []WebhookEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook events: %w", err)
+	}
+	return events, nil
+}
+
+// IsNodeEvent reports whether the event is about a node, with [WebhookNodeEventData].
+func (e *WebhookEvent) IsNodeEvent() bool {
+	switch e.Type {
+	case WebhookNodeCreated, WebhookNodeNeedsApproval, WebhookNodeApproved, WebhookNodeKeyExpiringInOneDay,
+		WebhookNodeKeyExpired, WebhookNodeDeleted, WebhookNodeNeedsSignature, WebhookNodeSigned,
+		WebhookSubnetIPForwardingNotEnabled, WebhookExitNodeIPForwardingNotEnabled:
+		return true
+	}
+	return false
+}
+
+// IsUserEvent reports whether the event is about a user, with [WebhookUserEventData].
+func (e *WebhookEvent) IsUserEvent() bool {
+	switch e.Type {
+	case WebhookUserCreated, WebhookUserNeedsApproval, WebhookUserSuspended, WebhookUserRestored,
+		WebhookUserDeleted, WebhookUserApproved, WebhookUserRoleUpdated:
+		return true
+	}
+	return false
+}
+
+// NodeData decodes the data of a node event. See [WebhookEvent.IsNodeEvent].
+func (e *WebhookEvent) NodeData() (*WebhookNodeEventData, error) {
+	if !e.IsNodeEvent() {
+		return nil, fmt.Errorf("%s event is not a node event", e.Type)
+	}
+	return decodeWebhookEventData[WebhookNodeEventData](e)
+}
+
+// UserData decodes the data of a user event. See [WebhookEvent.IsUserEvent].
+func (e *WebhookEvent) UserData() (*WebhookUserEventData, error) {
+	if !e.IsUserEvent() {
+		return nil, fmt.Errorf("%s event is not a user event", e.Type)
+	}
+	return decodeWebhookEventData[WebhookUserEventData](e)
+}
+
+// PolicyData decodes the data of a [WebhookPolicyUpdate] event.
+func (e *WebhookEvent) PolicyData() (*WebhookPolicyUpdateEventData, error) {
+	if e.Type != WebhookPolicyUpdate {
+		return nil, fmt.Errorf("%s event is not a %s event", e.Type, WebhookPolicyUpdate)
+	}
+	return decodeWebhookEventData[WebhookPolicyUpdateEventData](e)
+}
+
+func decodeWebhookEventData[T any](e *WebhookEvent) (*T, error) {
+	var data T
+	if len(e.Data) == 0 {
+		return &data, nil
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse %s event data: %w", e.Type, err)
+	}
+	return &data, nil
+}
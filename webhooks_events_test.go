@@ -0,0 +1,82 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookEvents(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`[
+		{
+			"timestamp": "2024-05-01T12:00:00Z",
+			"version": 1,
+			"type": "nodeKeyExpiringInOneDay",
+			"tailnet": "example.com",
+			"message": "Node laptop's key is expiring in one day",
+			"data": {
+				"nodeID": "n123",
+				"deviceName": "laptop.example.ts.net",
+				"managedBy": "amelie@example.com",
+				"url": "https://login.tailscale.com/admin/machines/100.64.0.1",
+				"expiration": "2024-05-02T12:00:00Z"
+			}
+		},
+		{
+			"timestamp": "2024-05-01T12:01:00Z",
+			"version": 1,
+			"type": "userRoleUpdated",
+			"tailnet": "example.com",
+			"message": "User role updated",
+			"data": {"user": "bob@example.com", "actor": "amelie@example.com", "oldRoles": ["member"], "newRoles": ["admin"]}
+		},
+		{
+			"timestamp": "2024-05-01T12:02:00Z",
+			"version": 1,
+			"type": "policyUpdate",
+			"tailnet": "example.com",
+			"message": "Tailnet policy file updated",
+			"data": {"actor": "amelie@example.com", "oldPolicy": "{}", "newPolicy": "{\"acls\": []}"}
+		}
+	]`)
+
+	events, err := ParseWebhookEvents(payload)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, WebhookNodeKeyExpiringInOneDay, events[0].Type)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), events[0].Timestamp)
+
+	node, err := events[0].NodeData()
+	assert.NoError(t, err)
+	expiration := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, &WebhookNodeEventData{
+		NodeID:     "n123",
+		DeviceName: "laptop.example.ts.net",
+		ManagedBy:  "amelie@example.com",
+		URL:        "https://login.tailscale.com/admin/machines/100.64.0.1",
+		Expiration: &expiration,
+	}, node)
+	_, err = events[0].UserData()
+	assert.EqualError(t, err, "nodeKeyExpiringInOneDay event is not a user event")
+
+	user, err := events[1].UserData()
+	assert.NoError(t, err)
+	assert.Equal(t, &WebhookUserEventData{User: "bob@example.com", Actor: "amelie@example.com", OldRoles: []string{"member"}, NewRoles: []string{"admin"}}, user)
+
+	policy, err := events[2].PolicyData()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"acls": []}`, policy.NewPolicy)
+
+	events, err = ParseWebhookEvents([]byte(`{"type": "test", "message": "This is a test event"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []WebhookEvent{{Type: WebhookTestEvent, Message: "This is a test event"}}, events)
+
+	_, err = ParseWebhookEvents([]byte(`not json`))
+	assert.ErrorContains(t, err, "failed to parse webhook events")
+}
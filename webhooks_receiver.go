@@ -0,0 +1,310 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WebhookSignatureHeader is the header of a webhook delivery that holds its signature.
+const WebhookSignatureHeader = "Tailscale-Webhook-Signature"
+
+const (
+	// defaultWebhookTolerance is how old a webhook delivery may be before it is rejected.
+	defaultWebhookTolerance = 5 * time.Minute
+	// maxWebhookBodySize limits the size of webhook deliveries accepted by a [WebhookReceiver].
+	maxWebhookBodySize = 1 << 20
+)
+
+// ErrInvalidWebhookSignature is returned when a webhook delivery's signature is missing,
+// malformed, too old or doesn't match its body.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhookSignature verifies that body was signed with one of the webhook's secrets, where
+// signature is the value of the [WebhookSignatureHeader] of the delivery, in the form "t=<unix
+// time>,v1=<hex HMAC-SHA256>". Deliveries signed more than 5 minutes ago are rejected, to prevent
+// replays. Errors wrap [ErrInvalidWebhookSignature].
+//
+// Pass both the old and new secrets while a secret is being rotated with
+// [WebhooksResource.RotateSecret], so that deliveries signed with either are accepted.
+func VerifyWebhookSignature(signature string, body []byte, secrets ...string) error {
+	return verifyWebhookSignature(signature, body, secrets, time.Now(), defaultWebhookTolerance)
+}
+
+func verifyWebhookSignature(signature string, body []byte, secrets []string, now time.Time, tolerance time.Duration) error {
+	if len(secrets) == 0 {
+		return fmt.Errorf("%w: no secrets to verify with", ErrInvalidWebhookSignature)
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or signature", ErrInvalidWebhookSignature)
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidWebhookSignature, timestamp)
+	}
+	if signed := time.Unix(unix, 0); now.Sub(signed) > tolerance || signed.Sub(now) > tolerance {
+		return fmt.Errorf("%w: signed at %s, outside the allowed %s", ErrInvalidWebhookSignature, signed.UTC().Format(time.RFC3339), tolerance)
+	}
+
+	for _, secret := range secrets {
+		want := signWebhook(timestamp, body, secret)
+		for _, s := range signatures {
+			got, err := hex.DecodeString(s)
+			if err == nil && hmac.Equal(got, want) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: signature does not match", ErrInvalidWebhookSignature)
+}
+
+// signWebhook returns the HMAC-SHA256 of a webhook delivery body signed at timestamp.
+func signWebhook(timestamp string, body []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// WebhookHandler handles a single webhook event received by a [WebhookReceiver].
+type WebhookHandler func(ctx context.Context, event WebhookEvent) error
+
+// WebhookReceiver is an [http.Handler] that receives webhook deliveries, verifies their signatures
+// and dispatches their events to the handlers registered for each event type. Events without a
+// handler are ignored. Register handlers before serving requests.
+//
+// Unsigned or incorrectly signed deliveries are rejected with 401 Unauthorized, malformed ones with
+// 400 Bad Request, and if a handler returns an error, the delivery is failed with 500 Internal Server
+// Error so that it is retried.
+type WebhookReceiver struct {
+	// Tolerance is how old a delivery's signature may be. Defaults to 5 minutes.
+	Tolerance time.Duration
+	// ErrorLog, if not nil, is called with errors returned by handlers.
+	ErrorLog func(event WebhookEvent, err error)
+
+	secrets  atomic.Pointer[[]string]
+	handlers map[WebhookSubscriptionType][]WebhookHandler
+
+	mu          sync.Mutex
+	testWaiters map[chan WebhookEvent]struct{} // see [WebhookReceiver.waitForTest]
+}
+
+// NewWebhookReceiver returns a [WebhookReceiver] that verifies deliveries with the webhook's
+// secret. See [WebhookReceiver.SetSecrets] for passing more than one.
+func NewWebhookReceiver(secrets ...string) *WebhookReceiver {
+	wr := &WebhookReceiver{
+		handlers: make(map[WebhookSubscriptionType][]WebhookHandler),
+	}
+	wr.SetSecrets(secrets...)
+	return wr
+}
+
+// SetSecrets replaces the secrets that deliveries are verified with. Deliveries signed with any of
+// them are accepted. To rotate a webhook's secret without rejecting deliveries, set both the old
+// and new secrets after calling [WebhooksResource.RotateSecret], then only the new secret once
+// deliveries are signed with it. It is safe to call while serving requests.
+func (wr *WebhookReceiver) SetSecrets(secrets ...string) {
+	secrets = slices.Clone(secrets)
+	wr.secrets.Store(&secrets)
+}
+
+// On registers handler for events of the given type. Several handlers may be registered for the
+// same type, and are called in the order they were registered.
+func (wr *WebhookReceiver) On(eventType WebhookSubscriptionType, handler WebhookHandler) {
+	wr.handlers[eventType] = append(wr.handlers[eventType], handler)
+}
+
+// OnNodeEvent registers handler for node events of the given type, with their decoded data.
+func (wr *WebhookReceiver) OnNodeEvent(eventType WebhookSubscriptionType, handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.On(eventType, func(ctx context.Context, event WebhookEvent) error {
+		data, err := event.NodeData()
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event, data)
+	})
+}
+
+// OnUserEvent registers handler for user events of the given type, with their decoded data.
+func (wr *WebhookReceiver) OnUserEvent(eventType WebhookSubscriptionType, handler func(ctx context.Context, event WebhookEvent, data *WebhookUserEventData) error) {
+	wr.On(eventType, func(ctx context.Context, event WebhookEvent) error {
+		data, err := event.UserData()
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event, data)
+	})
+}
+
+// OnNodeCreated registers handler for [WebhookNodeCreated] events.
+func (wr *WebhookReceiver) OnNodeCreated(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeCreated, handler)
+}
+
+// OnNodeNeedsApproval registers handler for [WebhookNodeNeedsApproval] events.
+func (wr *WebhookReceiver) OnNodeNeedsApproval(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeNeedsApproval, handler)
+}
+
+// OnNodeApproved registers handler for [WebhookNodeApproved] events.
+func (wr *WebhookReceiver) OnNodeApproved(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeApproved, handler)
+}
+
+// OnNodeKeyExpiringInOneDay registers handler for [WebhookNodeKeyExpiringInOneDay] events.
+func (wr *WebhookReceiver) OnNodeKeyExpiringInOneDay(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeKeyExpiringInOneDay, handler)
+}
+
+// OnNodeKeyExpired registers handler for [WebhookNodeKeyExpired] events.
+func (wr *WebhookReceiver) OnNodeKeyExpired(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeKeyExpired, handler)
+}
+
+// OnNodeDeleted registers handler for [WebhookNodeDeleted] events.
+func (wr *WebhookReceiver) OnNodeDeleted(handler func(ctx context.Context, event WebhookEvent, data *WebhookNodeEventData) error) {
+	wr.OnNodeEvent(WebhookNodeDeleted, handler)
+}
+
+// OnUserCreated registers handler for [WebhookUserCreated] events.
+func (wr *WebhookReceiver) OnUserCreated(handler func(ctx context.Context, event WebhookEvent, data *WebhookUserEventData) error) {
+	wr.OnUserEvent(WebhookUserCreated, handler)
+}
+
+// OnUserNeedsApproval registers handler for [WebhookUserNeedsApproval] events.
+func (wr *WebhookReceiver) OnUserNeedsApproval(handler func(ctx context.Context, event WebhookEvent, data *WebhookUserEventData) error) {
+	wr.OnUserEvent(WebhookUserNeedsApproval, handler)
+}
+
+// OnUserApproved registers handler for [WebhookUserApproved] events.
+func (wr *WebhookReceiver) OnUserApproved(handler func(ctx context.Context, event WebhookEvent, data *WebhookUserEventData) error) {
+	wr.OnUserEvent(WebhookUserApproved, handler)
+}
+
+// OnUserRoleUpdated registers handler for [WebhookUserRoleUpdated] events.
+func (wr *WebhookReceiver) OnUserRoleUpdated(handler func(ctx context.Context, event WebhookEvent, data *WebhookUserEventData) error) {
+	wr.OnUserEvent(WebhookUserRoleUpdated, handler)
+}
+
+// OnPolicyUpdate registers handler for [WebhookPolicyUpdate] events.
+func (wr *WebhookReceiver) OnPolicyUpdate(handler func(ctx context.Context, event WebhookEvent, data *WebhookPolicyUpdateEventData) error) {
+	wr.On(WebhookPolicyUpdate, func(ctx context.Context, event WebhookEvent) error {
+		data, err := event.PolicyData()
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event, data)
+	})
+}
+
+// OnTest registers handler for the test events sent by [WebhooksResource.Test].
+func (wr *WebhookReceiver) OnTest(handler WebhookHandler) {
+	wr.On(WebhookTestEvent, handler)
+}
+
+// ServeHTTP implements [http.Handler].
+func (wr *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	tolerance := wr.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultWebhookTolerance
+	}
+	if err := verifyWebhookSignature(r.Header.Get(WebhookSignatureHeader), body, *wr.secrets.Load(), time.Now(), tolerance); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	events, err := ParseWebhookEvents(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	failed := false
+	for _, event := range events {
+		if event.Type == WebhookTestEvent {
+			wr.notifyTest(event)
+		}
+		for _, handler := range wr.handlers[event.Type] {
+			if err := handler(r.Context(), event); err != nil {
+				failed = true
+				if wr.ErrorLog != nil {
+					wr.ErrorLog(event, err)
+				}
+			}
+		}
+	}
+	if failed {
+		http.Error(w, "failed to handle webhook events", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// waitForTest returns a channel that receives the next test event delivered to the receiver,
+// and a function to stop waiting that must be called once done.
+func (wr *WebhookReceiver) waitForTest() (<-chan WebhookEvent, func()) {
+	ch := make(chan WebhookEvent, 1)
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	if wr.testWaiters == nil {
+		wr.testWaiters = make(map[chan WebhookEvent]struct{})
+	}
+	wr.testWaiters[ch] = struct{}{}
+	return ch, func() {
+		wr.mu.Lock()
+		defer wr.mu.Unlock()
+		delete(wr.testWaiters, ch)
+	}
+}
+
+// notifyTest passes a delivered test event to everyone waiting for one.
+func (wr *WebhookReceiver) notifyTest(event WebhookEvent) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	for ch := range wr.testWaiters {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
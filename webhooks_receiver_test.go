@@ -0,0 +1,125 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`[{"type":"test"}]`)
+	now := time.Now()
+	assert.NoError(t, VerifyWebhookSignature(SignWebhookPayload(body, "secret", now), body, "secret"))
+
+	tests := map[string]string{
+		"missing":      "",
+		"no signature": fmt.Sprintf("t=%d", now.Unix()),
+		"bad time":     "t=soon,v1=00",
+		"wrong secret": SignWebhookPayload(body, "other", now),
+		"too old":      SignWebhookPayload(body, "secret", now.Add(-10*time.Minute)),
+		"future":       SignWebhookPayload(body, "secret", now.Add(10*time.Minute)),
+		"not hex":      fmt.Sprintf("t=%d,v1=zz", now.Unix()),
+	}
+	for name, signature := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyWebhookSignature(signature, body, "secret")
+			assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+		})
+	}
+
+	// The body is covered by the signature.
+	err := VerifyWebhookSignature(SignWebhookPayload(body, "secret", now), []byte(`[]`), "secret")
+	assert.ErrorIs(t, err, ErrInvalidWebhookSignature)
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	t.Parallel()
+
+	receiver := NewWebhookReceiver("secret")
+	var created []string
+	receiver.OnNodeCreated(func(_ context.Context, event WebhookEvent, data *WebhookNodeEventData) error {
+		created = append(created, data.NodeID)
+		return nil
+	})
+	receiver.OnUserApproved(func(context.Context, WebhookEvent, *WebhookUserEventData) error {
+		return errors.New("approval failed")
+	})
+	var logged []error
+	receiver.ErrorLog = func(_ WebhookEvent, err error) {
+		logged = append(logged, err)
+	}
+
+	deliver := func(method, body, signature string) int {
+		r := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		if signature != "" {
+			r.Header.Set(WebhookSignatureHeader, signature)
+		}
+		w := httptest.NewRecorder()
+		receiver.ServeHTTP(w, r)
+		return w.Code
+	}
+	sign := func(body string) string {
+		return SignWebhookPayload([]byte(body), "secret", time.Now())
+	}
+
+	body := `[{"type":"nodeCreated","data":{"nodeID":"n1"}},{"type":"nodeDeleted","data":{"nodeID":"n2"}},{"type":"nodeCreated","data":{"nodeID":"n3"}}]`
+	assert.Equal(t, http.StatusOK, deliver(http.MethodPost, body, sign(body)))
+	assert.Equal(t, []string{"n1", "n3"}, created)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, deliver(http.MethodGet, "", ""))
+	assert.Equal(t, http.StatusUnauthorized, deliver(http.MethodPost, body, ""))
+	assert.Equal(t, http.StatusUnauthorized, deliver(http.MethodPost, body, SignWebhookPayload([]byte(body), "wrong", time.Now())))
+	assert.Equal(t, http.StatusBadRequest, deliver(http.MethodPost, "not json", sign("not json")))
+	large := strings.Repeat(" ", maxWebhookBodySize+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, deliver(http.MethodPost, large, sign(large)))
+	assert.Len(t, created, 2)
+
+	body = `[{"type":"userApproved","data":{"user":"amelie@example.com"}}]`
+	assert.Equal(t, http.StatusInternalServerError, deliver(http.MethodPost, body, sign(body)))
+	assert.Len(t, logged, 1)
+	assert.EqualError(t, logged[0], "approval failed")
+}
+
+func TestWebhookReceiver_SecretRotation(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`[{"type":"test"}]`)
+	now := time.Now()
+	oldSignature := SignWebhookPayload(body, "old", now)
+	newSignature := SignWebhookPayload(body, "new", now)
+	assert.NoError(t, VerifyWebhookSignature(oldSignature, body, "old", "new"))
+	assert.NoError(t, VerifyWebhookSignature(newSignature, body, "old", "new"))
+	assert.ErrorIs(t, VerifyWebhookSignature(newSignature, body), ErrInvalidWebhookSignature)
+
+	receiver := NewWebhookReceiver("old")
+	deliver := func(signature string) int {
+		r := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		r.Header.Set(WebhookSignatureHeader, signature)
+		w := httptest.NewRecorder()
+		receiver.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, deliver(oldSignature))
+	assert.Equal(t, http.StatusUnauthorized, deliver(newSignature))
+
+	receiver.SetSecrets("old", "new")
+	assert.Equal(t, http.StatusOK, deliver(oldSignature))
+	assert.Equal(t, http.StatusOK, deliver(newSignature))
+
+	receiver.SetSecrets("new")
+	assert.Equal(t, http.StatusUnauthorized, deliver(oldSignature))
+	assert.Equal(t, http.StatusOK, deliver(newSignature))
+}
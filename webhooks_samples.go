@@ -0,0 +1,151 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignWebhookPayload returns the value of the [WebhookSignatureHeader] for body signed with secret
+// at time t, as the Tailscale API signs webhook deliveries.
+func SignWebhookPayload(body []byte, secret string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(signWebhook(timestamp, body, secret))
+}
+
+// sampleWebhookTailnet is the tailnet of sample webhook events.
+const sampleWebhookTailnet = "example.com"
+
+// SampleWebhookEvent returns a realistic sample event of the given type, timestamped now, for
+// developing and testing webhook receivers. eventType must be a known event type or
+// [WebhookTestEvent], not a category.
+func SampleWebhookEvent(eventType WebhookSubscriptionType) (WebhookEvent, error) {
+	event := WebhookEvent{
+		Timestamp: time.Now().UTC().Truncate(time.Millisecond),
+		Version:   1,
+		Type:      eventType,
+		Tailnet:   sampleWebhookTailnet,
+	}
+
+	const (
+		nodeURL = "https://login.tailscale.com/admin/machines/100.101.102.103"
+		userURL = "https://login.tailscale.com/admin/users"
+	)
+	node := WebhookNodeEventData{
+		NodeID:     "nAbCdEf1CNTRL",
+		DeviceName: "laptop.tail1234.ts.net",
+		ManagedBy:  "amelie@example.com",
+		Actor:      "amelie@example.com",
+		URL:        nodeURL,
+	}
+	user := WebhookUserEventData{User: "bob@example.com", Actor: "amelie@example.com", URL: userURL}
+
+	var data any
+	switch eventType {
+	case WebhookTestEvent:
+		event.Message = "This is a test event"
+	case WebhookNodeCreated:
+		event.Message = "Node laptop.tail1234.ts.net created"
+		data = node
+	case WebhookNodeNeedsApproval:
+		event.Message = "Node laptop.tail1234.ts.net needs approval"
+		data = node
+	case WebhookNodeApproved:
+		event.Message = "Node laptop.tail1234.ts.net approved"
+		data = node
+	case WebhookNodeKeyExpiringInOneDay:
+		event.Message = "Node laptop.tail1234.ts.net key is expiring in one day"
+		node.Actor = ""
+		node.Expiration = PointerTo(event.Timestamp.Add(24 * time.Hour))
+		data = node
+	case WebhookNodeKeyExpired:
+		event.Message = "Node laptop.tail1234.ts.net key has expired"
+		node.Actor = ""
+		node.Expiration = PointerTo(event.Timestamp)
+		data = node
+	case WebhookNodeDeleted:
+		event.Message = "Node laptop.tail1234.ts.net deleted"
+		data = node
+	case WebhookNodeNeedsSignature:
+		event.Message = "Node laptop.tail1234.ts.net needs a tailnet lock signature"
+		node.Actor = ""
+		data = node
+	case WebhookNodeSigned:
+		event.Message = "Node laptop.tail1234.ts.net signed"
+		data = node
+	case WebhookSubnetIPForwardingNotEnabled:
+		event.Message = "Subnet router laptop.tail1234.ts.net does not have IP forwarding enabled"
+		node.Actor = ""
+		data = node
+	case WebhookExitNodeIPForwardingNotEnabled:
+		event.Message = "Exit node laptop.tail1234.ts.net does not have IP forwarding enabled"
+		node.Actor = ""
+		data = node
+	case WebhookPolicyUpdate:
+		event.Message = "Tailnet policy file updated"
+		data = WebhookPolicyUpdateEventData{
+			Actor:     "amelie@example.com",
+			URL:       "https://login.tailscale.com/admin/acls",
+			OldPolicy: `{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`,
+			NewPolicy: `{"acls": [{"action": "accept", "src": ["autogroup:member"], "dst": ["autogroup:self:*"]}]}`,
+		}
+	case WebhookUserCreated:
+		event.Message = "User bob@example.com created"
+		data = user
+	case WebhookUserNeedsApproval:
+		event.Message = "User bob@example.com needs approval"
+		user.Actor = ""
+		data = user
+	case WebhookUserSuspended:
+		event.Message = "User bob@example.com suspended"
+		data = user
+	case WebhookUserRestored:
+		event.Message = "User bob@example.com restored"
+		data = user
+	case WebhookUserDeleted:
+		event.Message = "User bob@example.com deleted"
+		data = user
+	case WebhookUserApproved:
+		event.Message = "User bob@example.com approved"
+		data = user
+	case WebhookUserRoleUpdated:
+		event.Message = "User bob@example.com role updated"
+		user.OldRoles = []string{"member"}
+		user.NewRoles = []string{"admin"}
+		data = user
+	default:
+		return WebhookEvent{}, fmt.Errorf("no sample for webhook event type %q", eventType)
+	}
+
+	if data != nil {
+		var err error
+		if event.Data, err = json.Marshal(data); err != nil {
+			return WebhookEvent{}, err
+		}
+	}
+	return event, nil
+}
+
+// SampleWebhookDelivery returns the body of a webhook delivery with a sample event of each of the
+// given types, or of every known event type if none are given, along with its signature header
+// value for secret. See [SampleWebhookEvent].
+func SampleWebhookDelivery(secret string, eventTypes ...WebhookSubscriptionType) (body []byte, signature string, err error) {
+	if len(eventTypes) == 0 {
+		eventTypes = AllWebhookSubscriptions()
+	}
+	events := make([]WebhookEvent, len(eventTypes))
+	for i, eventType := range eventTypes {
+		if events[i], err = SampleWebhookEvent(eventType); err != nil {
+			return nil, "", err
+		}
+	}
+	if body, err = json.Marshal(events); err != nil {
+		return nil, "", err
+	}
+	return body, SignWebhookPayload(body, secret, time.Now()), nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	body, signature, err := SampleWebhookDelivery("secret")
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyWebhookSignature(signature, body, "secret"))
+
+	events, err := ParseWebhookEvents(body)
+	assert.NoError(t, err)
+	assert.Len(t, events, len(AllWebhookSubscriptions()))
+	for _, event := range events {
+		assert.NotEmpty(t, event.Message, event.Type)
+		switch {
+		case event.IsNodeEvent():
+			data, err := event.NodeData()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, data.NodeID)
+		case event.IsUserEvent():
+			data, err := event.UserData()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, data.User)
+		default:
+			data, err := event.PolicyData()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, data.NewPolicy)
+		}
+	}
+
+	// Samples are accepted by a receiver.
+	receiver := NewWebhookReceiver("secret")
+	var received []WebhookSubscriptionType
+	receiver.OnTest(func(_ context.Context, event WebhookEvent) error {
+		received = append(received, event.Type)
+		return nil
+	})
+	body, signature, err = SampleWebhookDelivery("secret", WebhookTestEvent)
+	assert.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(WebhookSignatureHeader, signature)
+	w := httptest.NewRecorder()
+	receiver.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []WebhookSubscriptionType{WebhookTestEvent}, received)
+
+	_, _, err = SampleWebhookDelivery("secret", WebhookCategoryTailnetManagement)
+	assert.EqualError(t, err, `no sample for webhook event type "categoryTailnetManagement"`)
+}
@@ -6,6 +6,7 @@ package tailscale
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -131,6 +132,34 @@ func TestClient_UpdateWebhook(t *testing.T) {
 	assert.Equal(t, expectedWebhook, actualWebhook)
 }
 
+func TestClient_ModifyWebhook(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusOK
+	expectedWebhook := &Webhook{
+		EndpointID:   "54321",
+		EndpointURL:  "https://example.com/my/new/endpoint",
+		ProviderType: WebhookMattermostProviderType,
+	}
+	server.ResponseBody = expectedWebhook
+
+	actualWebhook, err := client.Webhooks().Modify(context.Background(), "54321", UpdateWebhookRequest{
+		EndpointURL:  PointerTo("https://example.com/my/new/endpoint"),
+		ProviderType: PointerTo(WebhookMattermostProviderType),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPatch, server.Method)
+	assert.Equal(t, "/api/v2/webhooks/54321", server.Path)
+	assert.JSONEq(t, `{"endpointUrl": "https://example.com/my/new/endpoint", "providerType": "mattermost"}`, server.Body.String())
+	assert.Equal(t, expectedWebhook, actualWebhook)
+
+	// Subscriptions can be cleared with an empty, non-nil slice.
+	_, err = client.Webhooks().Modify(context.Background(), "54321", UpdateWebhookRequest{Subscriptions: []WebhookSubscriptionType{}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"subscriptions": []}`, server.Body.String())
+}
+
 func TestClient_DeleteWebhook(t *testing.T) {
 	t.Parallel()
 
@@ -180,3 +209,99 @@ func TestClient_RotateWebhookSecret(t *testing.T) {
 	assert.Equal(t, "/api/v2/webhooks/54321/rotate", server.Path)
 	assert.Equal(t, expectedWebhook, actualWebhook)
 }
+
+func TestWebhookSubscriptionType_Validate(t *testing.T) {
+	t.Parallel()
+
+	all := AllWebhookSubscriptions()
+	assert.Len(t, all, 18)
+	for _, sub := range all {
+		assert.NoError(t, sub.Validate())
+		assert.NotEmpty(t, sub.Category())
+	}
+	assert.NoError(t, WebhookCategoryTailnetManagement.Validate())
+	assert.Empty(t, WebhookCategoryTailnetManagement.Category())
+	assert.Equal(t, WebhookCategoryDeviceMisconfigurations, WebhookExitNodeIPForwardingNotEnabled.Category())
+	assert.EqualError(t, WebhookSubscriptionType("nodeExploded").Validate(), `unknown webhook subscription type "nodeExploded"`)
+
+	// Invalid subscriptions are rejected before a request is made.
+	client, server := NewTestHarness(t)
+	_, err := client.Webhooks().Create(context.Background(), CreateWebhookRequest{
+		EndpointURL:   "https://example.com/my/endpoint",
+		Subscriptions: []WebhookSubscriptionType{WebhookNodeCreated, "nodeExploded"},
+	})
+	assert.EqualError(t, err, `unknown webhook subscription type "nodeExploded"`)
+	_, err = client.Webhooks().Update(context.Background(), "12345", []WebhookSubscriptionType{"userExploded"})
+	assert.EqualError(t, err, `unknown webhook subscription type "userExploded"`)
+	assert.Empty(t, server.Method)
+}
+
+func TestCreateWebhookRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	req := CreateWebhookRequest{
+		EndpointURL:   "https://example.com/my/endpoint",
+		ProviderType:  WebhookGenericProviderType,
+		Subscriptions: []WebhookSubscriptionType{WebhookNodeCreated},
+	}
+	assert.NoError(t, req.Validate())
+
+	req = CreateWebhookRequest{EndpointURL: "example.com", ProviderType: "teams"}
+	assert.EqualError(t, req.Validate(), "webhook endpoint URL \"example.com\" is not an http or https URL\nunknown webhook provider type \"teams\"")
+
+	client, server := NewTestHarness(t)
+	_, err := client.Webhooks().Create(context.Background(), req)
+	assert.Error(t, err)
+	_, err = client.Webhooks().Modify(context.Background(), "12345", UpdateWebhookRequest{ProviderType: PointerTo(WebhookProviderType("teams"))})
+	assert.EqualError(t, err, `unknown webhook provider type "teams"`)
+	assert.Empty(t, server.Method)
+}
+
+func TestClient_CheckWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	receiver := NewWebhookReceiver("secret")
+	endpoint := httptest.NewServer(receiver)
+	t.Cleanup(endpoint.Close)
+
+	client, server := NewTestHarness(t)
+	server.ResponseCode = http.StatusAccepted
+	server.WebhookSecret = "secret"
+	server.WebhookEndpoints = map[string]string{"54321": endpoint.URL}
+
+	event, err := client.Webhooks().CheckDelivery(context.Background(), "54321", receiver, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookTestEvent, event.Type)
+	assert.Equal(t, "/api/v2/webhooks/54321/test", server.Path)
+
+	_, err = client.Webhooks().CheckDelivery(context.Background(), "12345", receiver, 50*time.Millisecond)
+	assert.EqualError(t, err, "test event for webhook 12345 was not delivered within 50ms")
+}
+
+func TestTestServer_DeliverWebhook(t *testing.T) {
+	t.Parallel()
+
+	receiver := NewWebhookReceiver("secret")
+	var created []string
+	receiver.OnNodeCreated(func(_ context.Context, _ WebhookEvent, data *WebhookNodeEventData) error {
+		created = append(created, data.NodeID)
+		return nil
+	})
+	endpoint := httptest.NewServer(receiver)
+	t.Cleanup(endpoint.Close)
+
+	_, server := NewTestHarness(t)
+	server.WebhookSecret = "secret"
+	event, err := SampleWebhookEvent(WebhookNodeCreated)
+	assert.NoError(t, err)
+	code, err := server.DeliverWebhook(endpoint.URL, event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, created, 1)
+
+	server.WebhookSecret = "wrong"
+	code, err = server.DeliverWebhook(endpoint.URL, event)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Len(t, created, 1)
+}